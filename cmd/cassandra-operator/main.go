@@ -4,20 +4,30 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"time"
 
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/jolokia"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/secrets"
+	"github.com/pantheon-systems/cassandra-operator/pkg/health"
+	"github.com/pantheon-systems/cassandra-operator/pkg/leader"
+	"github.com/pantheon-systems/cassandra-operator/pkg/metrics"
 
+	"github.com/operator-framework/operator-sdk/pkg/k8sclient"
 	opsdk "github.com/operator-framework/operator-sdk/pkg/sdk"
 	stub "github.com/pantheon-systems/cassandra-operator/pkg/stub"
 
 	sdkVersion "github.com/operator-framework/operator-sdk/version"
 	"github.com/pantheon-systems/cassandra-operator/version"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 )
 
@@ -25,6 +35,16 @@ const (
 	allNamespaces = ""
 	resource      = "database.pantheon.io/v1alpha1"
 	kind          = "CassandraCluster"
+	keyspaceKind  = "CassandraKeyspace"
+	roleKind      = "CassandraRole"
+
+	defaultMetricsPort = 60000
+	defaultLockName    = "cassandra-operator-lock"
+	// the leader is unhealthy when the renewals of the lock stop arriving through its watches for this long
+	watchTimeout = 4 * leader.DefaultLeaseDuration
+
+	nodeBackendNodetool = "nodetool"
+	nodeBackendJolokia  = "jolokia"
 )
 
 func main() {
@@ -43,6 +63,23 @@ func main() {
 	resyncPeriod := flag.Duration("resync", 20*time.Second, "Resync period")
 	debug := flag.Bool("debug", false, "debug level logging")
 	versionTaint := flag.String("version-taint", "", "sets and enables a version taint to run a private controller")
+	nodeBackend := flag.String("node-backend", nodeBackendNodetool, "backend used to query and manage cassandra nodes (nodetool or jolokia)")
+	nodetoolTimeout := flag.Duration("nodetool-timeout", nodetool.DefaultCommandTimeout, "timeout of the nodetool commands that query a node")
+	nodetoolDrainTimeout := flag.Duration("nodetool-drain-timeout", nodetool.DefaultDrainTimeout, "timeout of nodetool drain")
+	nodetoolDecommissionTimeout := flag.Duration("nodetool-decommission-timeout", nodetool.DefaultDecommissionTimeout, "timeout of nodetool decommission")
+	nodetoolCleanupTimeout := flag.Duration("nodetool-cleanup-timeout", nodetool.DefaultCleanupTimeout, "timeout of nodetool cleanup")
+	jolokiaPort := flag.Int("jolokia-port", jolokia.DefaultPort, "port of the jolokia agent in the cassandra pods")
+	metricsPort := flag.Int("metrics-port", defaultMetricsPort, "port the /metrics endpoint is served on")
+	vaultAddr := flag.String("vault-addr", "", "address of the vault that clusters with the vault secrets provider read from")
+	vaultRole := flag.String("vault-role", "cassandra-operator", "vault role the operator logs in as with the kubernetes auth method")
+	vaultAuthMount := flag.String("vault-auth-mount", secrets.DefaultVaultAuthMount, "mount of the vault kubernetes auth method")
+	vaultKVMount := flag.String("vault-kv-mount", secrets.DefaultVaultKVMount, "mount of the vault KV version 2 secrets engine")
+	workers := flag.Int("workers", 4, "number of clusters reconciled in parallel, the events of a cluster are processed one at a time")
+	leaderElect := flag.Bool("leader-elect", false, "elect a leader among the replicas of the operator, only the leader watches and reconciles")
+	leaderElectNamespace := flag.String("leader-elect-namespace", os.Getenv("POD_NAMESPACE"), "namespace of the leader lock configmap")
+	leaderElectName := flag.String("leader-elect-name", defaultLockName, "name of the leader lock configmap")
+	namespaces := flag.String("namespaces", os.Getenv("WATCH_NAMESPACE"), "comma separated namespaces to watch, all namespaces when empty")
+	clusterSelector := flag.String("cluster-selector", os.Getenv("CLUSTER_SELECTOR"), "label selector of the cassandra clusters to manage, all clusters when empty")
 	flag.Parse()
 
 	if versionTaint != nil && *versionTaint != "" {
@@ -65,18 +102,165 @@ func main() {
 		logrus.Debug("Logging level set to DEBUG")
 	}
 
+	status := health.NewStatus(watchTimeout)
+	go serveHTTP(*metricsPort, status)
+
 	kubeClient := k8s.NewOperatorSdkClient()
-	nodetoolClient := nodetool.NewExecutor(kubeClient)
-	handler := stub.NewHandler(kubeClient, nodetoolClient)
 
-	// Register primary watcher and handler for CassandraCluster CRD
-	logrus.Infof("Watching %s, %s, all namespaces, %d", resource, kind, *resyncPeriod)
-	opsdk.Watch(resource, kind, allNamespaces, *resyncPeriod)
-	opsdk.Watch("v1", "Pod", allNamespaces, 0, opsdk.WithLabelSelector("type=cassandra-node"))
+	var nodeManager nodetool.NodeManager
+	switch *nodeBackend {
+	case nodeBackendNodetool:
+		nodeManager = nodetool.NewExecutor(kubeClient,
+			nodetool.WithDefaultTimeout(*nodetoolTimeout),
+			nodetool.WithCommandTimeout("drain", *nodetoolDrainTimeout),
+			nodetool.WithCommandTimeout("decommission", *nodetoolDecommissionTimeout),
+			nodetool.WithCommandTimeout("cleanup", *nodetoolCleanupTimeout))
+	case nodeBackendJolokia:
+		nodeManager = jolokia.NewClient(jolokia.WithPort(*jolokiaPort))
+	default:
+		logrus.Fatalf("Unsupported node backend: %s", *nodeBackend)
+	}
+	logrus.Infof("Using %s backend to manage cassandra nodes", *nodeBackend)
+
+	var secretProvider secrets.SecretProvider
+	if *vaultAddr != "" {
+		secretProvider = secrets.NewVaultProvider(*vaultAddr, *vaultRole,
+			secrets.WithAuthMount(*vaultAuthMount), secrets.WithKVMount(*vaultKVMount))
+		logrus.Infof("Reading vault secrets from %s as %s", *vaultAddr, *vaultRole)
+	}
+
+	selector, err := labels.Parse(*clusterSelector)
+	if err != nil {
+		logrus.Fatalf("Invalid cluster selector %q: %v", *clusterSelector, err)
+	}
+	scope := watchScope{
+		namespaces:      splitNamespaces(*namespaces),
+		clusterSelector: selector.String(),
+	}
+
+	handler := stub.NewHandler(kubeClient, nodeManager, cql.NewConnector(), secretProvider, stub.WithClusterSelector(selector))
+
+	if !*leaderElect {
+		go handler.Run(ctx, *workers)
+		run(ctx, handler, *resyncPeriod, scope)
+		return
+	}
+
+	if *leaderElectNamespace == "" {
+		logrus.Fatalf("The namespace of the leader lock is not set, set POD_NAMESPACE or --leader-elect-namespace")
+	}
+	identity := os.Getenv("POD_NAME")
+	if identity == "" {
+		identity, _ = os.Hostname()
+	}
+
+	if !scope.watches(*leaderElectNamespace) {
+		// the renewals of the lock are watched even though the clusters of its namespace are not managed
+		scope.lockNamespace = *leaderElectNamespace
+	}
+
+	elector := leader.NewElector(k8sclient.GetKubeClient().CoreV1(), *leaderElectNamespace, *leaderElectName, identity)
+	// the renewals of the lock arrive through the configmap watch and show the watches of the leader are connected
+	events := status.WatchHandler(handler, *leaderElectNamespace, *leaderElectName)
+	err = elector.Run(ctx, func(leaderCtx context.Context) {
+		status.SetLeading(true)
+		go handler.Run(leaderCtx, *workers)
+		run(leaderCtx, events, *resyncPeriod, scope)
+	})
+	status.SetLeading(false)
+	if err != nil && ctx.Err() == nil {
+		// the watches of the sdk can not be restarted, a new process competes for the lock again
+		logrus.Errorf("Stopped leading as %s, exiting: %v", identity, err)
+		os.Exit(1)
+	}
+}
+
+// watchScope is the set of namespaces and clusters the operator watches
+type watchScope struct {
+	// namespaces to watch, all namespaces when empty
+	namespaces []string
+	// clusterSelector is the label selector of the cassandra clusters to watch
+	clusterSelector string
+	// lockNamespace is the namespace of the leader lock when it is not one of the watched namespaces
+	lockNamespace string
+}
+
+// watches returns true when the namespace is watched
+func (s watchScope) watches(namespace string) bool {
+	if len(s.namespaces) == 0 {
+		return true
+	}
+	for _, ns := range s.namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// splitNamespaces splits the comma separated list of namespaces, an empty list watches all namespaces
+func splitNamespaces(namespaces string) []string {
+	var split []string
+	for _, ns := range strings.Split(namespaces, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns != "" {
+			split = append(split, ns)
+		}
+	}
+	return split
+}
+
+// run watches the resources of the operator and handles their events until the context is done
+func run(ctx context.Context, handler opsdk.Handler, resyncPeriod time.Duration, scope watchScope) {
+	namespaces := scope.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{allNamespaces}
+	}
+
+	var clusterOpts []opsdk.WatchOption
+	if scope.clusterSelector != "" {
+		clusterOpts = append(clusterOpts, opsdk.WithLabelSelector(scope.clusterSelector))
+	}
+
+	for _, namespace := range namespaces {
+		name := namespace
+		if name == allNamespaces {
+			name = "all namespaces"
+		}
+
+		// Register primary watcher and handler for CassandraCluster CRD
+		logrus.Infof("Watching %s, %s, %s, %d", resource, kind, name, resyncPeriod)
+		opsdk.Watch(resource, kind, namespace, resyncPeriod, clusterOpts...)
+		logrus.Infof("Watching %s, %s, %s, %d", resource, keyspaceKind, name, resyncPeriod)
+		opsdk.Watch(resource, keyspaceKind, namespace, resyncPeriod)
+		logrus.Infof("Watching %s, %s, %s, %d", resource, roleKind, name, resyncPeriod)
+		opsdk.Watch(resource, roleKind, namespace, resyncPeriod)
+		opsdk.Watch("v1", "Pod", namespace, 0, opsdk.WithLabelSelector("type=cassandra-node"))
+		// clusters referencing a secret or configmap that is missing are validated again when it changes
+		opsdk.Watch("v1", "Secret", namespace, 0)
+		opsdk.Watch("v1", "ConfigMap", namespace, 0)
+	}
+	if scope.lockNamespace != "" {
+		opsdk.Watch("v1", "ConfigMap", scope.lockNamespace, 0)
+	}
 	opsdk.Handle(handler)
 	opsdk.Run(ctx)
 }
 
+// serveHTTP serves the metrics and the health endpoints of the operator
+func serveHTTP(port int, status *health.Status) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/healthz", status.HealthzHandler())
+	mux.Handle("/readyz", status.ReadyzHandler())
+
+	logrus.Infof("Serving metrics and health endpoints on port %d", port)
+	err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux)
+	if err != nil {
+		logrus.Fatalf("Could not serve metrics: %v", err)
+	}
+}
+
 func printVersion() {
 	logrus.Infof("Go Version: %s", runtime.Version())
 	logrus.Infof("Go OS/Arch: %s/%s", runtime.GOOS, runtime.GOARCH)
@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CassandraKeyspaceList Lists of CassandraKeyspaces
+type CassandraKeyspaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CassandraKeyspace `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CassandraKeyspace CassandraKeyspace api representation
+type CassandraKeyspace struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              KeyspaceSpec   `json:"spec"`
+	Status            KeyspaceStatus `json:"status"`
+}
+
+// KeyspaceSpec Specification for a keyspace in a cassandra cluster
+type KeyspaceSpec struct {
+	// Cluster is the name of the CassandraCluster in the same namespace
+	Cluster string `json:"cluster"`
+	// KeyspaceName defaults to the name of the resource
+	KeyspaceName  string         `json:"keyspaceName,omitempty"`
+	Replication   map[string]int `json:"replication"`
+	DurableWrites *bool          `json:"durableWrites,omitempty"`
+}
+
+// KeyspacePhase type alias for the string representing the phase of a keyspace
+type KeyspacePhase string
+
+// KeyspacePhases enumerated
+const (
+	// KeyspacePhasePending the cluster is not running yet
+	KeyspacePhasePending KeyspacePhase = "Pending"
+	// KeyspacePhaseReady the keyspace matches the spec
+	KeyspacePhaseReady KeyspacePhase = "Ready"
+	// KeyspacePhaseRejected the spec can not be applied, see the message
+	KeyspacePhaseRejected KeyspacePhase = "Rejected"
+)
+
+// KeyspaceStatus specifies the status of the keyspace
+type KeyspaceStatus struct {
+	Phase       KeyspacePhase  `json:"phase"`
+	Message     string         `json:"message,omitempty"`
+	Replication map[string]int `json:"replication,omitempty"`
+	// RepairJob is the name of the last job started to repair the keyspace after replication was increased
+	RepairJob string `json:"repairJob,omitempty"`
+	// PendingRepair identifies the replication increase whose repair has not been started yet, it is recorded
+	// before the keyspace is altered so the repair is not lost when starting it fails
+	PendingRepair string `json:"pendingRepair,omitempty"`
+}
+
+// GetKeyspaceName returns the name of the keyspace in cassandra
+func (k *CassandraKeyspace) GetKeyspaceName() string {
+	if k.Spec.KeyspaceName != "" {
+		return k.Spec.KeyspaceName
+	}
+	return k.GetName()
+}
+
+// GetDurableWrites returns if durable writes are enabled, they are by default
+func (k *CassandraKeyspace) GetDurableWrites() bool {
+	if k.Spec.DurableWrites == nil {
+		return true
+	}
+	return *k.Spec.DurableWrites
+}
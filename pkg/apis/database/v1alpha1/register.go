@@ -31,6 +31,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&CassandraCluster{},
 		&CassandraClusterList{},
+		&CassandraKeyspace{},
+		&CassandraKeyspaceList{},
+		&CassandraRole{},
+		&CassandraRoleList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
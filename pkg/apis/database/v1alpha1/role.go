@@ -0,0 +1,89 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RotatePasswordAnnotation requests a new password for a CassandraRole, it is removed once the password is rotated
+const RotatePasswordAnnotation = "database.panth.io/rotate-password"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CassandraRoleList Lists of CassandraRoles
+type CassandraRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CassandraRole `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CassandraRole CassandraRole api representation
+type CassandraRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              RoleSpec   `json:"spec"`
+	Status            RoleStatus `json:"status"`
+}
+
+// RoleSpec Specification for a role in a cassandra cluster
+type RoleSpec struct {
+	// Cluster is the name of the CassandraCluster in the same namespace
+	Cluster string `json:"cluster"`
+	// RoleName defaults to the name of the resource
+	RoleName  string `json:"roleName,omitempty"`
+	Login     *bool  `json:"login,omitempty"`
+	Superuser bool   `json:"superuser,omitempty"`
+	// SecretName is the secret the generated password is stored in, defaults to `<name>-credentials`
+	SecretName  string                `json:"secretName,omitempty"`
+	Permissions []KeyspacePermissions `json:"permissions,omitempty"`
+}
+
+// KeyspacePermissions are the permissions granted to a role on a keyspace
+type KeyspacePermissions struct {
+	Keyspace    string   `json:"keyspace"`
+	Permissions []string `json:"permissions"`
+}
+
+// RolePhase type alias for the string representing the phase of a role
+type RolePhase string
+
+// RolePhases enumerated
+const (
+	// RolePhasePending the cluster is not running or does not have authentication enabled
+	RolePhasePending RolePhase = "Pending"
+	// RolePhaseReady the role and its permissions match the spec
+	RolePhaseReady RolePhase = "Ready"
+	// RolePhaseRejected the spec can not be applied, see the message
+	RolePhaseRejected RolePhase = "Rejected"
+)
+
+// RoleStatus specifies the status of the role
+type RoleStatus struct {
+	Phase   RolePhase `json:"phase"`
+	Message string    `json:"message,omitempty"`
+}
+
+// GetRoleName returns the name of the role in cassandra
+func (r *CassandraRole) GetRoleName() string {
+	if r.Spec.RoleName != "" {
+		return r.Spec.RoleName
+	}
+	return r.GetName()
+}
+
+// GetSecretName returns the name of the secret holding the credentials of the role
+func (r *CassandraRole) GetSecretName() string {
+	if r.Spec.SecretName != "" {
+		return r.Spec.SecretName
+	}
+	return r.GetName() + "-credentials"
+}
+
+// GetLogin returns if the role can log in, roles can log in by default
+func (r *CassandraRole) GetLogin() bool {
+	if r.Spec.Login == nil {
+		return true
+	}
+	return *r.Spec.Login
+}
@@ -1,5 +1,10 @@
 package v1alpha1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // ClusterPhase type alias for the string representing the phase
 type ClusterPhase string
 
@@ -109,7 +114,92 @@ type ClusterStatus struct {
 	Phase          ClusterPhase `json:"phase"`
 	State          ClusterState `json:"state"`
 	Members        NodesStatus  `json:"members"`
+	Nodes          []NodeInfo   `json:"nodes,omitempty"`
 	CurrentVersion string       `json:"currentVersion"`
+	// Conditions report problems that keep the operator from provisioning the cluster
+	Conditions []ClusterCondition `json:"conditions,omitempty"`
+	// Cleanup tracks the nodetool cleanup of the nodes after the cluster scaled up
+	Cleanup *CleanupStatus `json:"cleanup,omitempty"`
+	// NodeMetricsObservedAt is when the uptime and heap usage of the nodes were last written
+	NodeMetricsObservedAt *metav1.Time `json:"nodeMetricsObservedAt,omitempty"`
+}
+
+// CleanupStatus tracks the nodetool cleanup that removes the data the nodes no longer own after nodes were added,
+// the nodes are cleaned up once the cluster is running and no node is streaming
+type CleanupStatus struct {
+	// Pending nodes still have to be cleaned up
+	Pending []string `json:"pending,omitempty"`
+	// Running nodes are being cleaned up
+	Running []string `json:"running,omitempty"`
+	// Completed nodes were cleaned up since the cluster last scaled up
+	Completed []string `json:"completed,omitempty"`
+	// LastError is the error of the last failed cleanup, the node is pending again
+	LastError string `json:"lastError,omitempty"`
+}
+
+// Done returns true when no node is pending or running a cleanup
+func (s *CleanupStatus) Done() bool {
+	return s == nil || len(s.Pending)+len(s.Running) == 0
+}
+
+// ClusterConditionType is the type of a condition of the cluster
+type ClusterConditionType string
+
+const (
+	// ClusterConditionSecretsValid is false when a secret the nodes mount is missing or lacks an expected key
+	ClusterConditionSecretsValid ClusterConditionType = "SecretsValid"
+	// ClusterConditionConfigMapsValid is false when a configmap of the cluster is missing or lacks an expected key
+	ClusterConditionConfigMapsValid ClusterConditionType = "ConfigMapsValid"
+	// ClusterConditionPaused is true while the reconciliation of the cluster is paused
+	ClusterConditionPaused ClusterConditionType = "Paused"
+)
+
+// ClusterCondition is the latest observation of an aspect of the cluster
+type ClusterCondition struct {
+	Type               ClusterConditionType   `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+}
+
+// NodeInfo reports the runtime details of a single ready cassandra node, a node that is bootstrapping only reports
+// its name and streams. The uptime and heap usage alone only update the status every few minutes, they are
+// refreshed along with any other change
+type NodeInfo struct {
+	Name            string `json:"name"`
+	HostID          string `json:"hostID"`
+	UptimeSeconds   int64  `json:"uptimeSeconds"`
+	HeapUsedMB      int64  `json:"heapUsedMB"`
+	HeapTotalMB     int64  `json:"heapTotalMB"`
+	HeapUsedPercent int32  `json:"heapUsedPercent"`
+	// Streams is the progress of the data the node streams while it joins or leaves the ring
+	Streams *StreamProgress `json:"streams,omitempty"`
+}
+
+// StreamProgress is the progress of the streaming sessions of a node
+type StreamProgress struct {
+	Sessions []StreamSessionStatus `json:"sessions"`
+	// BytesDone and BytesTotal are summed over all the sessions
+	BytesDone  int64 `json:"bytesDone"`
+	BytesTotal int64 `json:"bytesTotal"`
+	// ObservedAt is when the progress was read from the node
+	ObservedAt metav1.Time `json:"observedAt"`
+	// EstimatedCompletion extrapolates the bytes streamed since the previous observation, it is not set until the
+	// node streamed data between two observations
+	EstimatedCompletion *metav1.Time `json:"estimatedCompletion,omitempty"`
+}
+
+// StreamSessionStatus is the progress of the files a node sends to or receives from a peer
+type StreamSessionStatus struct {
+	// Operation of the streaming plan, like Bootstrap, Unbootstrap, Rebuild or Repair
+	Operation  string `json:"operation"`
+	Peer       string `json:"peer"`
+	Direction  string `json:"direction"`
+	FilesDone  int64  `json:"filesDone"`
+	FilesTotal int64  `json:"filesTotal"`
+	BytesDone  int64  `json:"bytesDone"`
+	BytesTotal int64  `json:"bytesTotal"`
 }
 
 // NodesStatus bins nodes by state
@@ -120,6 +210,8 @@ type NodesStatus struct {
 	Leaving  []string `json:"leaving,omitempty"`
 	Unready  []string `json:"unready,omitempty"`
 	Deleted  []string `json:"deleted,omitempty"`
+	// Streaming nodes are ready but still send or receive data, they are in one of the other bins as well
+	Streaming []string `json:"streaming,omitempty"`
 }
 
 // Provisioning returns true if the cluster has a node that is in process of provisioning
@@ -133,3 +225,32 @@ func (s *ClusterStatus) Provisioning() bool {
 func (s *ClusterStatus) NodesInTransit() bool {
 	return len(s.Members.Creating)+len(s.Members.Joining)+len(s.Members.Leaving) > 0
 }
+
+// GetCondition returns the condition of the type or nil if the cluster does not have it
+func (s *ClusterStatus) GetCondition(conditionType ClusterConditionType) *ClusterCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == conditionType {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or replaces the condition of the same type and returns true if anything but the transition
+// time changed, the transition time is only moved when the status of the condition changes
+func (s *ClusterStatus) SetCondition(condition ClusterCondition) bool {
+	current := s.GetCondition(condition.Type)
+	if current == nil {
+		s.Conditions = append(s.Conditions, condition)
+		return true
+	}
+
+	if current.Status == condition.Status {
+		condition.LastTransitionTime = current.LastTransitionTime
+	}
+	changed := current.Status != condition.Status || current.Reason != condition.Reason ||
+		current.Message != condition.Message
+	*current = condition
+
+	return changed
+}
@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -33,20 +34,150 @@ type CassandraCluster struct {
 
 // ClusterSpec Specification for cassandra cluster for API
 type ClusterSpec struct {
-	Size                      int              `json:"size"`
-	Repair                    *RepairPolicy    `json:"repair,omitempty"`
-	Node                      *NodePolicy      `json:"node"`
-	KeyspaceName              string           `json:"keyspaceName,omitempty"`
-	SecretName                string           `json:"secretName,omitempty"`
-	ConfigMapName             string           `json:"configMapName,omitempty"`
-	JvmAgentConfigName        string           `json:"jvmAgentConfigName,omitemtpy"`
-	JvmAgent                  string           `json:"jvmAgent,omitempty"`
-	Datacenter                string           `json:"datacenter"`
-	ExternalSeeds             []string         `json:"externalSeeds,omitempty"`
-	EnablePublicPodServices   bool             `json:"enablePublicPodServices"`
-	ExposePublicLB            bool             `json:"exposePublicLB"`
-	EnablePodDisruptionBudget bool             `json:"enablePodDisruptionBudget"`
-	Affinity                  *corev1.Affinity `json:"affinity,omitempty"`
+	Size                      int               `json:"size"`
+	Repair                    *RepairPolicy     `json:"repair,omitempty"`
+	Node                      *NodePolicy       `json:"node"`
+	KeyspaceName              string            `json:"keyspaceName,omitempty"`
+	SecretName                string            `json:"secretName,omitempty"`
+	ConfigMapName             string            `json:"configMapName,omitempty"`
+	JvmAgentConfigName        string            `json:"jvmAgentConfigName,omitemtpy"`
+	JvmAgent                  string            `json:"jvmAgent,omitempty"`
+	Datacenter                string            `json:"datacenter"`
+	ExternalSeeds             []string          `json:"externalSeeds,omitempty"`
+	EnablePublicPodServices   bool              `json:"enablePublicPodServices"`
+	ExposePublicLB            bool              `json:"exposePublicLB"`
+	EnablePodDisruptionBudget bool              `json:"enablePodDisruptionBudget"`
+	Affinity                  *corev1.Affinity  `json:"affinity,omitempty"`
+	Auth                      *AuthPolicy       `json:"auth,omitempty"`
+	Replication               map[string]int    `json:"replication,omitempty"`
+	TLS                       *TLSPolicy        `json:"tls,omitempty"`
+	Secrets                   *SecretsPolicy    `json:"secrets,omitempty"`
+	Monitoring                *MonitoringPolicy `json:"monitoring,omitempty"`
+	// Paused stops the operator from reconciling the cluster during manual maintenance, the status is still
+	// reported, pods are deleted without draining their nodes and the repair cron job is suspended
+	Paused bool `json:"paused,omitempty"`
+	// MaxConcurrentCleanups is how many nodes run nodetool cleanup at the same time after the cluster scaled up,
+	// defaults to 1
+	MaxConcurrentCleanups int `json:"maxConcurrentCleanups,omitempty"`
+	// DisruptionBudget sets how many nodes may be evicted at the same time when `enablePodDisruptionBudget` is set
+	DisruptionBudget *DisruptionBudgetPolicy `json:"disruptionBudget,omitempty"`
+	// ServiceTemplate adds labels and annotations to the services of the cluster
+	ServiceTemplate *ServiceTemplate `json:"serviceTemplate,omitempty"`
+	// Exposure sets how clients and the nodes of other datacenters reach the cluster
+	Exposure *ExposurePolicy `json:"exposure,omitempty"`
+}
+
+// ExposureType is the type of the services that expose the cluster
+type ExposureType string
+
+const (
+	// ExposureLoadBalancer exposes the cluster through load balancers
+	ExposureLoadBalancer ExposureType = "LoadBalancer"
+	// ExposureNodePort exposes the cluster through a port on every kubernetes node
+	ExposureNodePort ExposureType = "NodePort"
+	// ExposureNone does not expose the cluster, the services are removed
+	ExposureNone ExposureType = "None"
+)
+
+// ExposurePolicy sets the services of the client and the internode traffic
+type ExposurePolicy struct {
+	// Client is the public service of the cluster, it defaults to a load balancer of the cql and thrift ports
+	Client *ServiceExposure `json:"client,omitempty"`
+	// Internode is the public service of every node, it defaults to a load balancer of the ssl internode port
+	// when `enablePublicPodServices` is set and to none otherwise
+	Internode *ServiceExposure `json:"internode,omitempty"`
+}
+
+// ServiceExposure sets the type, the ports and the load balancers of services
+type ServiceExposure struct {
+	// Type defaults to LoadBalancer
+	Type  ExposureType         `json:"type,omitempty"`
+	Ports []corev1.ServicePort `json:"ports,omitempty"`
+	// SourceRanges restricts the addresses load balancers accept connections from
+	SourceRanges []string `json:"sourceRanges,omitempty"`
+	// Annotations are added to the services, like the annotations that pick the class of the load balancers of a
+	// cloud provider
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ServiceTemplate holds the labels and annotations of the services, like the annotations that make a cloud provider
+// create an internal load balancer. The labels the operator sets on the services can not be overridden.
+type ServiceTemplate struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// DisruptionBudgetPolicy sets the PodDisruptionBudget of the nodes, only one of MinAvailable and MaxUnavailable can
+// be set. When neither is set, as many nodes may be unavailable as leave a quorum of every token range, which is
+// at least one node.
+type DisruptionBudgetPolicy struct {
+	// MinAvailable is a number of nodes or a percentage of the size of the cluster that must stay available
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// MaxUnavailable is a number of nodes or a percentage of the size of the cluster that may be evicted
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// AuthPolicy sets the authentication of the cluster and the credentials the operator uses for CQL management operations
+type AuthPolicy struct {
+	// Enabled switches on the PasswordAuthenticator and CassandraAuthorizer and replaces the default superuser
+	Enabled bool `json:"enabled,omitempty"`
+	// SecretName is the name of a secret with the `username` and `password` keys, when authentication is enabled
+	// and it is not set a superuser is generated into the `<cluster>-cassandra-superuser` secret
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// TLSPolicy sets how the certificates for internode and client encryption are issued
+type TLSPolicy struct {
+	// Managed makes the operator issue a cluster CA and the node keystores into the certs secret, internode
+	// traffic is encrypted whenever the certificates are managed
+	Managed bool `json:"managed,omitempty"`
+	// ClientEncryption requires TLS on the native transport as well
+	ClientEncryption bool `json:"clientEncryption,omitempty"`
+	// ValidityDays is how long node certificates are valid for, defaults to 365
+	ValidityDays int `json:"validityDays,omitempty"`
+	// RenewBeforeDays is how long before expiry node certificates are reissued, defaults to 30
+	RenewBeforeDays int `json:"renewBeforeDays,omitempty"`
+}
+
+// Secret providers that can be set in the SecretsPolicy
+const (
+	SecretsProviderKubernetes = "kubernetes"
+	SecretsProviderVault      = "vault"
+)
+
+// SecretsPolicy sets where the keystore of the nodes and the credentials of the operator are read from, secrets
+// read from vault are copied into secrets owned by the cluster
+type SecretsPolicy struct {
+	// Provider is either `kubernetes` (default) or `vault`
+	Provider string `json:"provider,omitempty"`
+	// KeystorePath is the path in the vault KV engine with the base64 encoded keystore files, it is ignored when
+	// TLS is managed
+	KeystorePath string `json:"keystorePath,omitempty"`
+	// CredentialsPath is the path in the vault KV engine with the `username` and `password` the operator logs in with
+	CredentialsPath string `json:"credentialsPath,omitempty"`
+}
+
+// Metrics exporters that can be set in the MonitoringPolicy
+const (
+	ExporterTelegraf          = "telegraf"
+	ExporterCassandraExporter = "cassandra-exporter"
+	ExporterJMX               = "jmx-exporter"
+)
+
+// MonitoringPolicy sets the exporter that serves the metrics of the nodes to prometheus, the exporter reads its
+// configuration from the configmap named in `jvmAgentConfigName`
+type MonitoringPolicy struct {
+	// Exporter is `telegraf` (default) or `cassandra-exporter` which run as a sidecar, or `jmx-exporter` which is
+	// loaded into the cassandra JVM as a javaagent
+	Exporter string `json:"exporter,omitempty"`
+	// Image of the exporter sidecar, for the jmx-exporter it is an image the javaagent is copied from
+	Image string `json:"image,omitempty"`
+	// Resources of the exporter sidecar
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Port the metrics are served on
+	Port int32 `json:"port,omitempty"`
+	// ServiceMonitor creates a prometheus-operator ServiceMonitor for the cluster when its CRD is installed
+	ServiceMonitor bool `json:"serviceMonitor,omitempty"`
 }
 
 // RepairPolicy sets the policies for the automated cassandra repair job
@@ -0,0 +1,5 @@
+// +k8s:deepcopy-gen=package
+// +groupName=monitoring.coreos.com
+
+// Package v1 holds the subset of the prometheus-operator API the operator creates
+package v1
@@ -0,0 +1,33 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceMonitor makes the prometheus-operator scrape the endpoints of the services it selects
+type ServiceMonitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ServiceMonitorSpec `json:"spec"`
+}
+
+// ServiceMonitorSpec selects the services and the ports of their endpoints that are scraped
+type ServiceMonitorSpec struct {
+	Selector          metav1.LabelSelector `json:"selector"`
+	NamespaceSelector NamespaceSelector    `json:"namespaceSelector,omitempty"`
+	Endpoints         []Endpoint           `json:"endpoints"`
+}
+
+// NamespaceSelector selects the namespaces the services are looked up in
+type NamespaceSelector struct {
+	MatchNames []string `json:"matchNames,omitempty"`
+}
+
+// Endpoint is a port of the selected services that is scraped
+type Endpoint struct {
+	Port     string `json:"port,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Interval string `json:"interval,omitempty"`
+}
@@ -0,0 +1,159 @@
+package cql
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultSessionTTL is how long a cached session is handed out before a new one replaces it
+const DefaultSessionTTL = 10 * time.Minute
+
+// SessionCache is a Connector that keeps the sessions it opened so the reconciles of a cluster share one session
+// instead of opening a new one every time. A session is shared for the same hosts and username as long as the
+// password and the options are the same and it is younger than the ttl, a replaced session is closed once the
+// last caller closed it.
+type SessionCache struct {
+	connector Connector
+	ttl       time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*cachedSession
+}
+
+// cachedSession is a session shared by the callers holding a handle to it
+type cachedSession struct {
+	manager     Manager
+	fingerprint string
+	created     time.Time
+	refs        int
+	retired     bool
+}
+
+// SessionCacheOption is a function that sets the configuration on the SessionCache
+type SessionCacheOption func(*SessionCache)
+
+// WithSessionTTL sets how long a session is shared before a new one replaces it
+func WithSessionTTL(ttl time.Duration) SessionCacheOption {
+	return func(c *SessionCache) {
+		c.ttl = ttl
+	}
+}
+
+// NewSessionCache creates a new SessionCache that opens the sessions with the connector
+func NewSessionCache(connector Connector, opts ...SessionCacheOption) *SessionCache {
+	c := &SessionCache{
+		connector: connector,
+		ttl:       DefaultSessionTTL,
+		sessions:  map[string]*cachedSession{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Connect returns the cached session of the hosts and credentials or opens a new one, closing the returned
+// session releases it. The cache is not locked while a session is opened so other clusters are not held up.
+func (c *SessionCache) Connect(hosts []string, credentials *Credentials, opts ...ConnectOption) (Manager, error) {
+	key, fingerprint := sessionKey(hosts, credentials, opts)
+
+	c.mu.Lock()
+	session, ok := c.sessions[key]
+	if ok && session.fingerprint == fingerprint && time.Since(session.created) < c.ttl {
+		session.refs++
+		c.mu.Unlock()
+		return &sessionHandle{Manager: session.manager, cache: c, session: session}, nil
+	}
+	c.mu.Unlock()
+
+	manager, err := c.connector.Connect(hosts, credentials, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.sessions[key]; ok {
+		c.retire(key)
+	}
+	session = &cachedSession{manager: manager, fingerprint: fingerprint, created: time.Now(), refs: 1}
+	c.sessions[key] = session
+	return &sessionHandle{Manager: session.manager, cache: c, session: session}, nil
+}
+
+// Close closes the sessions to the hosts, the sessions in use are closed once they are released
+func (c *SessionCache) Close(hosts []string) {
+	prefix := strings.Join(hosts, ",") + "/"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.sessions {
+		if strings.HasPrefix(key, prefix) {
+			c.retire(key)
+		}
+	}
+}
+
+// retire removes the session from the cache and closes it unless it is in use
+func (c *SessionCache) retire(key string) {
+	session := c.sessions[key]
+	delete(c.sessions, key)
+
+	session.retired = true
+	if session.refs == 0 {
+		logrus.Debugf("Closing CQL session %s", key)
+		session.manager.Close()
+	}
+}
+
+// release closes a retired session once its last handle is closed
+func (c *SessionCache) release(session *cachedSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	session.refs--
+	if session.retired && session.refs == 0 {
+		session.manager.Close()
+	}
+}
+
+// sessionKey returns the key a session is cached under and the fingerprint of the secrets and options it was
+// opened with, a session with another fingerprint is replaced
+func sessionKey(hosts []string, credentials *Credentials, opts []ConnectOption) (string, string) {
+	op := &connectOptions{}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	username, password := "", ""
+	if credentials != nil {
+		username, password = credentials.Username, credentials.Password
+	}
+
+	key := fmt.Sprintf("%s/%s", strings.Join(hosts, ","), username)
+	fingerprint := strings.Join([]string{password, string(op.caCertificate), op.serverName}, "\x00")
+	return key, fingerprint
+}
+
+// sessionHandle is the session handed out by the cache, closing it releases the session
+type sessionHandle struct {
+	Manager
+	cache   *SessionCache
+	session *cachedSession
+	once    sync.Once
+}
+
+// Close releases the session, it is closed by the cache
+func (h *sessionHandle) Close() {
+	h.once.Do(func() {
+		h.cache.release(h.session)
+	})
+}
@@ -0,0 +1,101 @@
+package cql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/stretchr/testify/assert"
+)
+
+// sessionCounter opens mock sessions and counts the sessions it opened and closed
+type sessionCounter struct {
+	opened int
+	closed int
+	err    error
+}
+
+func (c *sessionCounter) connector() *cql.MockConnector {
+	return &cql.MockConnector{
+		ConnectCallback: func(hosts []string, credentials *cql.Credentials) (cql.Manager, error) {
+			if c.err != nil {
+				return nil, c.err
+			}
+			c.opened++
+			return &cql.MockManager{CloseCallback: func() { c.closed++ }}, nil
+		},
+	}
+}
+
+func TestSessionCache_SharesSessions(t *testing.T) {
+	counter := &sessionCounter{}
+	cache := cql.NewSessionCache(counter.connector())
+	hosts := []string{"test-cluster-cassandra.default.svc.cluster.local"}
+	admin := &cql.Credentials{Username: "admin", Password: "secret"}
+
+	for i := 0; i < 3; i++ {
+		session, err := cache.Connect(hosts, admin)
+		assert.NoError(t, err)
+		session.Close()
+		session.Close()
+	}
+	assert.Equal(t, 1, counter.opened)
+	assert.Equal(t, 0, counter.closed)
+
+	// another user gets its own session, a new password or certificate replaces the session
+	session, err := cache.Connect(hosts, cql.DefaultCredentials)
+	assert.NoError(t, err)
+	session.Close()
+	session, err = cache.Connect(hosts, &cql.Credentials{Username: "admin", Password: "rotated"})
+	assert.NoError(t, err)
+	session.Close()
+	session, err = cache.Connect(hosts, &cql.Credentials{Username: "admin", Password: "rotated"}, cql.WithTLS([]byte("ca"), hosts[0]))
+	assert.NoError(t, err)
+	session.Close()
+	assert.Equal(t, 4, counter.opened)
+	assert.Equal(t, 2, counter.closed)
+
+	// the sessions of a deleted cluster are closed, the sessions of other clusters are kept
+	other, err := cache.Connect([]string{"other-cluster-cassandra.default.svc.cluster.local"}, admin)
+	assert.NoError(t, err)
+	other.Close()
+	cache.Close(hosts)
+	assert.Equal(t, 4, counter.closed)
+}
+
+func TestSessionCache_ClosesReplacedSessionOnceReleased(t *testing.T) {
+	counter := &sessionCounter{}
+	cache := cql.NewSessionCache(counter.connector(), cql.WithSessionTTL(0))
+	hosts := []string{"test-cluster-cassandra.default.svc.cluster.local"}
+
+	first, err := cache.Connect(hosts, nil)
+	assert.NoError(t, err)
+	// the session expired, it is replaced but still in use
+	second, err := cache.Connect(hosts, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, counter.opened)
+	assert.Equal(t, 0, counter.closed)
+
+	first.Close()
+	assert.Equal(t, 1, counter.closed)
+
+	cache.Close(hosts)
+	assert.Equal(t, 1, counter.closed)
+	second.Close()
+	assert.Equal(t, 2, counter.closed)
+}
+
+func TestSessionCache_DoesNotCacheFailures(t *testing.T) {
+	counter := &sessionCounter{err: errors.New("no hosts available")}
+	cache := cql.NewSessionCache(counter.connector())
+	hosts := []string{"test-cluster-cassandra.default.svc.cluster.local"}
+
+	_, err := cache.Connect(hosts, nil)
+	assert.EqualError(t, err, "no hosts available")
+
+	counter.err = nil
+	session, err := cache.Connect(hosts, nil)
+	assert.NoError(t, err)
+	session.Close()
+	assert.Equal(t, 1, counter.opened)
+}
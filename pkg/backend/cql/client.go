@@ -0,0 +1,147 @@
+package cql
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultPort is the CQL native transport port
+	DefaultPort = 9042
+
+	defaultTimeout        = 10 * time.Second
+	defaultConnectTimeout = 10 * time.Second
+)
+
+// Manager is the contract for the management operations the operator runs over CQL
+type Manager interface {
+	// EnsureKeyspace creates the keyspace if it does not exist and alters it if replication or durable writes have drifted
+	EnsureKeyspace(keyspace string, replication map[string]int, durableWrites bool) error
+	// GetKeyspace returns the current replication and durable writes of the keyspace, nil if it does not exist
+	GetKeyspace(keyspace string) (*Keyspace, error)
+	// GetRole returns the role, nil if it does not exist
+	GetRole(name string) (*Role, error)
+	// CreateRole creates the role if it does not exist
+	CreateRole(role Role, password string) error
+	// AlterRole sets the flags of the role and the password when it is not empty
+	AlterRole(role Role, password string) error
+	// DropRole drops the role if it exists
+	DropRole(name string) error
+	// GetPermissions returns the permissions granted to the role on keyspaces
+	GetPermissions(name string) ([]Grant, error)
+	// Grant grants a permission on a keyspace to the role
+	Grant(name string, grant Grant) error
+	// Revoke revokes a permission on a keyspace from the role
+	Revoke(name string, grant Grant) error
+	// Local returns the node the session is connected to from system.local
+	Local() (*Node, error)
+	// Peers returns the other nodes in the ring from system.peers
+	Peers() ([]Node, error)
+	// Close closes the session
+	Close()
+}
+
+// Connector opens management sessions to a cassandra cluster
+type Connector interface {
+	Connect(hosts []string, credentials *Credentials, opts ...ConnectOption) (Manager, error)
+}
+
+type connectOptions struct {
+	caCertificate []byte
+	serverName    string
+}
+
+// ConnectOption is a function that sets optional configuration on a session
+type ConnectOption func(*connectOptions)
+
+// WithTLS encrypts the session and only trusts nodes presenting a certificate signed by the PEM encoded CA
+// for the server name, the nodes are dialed by IP so the name is verified instead of the address
+func WithTLS(caCertificate []byte, serverName string) ConnectOption {
+	return func(op *connectOptions) {
+		op.caCertificate = caCertificate
+		op.serverName = serverName
+	}
+}
+
+// GocqlConnector connects to cassandra using the gocql driver
+type GocqlConnector struct {
+	Port    int
+	Timeout time.Duration
+}
+
+// NewConnector creates a connector with the default port and timeouts
+func NewConnector() *GocqlConnector {
+	return &GocqlConnector{
+		Port:    DefaultPort,
+		Timeout: defaultTimeout,
+	}
+}
+
+// Connect opens a session to the hosts authenticating with the credentials if they are set
+func (c *GocqlConnector) Connect(hosts []string, credentials *Credentials, opts ...ConnectOption) (Manager, error) {
+	op := &connectOptions{}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	cluster := gocql.NewCluster(hosts...)
+	cluster.Port = c.Port
+	cluster.Timeout = c.Timeout
+	cluster.ConnectTimeout = defaultConnectTimeout
+	// schema changes need to be agreed on by the whole ring
+	cluster.Consistency = gocql.Quorum
+	cluster.ProtoVersion = 3
+
+	if credentials != nil {
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: credentials.Username,
+			Password: credentials.Password,
+		}
+	}
+
+	if op.caCertificate != nil {
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(op.caCertificate) {
+			return nil, errors.New("no PEM encoded CA certificate to verify the CQL session with")
+		}
+
+		cluster.SslOpts = &gocql.SslOptions{
+			Config: &tls.Config{
+				RootCAs:    roots,
+				ServerName: op.serverName,
+			},
+			EnableHostVerification: true,
+		}
+	}
+
+	logrus.Debugf("Opening CQL session to %v", hosts)
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, fmt.Errorf("could not open CQL session to %v: %v", hosts, err)
+	}
+
+	return &Client{session: session}, nil
+}
+
+// Client implements Manager on top of a gocql session
+type Client struct {
+	session *gocql.Session
+}
+
+var _ Manager = &Client{}
+
+// Close closes the session
+func (c *Client) Close() {
+	c.session.Close()
+}
+
+func (c *Client) exec(statement string, values ...interface{}) error {
+	logrus.Debugf("Executing CQL statement: %s", statement)
+	return c.session.Query(statement, values...).Exec()
+}
@@ -0,0 +1,58 @@
+package cql
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// UsernameKey is the key of the username in the credentials secret
+	UsernameKey = "username"
+	// PasswordKey is the key of the password in the credentials secret
+	PasswordKey = "password"
+
+	generatedPasswordLength = 32
+	passwordAlphabet        = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// Credentials are used to authenticate a CQL session
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// NewCredentialsFromSecret reads the credentials from the username and password keys of the secret
+func NewCredentialsFromSecret(secret *corev1.Secret) (*Credentials, error) {
+	username, ok := secret.Data[UsernameKey]
+	if !ok || len(username) == 0 {
+		return nil, fmt.Errorf("secret %s is missing key '%s'", secret.GetName(), UsernameKey)
+	}
+
+	password, ok := secret.Data[PasswordKey]
+	if !ok || len(password) == 0 {
+		return nil, fmt.Errorf("secret %s is missing key '%s'", secret.GetName(), PasswordKey)
+	}
+
+	return &Credentials{
+		Username: string(username),
+		Password: string(password),
+	}, nil
+}
+
+// GeneratePassword returns a random alphanumeric password
+func GeneratePassword() (string, error) {
+	max := big.NewInt(int64(len(passwordAlphabet)))
+	password := make([]byte, generatedPasswordLength)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("could not generate password: %v", err)
+		}
+		password[i] = passwordAlphabet[n.Int64()]
+	}
+
+	return string(password), nil
+}
@@ -0,0 +1,59 @@
+package cql_test
+
+import (
+	"testing"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewCredentialsFromSecret(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string][]byte
+		expected *cql.Credentials
+	}{
+		{
+			name:     "valid",
+			data:     map[string][]byte{"username": []byte("operator"), "password": []byte("secret")},
+			expected: &cql.Credentials{Username: "operator", Password: "secret"},
+		},
+		{
+			name: "missing username",
+			data: map[string][]byte{"password": []byte("secret")},
+		},
+		{
+			name: "empty password",
+			data: map[string][]byte{"username": []byte("operator"), "password": []byte{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-cql-credentials"},
+				Data:       tt.data,
+			}
+
+			credentials, err := cql.NewCredentialsFromSecret(secret)
+			if tt.expected == nil {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, credentials)
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	first, err := cql.GeneratePassword()
+	assert.NoError(t, err)
+	assert.Regexp(t, "^[a-zA-Z0-9]{32}$", first)
+
+	second, err := cql.GeneratePassword()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
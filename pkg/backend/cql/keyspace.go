@@ -0,0 +1,161 @@
+package cql
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gocql/gocql"
+	"github.com/sirupsen/logrus"
+)
+
+const networkTopologyStrategy = "org.apache.cassandra.locator.NetworkTopologyStrategy"
+
+// cassandra only allows alphanumeric characters and underscores in keyspace and table names, even when quoted.
+// Unquoted names are lower cased by cassandra so upper case is not accepted to keep names in the schema tables
+// matching the names in the statements
+var identifierRegexp = regexp.MustCompile(`^[a-z0-9_]{1,48}$`)
+
+// Keyspace is the current definition of a keyspace
+type Keyspace struct {
+	Name          string
+	Strategy      string
+	Replication   map[string]int
+	DurableWrites bool
+}
+
+// ValidateIdentifier returns an error if the name can not be used as a keyspace name
+func ValidateIdentifier(name string) error {
+	if !identifierRegexp.MatchString(name) {
+		return fmt.Errorf("'%s' is not a valid keyspace name, only up to 48 lower case alphanumeric characters and underscores are allowed", name)
+	}
+	return nil
+}
+
+// EnsureKeyspace creates the keyspace with NetworkTopologyStrategy if it does not exist, an existing keyspace
+// is altered when a datacenter is missing, has a different replication factor or durable writes differ. Datacenters that are replicated
+// to but not in the desired replication are kept, dropping a datacenter has to be done by hand
+func (c *Client) EnsureKeyspace(keyspace string, replication map[string]int, durableWrites bool) error {
+	err := ValidateIdentifier(keyspace)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.GetKeyspace(keyspace)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		logrus.Infof("Creating keyspace %s with replication %v", keyspace, replication)
+		return c.exec(CreateKeyspaceStatement(keyspace, replication, durableWrites))
+	}
+
+	merged, changed := MergeReplication(current, replication)
+	if !changed && current.DurableWrites == durableWrites {
+		return nil
+	}
+
+	logrus.Infof("Altering keyspace %s from replication %v to %v and durable writes %t", keyspace, current.Replication, merged, durableWrites)
+	return c.exec(AlterKeyspaceStatement(keyspace, merged, durableWrites))
+}
+
+// GetKeyspace returns the current definition of the keyspace or nil if it does not exist
+func (c *Client) GetKeyspace(keyspace string) (*Keyspace, error) {
+	var durableWrites bool
+	var replication map[string]string
+
+	err := c.session.Query(
+		"SELECT durable_writes, replication FROM system_schema.keyspaces WHERE keyspace_name = ?",
+		keyspace,
+	).Scan(&durableWrites, &replication)
+	if err == gocql.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read keyspace %s: %v", keyspace, err)
+	}
+
+	return ParseReplication(keyspace, durableWrites, replication)
+}
+
+// ParseReplication converts the replication map stored in system_schema.keyspaces into a Keyspace
+func ParseReplication(keyspace string, durableWrites bool, replication map[string]string) (*Keyspace, error) {
+	ks := &Keyspace{
+		Name:          keyspace,
+		DurableWrites: durableWrites,
+		Replication:   map[string]int{},
+	}
+
+	for key, value := range replication {
+		switch key {
+		case "class":
+			ks.Strategy = value
+		default:
+			rf, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid replication factor '%s' for %s in keyspace %s", value, key, keyspace)
+			}
+			ks.Replication[key] = rf
+		}
+	}
+
+	return ks, nil
+}
+
+// MergeReplication returns the replication the keyspace should have and if it differs from the current one. A keyspace
+// not using NetworkTopologyStrategy is always changed and only gets the desired datacenters
+func MergeReplication(current *Keyspace, desired map[string]int) (map[string]int, bool) {
+	merged := map[string]int{}
+	changed := current.Strategy != networkTopologyStrategy
+
+	if !changed {
+		for dc, rf := range current.Replication {
+			merged[dc] = rf
+		}
+	}
+
+	for dc, rf := range desired {
+		if merged[dc] != rf {
+			merged[dc] = rf
+			changed = true
+		}
+	}
+
+	return merged, changed
+}
+
+// CreateKeyspaceStatement builds an idempotent CREATE KEYSPACE statement using NetworkTopologyStrategy
+func CreateKeyspaceStatement(keyspace string, replication map[string]int, durableWrites bool) string {
+	return fmt.Sprintf("CREATE KEYSPACE IF NOT EXISTS %s WITH replication = %s AND durable_writes = %t",
+		keyspace, replicationLiteral(replication), durableWrites)
+}
+
+// AlterKeyspaceStatement builds an ALTER KEYSPACE statement setting the replication using NetworkTopologyStrategy
+func AlterKeyspaceStatement(keyspace string, replication map[string]int, durableWrites bool) string {
+	return fmt.Sprintf("ALTER KEYSPACE %s WITH replication = %s AND durable_writes = %t",
+		keyspace, replicationLiteral(replication), durableWrites)
+}
+
+// replicationLiteral renders the replication map with the datacenters sorted so the statement is stable
+func replicationLiteral(replication map[string]int) string {
+	dcs := make([]string, 0, len(replication))
+	for dc := range replication {
+		dcs = append(dcs, dc)
+	}
+	sort.Strings(dcs)
+
+	parts := []string{fmt.Sprintf("'class': '%s'", networkTopologyStrategy)}
+	for _, dc := range dcs {
+		parts = append(parts, fmt.Sprintf("'%s': %d", quoteString(dc), replication[dc]))
+	}
+
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// quoteString escapes single quotes for use inside a CQL string literal
+func quoteString(value string) string {
+	return strings.Replace(value, "'", "''", -1)
+}
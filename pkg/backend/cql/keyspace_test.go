@@ -0,0 +1,129 @@
+package cql_test
+
+import (
+	"testing"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyspace string
+		valid    bool
+	}{
+		{name: "simple", keyspace: "test_cluster", valid: true},
+		{name: "hyphens", keyspace: "test-cluster-1", valid: false},
+		{name: "upper case", keyspace: "Test_Cluster", valid: false},
+		{name: "quote", keyspace: "test'cluster", valid: false},
+		{name: "empty", keyspace: "", valid: false},
+		{name: "too long", keyspace: "a123456789012345678901234567890123456789012345678", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cql.ValidateIdentifier(tt.keyspace)
+			assert.Equal(t, tt.valid, err == nil)
+		})
+	}
+}
+
+func TestCreateKeyspaceStatement(t *testing.T) {
+	statement := cql.CreateKeyspaceStatement("test_cluster", map[string]int{"us-west1": 3, "us-central1": 2}, true)
+
+	assert.Equal(t,
+		"CREATE KEYSPACE IF NOT EXISTS test_cluster WITH replication = "+
+			"{'class': 'org.apache.cassandra.locator.NetworkTopologyStrategy', 'us-central1': 2, 'us-west1': 3} "+
+			"AND durable_writes = true",
+		statement,
+	)
+}
+
+func TestAlterKeyspaceStatement_EscapesDatacenter(t *testing.T) {
+	statement := cql.AlterKeyspaceStatement("test_cluster", map[string]int{"dc'1": 1}, false)
+
+	assert.Equal(t,
+		"ALTER KEYSPACE test_cluster WITH replication = "+
+			"{'class': 'org.apache.cassandra.locator.NetworkTopologyStrategy', 'dc''1': 1} "+
+			"AND durable_writes = false",
+		statement,
+	)
+}
+
+func TestParseReplication(t *testing.T) {
+	ks, err := cql.ParseReplication("test_cluster", true, map[string]string{
+		"class":    "org.apache.cassandra.locator.NetworkTopologyStrategy",
+		"us-west1": "3",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &cql.Keyspace{
+		Name:          "test_cluster",
+		Strategy:      "org.apache.cassandra.locator.NetworkTopologyStrategy",
+		Replication:   map[string]int{"us-west1": 3},
+		DurableWrites: true,
+	}, ks)
+
+	_, err = cql.ParseReplication("test_cluster", true, map[string]string{"us-west1": "three"})
+	assert.Error(t, err)
+}
+
+func TestMergeReplication(t *testing.T) {
+	nts := "org.apache.cassandra.locator.NetworkTopologyStrategy"
+
+	tests := []struct {
+		name            string
+		current         *cql.Keyspace
+		desired         map[string]int
+		expected        map[string]int
+		expectedChanged bool
+	}{
+		{
+			name:            "unchanged",
+			current:         &cql.Keyspace{Strategy: nts, Replication: map[string]int{"dc1": 3}},
+			desired:         map[string]int{"dc1": 3},
+			expected:        map[string]int{"dc1": 3},
+			expectedChanged: false,
+		},
+		{
+			name:            "datacenter added",
+			current:         &cql.Keyspace{Strategy: nts, Replication: map[string]int{"dc1": 3}},
+			desired:         map[string]int{"dc1": 3, "dc2": 3},
+			expected:        map[string]int{"dc1": 3, "dc2": 3},
+			expectedChanged: true,
+		},
+		{
+			name:            "replication factor changed",
+			current:         &cql.Keyspace{Strategy: nts, Replication: map[string]int{"dc1": 1}},
+			desired:         map[string]int{"dc1": 3},
+			expected:        map[string]int{"dc1": 3},
+			expectedChanged: true,
+		},
+		{
+			name:            "unmanaged datacenter is kept",
+			current:         &cql.Keyspace{Strategy: nts, Replication: map[string]int{"dc1": 3, "dc2": 2}},
+			desired:         map[string]int{"dc1": 3},
+			expected:        map[string]int{"dc1": 3, "dc2": 2},
+			expectedChanged: false,
+		},
+		{
+			name: "simple strategy is replaced",
+			current: &cql.Keyspace{
+				Strategy:    "org.apache.cassandra.locator.SimpleStrategy",
+				Replication: map[string]int{"replication_factor": 1},
+			},
+			desired:         map[string]int{"dc1": 3},
+			expected:        map[string]int{"dc1": 3},
+			expectedChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, changed := cql.MergeReplication(tt.current, tt.desired)
+			assert.Equal(t, tt.expected, merged)
+			assert.Equal(t, tt.expectedChanged, changed)
+		})
+	}
+}
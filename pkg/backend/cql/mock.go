@@ -0,0 +1,133 @@
+package cql
+
+// MockConnector implements a mock of the cql connector
+type MockConnector struct {
+	Manager Manager
+	Err     error
+
+	ConnectCallback func(hosts []string, credentials *Credentials) (Manager, error)
+}
+
+// Connect returns mock values
+func (c *MockConnector) Connect(hosts []string, credentials *Credentials, opts ...ConnectOption) (Manager, error) {
+	if c.ConnectCallback != nil {
+		return c.ConnectCallback(hosts, credentials)
+	}
+	return c.Manager, c.Err
+}
+
+// MockManager implements a mock of the cql manager
+type MockManager struct {
+	LocalNode *Node
+	PeerNodes []Node
+
+	EnsureKeyspaceCallback func(keyspace string, replication map[string]int, durableWrites bool) error
+	GetKeyspaceCallback    func(keyspace string) (*Keyspace, error)
+	GetRoleCallback        func(name string) (*Role, error)
+	CreateRoleCallback     func(role Role, password string) error
+	AlterRoleCallback      func(role Role, password string) error
+	DropRoleCallback       func(name string) error
+	GetPermissionsCallback func(name string) ([]Grant, error)
+	GrantCallback          func(name string, grant Grant) error
+	RevokeCallback         func(name string, grant Grant) error
+	LocalCallback          func() (*Node, error)
+	PeersCallback          func() ([]Node, error)
+	CloseCallback          func()
+}
+
+// EnsureKeyspace returns mock value
+func (m *MockManager) EnsureKeyspace(keyspace string, replication map[string]int, durableWrites bool) error {
+	if m.EnsureKeyspaceCallback != nil {
+		return m.EnsureKeyspaceCallback(keyspace, replication, durableWrites)
+	}
+	return nil
+}
+
+// GetKeyspace returns mock value
+func (m *MockManager) GetKeyspace(keyspace string) (*Keyspace, error) {
+	if m.GetKeyspaceCallback != nil {
+		return m.GetKeyspaceCallback(keyspace)
+	}
+	return nil, nil
+}
+
+// GetRole returns mock value
+func (m *MockManager) GetRole(name string) (*Role, error) {
+	if m.GetRoleCallback != nil {
+		return m.GetRoleCallback(name)
+	}
+	return nil, nil
+}
+
+// CreateRole returns mock value
+func (m *MockManager) CreateRole(role Role, password string) error {
+	if m.CreateRoleCallback != nil {
+		return m.CreateRoleCallback(role, password)
+	}
+	return nil
+}
+
+// AlterRole returns mock value
+func (m *MockManager) AlterRole(role Role, password string) error {
+	if m.AlterRoleCallback != nil {
+		return m.AlterRoleCallback(role, password)
+	}
+	return nil
+}
+
+// DropRole returns mock value
+func (m *MockManager) DropRole(name string) error {
+	if m.DropRoleCallback != nil {
+		return m.DropRoleCallback(name)
+	}
+	return nil
+}
+
+// GetPermissions returns mock value
+func (m *MockManager) GetPermissions(name string) ([]Grant, error) {
+	if m.GetPermissionsCallback != nil {
+		return m.GetPermissionsCallback(name)
+	}
+	return []Grant{}, nil
+}
+
+// Grant returns mock value
+func (m *MockManager) Grant(name string, grant Grant) error {
+	if m.GrantCallback != nil {
+		return m.GrantCallback(name, grant)
+	}
+	return nil
+}
+
+// Revoke returns mock value
+func (m *MockManager) Revoke(name string, grant Grant) error {
+	if m.RevokeCallback != nil {
+		return m.RevokeCallback(name, grant)
+	}
+	return nil
+}
+
+// Local returns mock value
+func (m *MockManager) Local() (*Node, error) {
+	if m.LocalCallback != nil {
+		return m.LocalCallback()
+	}
+	return m.LocalNode, nil
+}
+
+// Peers returns mock value
+func (m *MockManager) Peers() ([]Node, error) {
+	if m.PeersCallback != nil {
+		return m.PeersCallback()
+	}
+	return m.PeerNodes, nil
+}
+
+var _ Manager = &MockManager{}
+
+// Close calls the mock callback
+func (m *MockManager) Close() {
+	if m.CloseCallback != nil {
+		m.CloseCallback()
+	}
+}
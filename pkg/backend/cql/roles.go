@@ -0,0 +1,225 @@
+package cql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gocql/gocql"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultSuperuser is the superuser cassandra creates when authentication is first enabled
+	DefaultSuperuser = "cassandra"
+
+	dataResourcePrefix = "data/"
+	permissionAll      = "ALL"
+)
+
+// DefaultCredentials are the credentials of the default superuser
+var DefaultCredentials = &Credentials{Username: DefaultSuperuser, Password: DefaultSuperuser}
+
+// keyspacePermissions are the permissions that can be granted on a keyspace, ALL grants each of them
+var keyspacePermissions = []string{"ALTER", "AUTHORIZE", "CREATE", "DROP", "MODIFY", "SELECT"}
+
+// Role is a role in system_auth.roles
+type Role struct {
+	Name      string
+	Login     bool
+	Superuser bool
+}
+
+// Grant is a single permission on a keyspace
+type Grant struct {
+	Permission string
+	Keyspace   string
+}
+
+// GetRole returns the role or nil if it does not exist
+func (c *Client) GetRole(name string) (*Role, error) {
+	role := &Role{Name: name}
+
+	err := c.session.Query(
+		"SELECT can_login, is_superuser FROM system_auth.roles WHERE role = ?",
+		name,
+	).Scan(&role.Login, &role.Superuser)
+	if err == gocql.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read role %s: %v", name, err)
+	}
+
+	return role, nil
+}
+
+// CreateRole creates the role with the password if it does not exist
+func (c *Client) CreateRole(role Role, password string) error {
+	logrus.Infof("Creating role %s", role.Name)
+	return c.exec(CreateRoleStatement(role, password))
+}
+
+// AlterRole sets the login and superuser flags of the role, the password is only changed when it is not empty
+func (c *Client) AlterRole(role Role, password string) error {
+	logrus.Infof("Altering role %s", role.Name)
+	return c.exec(AlterRoleStatement(role, password))
+}
+
+// DropRole drops the role if it exists
+func (c *Client) DropRole(name string) error {
+	logrus.Infof("Dropping role %s", name)
+	return c.exec(fmt.Sprintf("DROP ROLE IF EXISTS %s", quoteIdentifier(name)))
+}
+
+// GetPermissions returns the permissions granted directly to the role on keyspaces
+func (c *Client) GetPermissions(name string) ([]Grant, error) {
+	var resource string
+	var permissions []string
+
+	grants := []Grant{}
+	iter := c.session.Query(
+		"SELECT resource, permissions FROM system_auth.role_permissions WHERE role = ?",
+		name,
+	).Iter()
+	for iter.Scan(&resource, &permissions) {
+		grants = append(grants, parsePermissions(resource, permissions)...)
+	}
+
+	err := iter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not read permissions of role %s: %v", name, err)
+	}
+
+	return grants, nil
+}
+
+// Grant grants the permission on the keyspace to the role
+func (c *Client) Grant(name string, grant Grant) error {
+	logrus.Infof("Granting %s on %s to %s", grant.Permission, grant.Keyspace, name)
+	return c.exec(fmt.Sprintf("GRANT %s ON KEYSPACE %s TO %s", grant.Permission, grant.Keyspace, quoteIdentifier(name)))
+}
+
+// Revoke revokes the permission on the keyspace from the role
+func (c *Client) Revoke(name string, grant Grant) error {
+	logrus.Infof("Revoking %s on %s from %s", grant.Permission, grant.Keyspace, name)
+	return c.exec(fmt.Sprintf("REVOKE %s ON KEYSPACE %s FROM %s", grant.Permission, grant.Keyspace, quoteIdentifier(name)))
+}
+
+// CreateRoleStatement builds an idempotent CREATE ROLE statement
+func CreateRoleStatement(role Role, password string) string {
+	return fmt.Sprintf("CREATE ROLE IF NOT EXISTS %s WITH PASSWORD = '%s' AND LOGIN = %t AND SUPERUSER = %t",
+		quoteIdentifier(role.Name), quoteString(password), role.Login, role.Superuser)
+}
+
+// AlterRoleStatement builds an ALTER ROLE statement, the password is left out when empty
+func AlterRoleStatement(role Role, password string) string {
+	options := []string{}
+	if password != "" {
+		options = append(options, fmt.Sprintf("PASSWORD = '%s'", quoteString(password)))
+	}
+	options = append(options,
+		fmt.Sprintf("LOGIN = %t", role.Login),
+		fmt.Sprintf("SUPERUSER = %t", role.Superuser),
+	)
+
+	return fmt.Sprintf("ALTER ROLE %s WITH %s", quoteIdentifier(role.Name), strings.Join(options, " AND "))
+}
+
+// ValidatePermission returns an error if the permission can not be granted on a keyspace
+func ValidatePermission(permission string) error {
+	if permission == permissionAll {
+		return nil
+	}
+	for _, p := range keyspacePermissions {
+		if p == permission {
+			return nil
+		}
+	}
+	return fmt.Errorf("'%s' is not a keyspace permission, expected ALL or one of %s", permission, strings.Join(keyspacePermissions, ", "))
+}
+
+// ExpandGrants turns ALL into the individual keyspace permissions and removes duplicates, the result is sorted
+func ExpandGrants(grants []Grant) []Grant {
+	seen := map[Grant]bool{}
+	expanded := []Grant{}
+
+	add := func(g Grant) {
+		if !seen[g] {
+			seen[g] = true
+			expanded = append(expanded, g)
+		}
+	}
+
+	for _, grant := range grants {
+		if grant.Permission != permissionAll {
+			add(grant)
+			continue
+		}
+		for _, permission := range keyspacePermissions {
+			add(Grant{Permission: permission, Keyspace: grant.Keyspace})
+		}
+	}
+
+	sortGrants(expanded)
+	return expanded
+}
+
+// DiffGrants returns the grants that are desired but missing and the ones that are held but not desired
+func DiffGrants(current, desired []Grant) ([]Grant, []Grant) {
+	current = ExpandGrants(current)
+	desired = ExpandGrants(desired)
+
+	held := map[Grant]bool{}
+	for _, grant := range current {
+		held[grant] = true
+	}
+
+	wanted := map[Grant]bool{}
+	missing := []Grant{}
+	for _, grant := range desired {
+		wanted[grant] = true
+		if !held[grant] {
+			missing = append(missing, grant)
+		}
+	}
+
+	extra := []Grant{}
+	for _, grant := range current {
+		if !wanted[grant] {
+			extra = append(extra, grant)
+		}
+	}
+
+	return missing, extra
+}
+
+// parsePermissions converts a row of system_auth.role_permissions into grants, resources other than
+// keyspaces such as tables and functions are not managed and skipped
+func parsePermissions(resource string, permissions []string) []Grant {
+	keyspace := strings.TrimPrefix(resource, dataResourcePrefix)
+	if keyspace == resource || strings.Contains(keyspace, "/") {
+		return nil
+	}
+
+	grants := []Grant{}
+	for _, permission := range permissions {
+		grants = append(grants, Grant{Permission: permission, Keyspace: keyspace})
+	}
+
+	return grants
+}
+
+func sortGrants(grants []Grant) {
+	sort.Slice(grants, func(i, j int) bool {
+		if grants[i].Keyspace != grants[j].Keyspace {
+			return grants[i].Keyspace < grants[j].Keyspace
+		}
+		return grants[i].Permission < grants[j].Permission
+	})
+}
+
+// quoteIdentifier quotes a role name so it keeps its case and may contain any character
+func quoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
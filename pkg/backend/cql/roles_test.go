@@ -0,0 +1,89 @@
+package cql_test
+
+import (
+	"testing"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRoleStatement(t *testing.T) {
+	statement := cql.CreateRoleStatement(cql.Role{Name: "app-user", Login: true}, "pa'ss")
+
+	assert.Equal(t,
+		`CREATE ROLE IF NOT EXISTS "app-user" WITH PASSWORD = 'pa''ss' AND LOGIN = true AND SUPERUSER = false`,
+		statement,
+	)
+}
+
+func TestAlterRoleStatement(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     cql.Role
+		password string
+		expected string
+	}{
+		{
+			name:     "with password",
+			role:     cql.Role{Name: "admin", Login: true, Superuser: true},
+			password: "secret",
+			expected: `ALTER ROLE "admin" WITH PASSWORD = 'secret' AND LOGIN = true AND SUPERUSER = true`,
+		},
+		{
+			name:     "without password",
+			role:     cql.Role{Name: `we"ird`},
+			expected: `ALTER ROLE "we""ird" WITH LOGIN = false AND SUPERUSER = false`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, cql.AlterRoleStatement(tt.role, tt.password))
+		})
+	}
+}
+
+func TestValidatePermission(t *testing.T) {
+	assert.NoError(t, cql.ValidatePermission("ALL"))
+	assert.NoError(t, cql.ValidatePermission("SELECT"))
+	assert.Error(t, cql.ValidatePermission("EXECUTE"))
+	assert.Error(t, cql.ValidatePermission("select"))
+}
+
+func TestExpandGrants(t *testing.T) {
+	grants := cql.ExpandGrants([]cql.Grant{
+		{Permission: "SELECT", Keyspace: "b"},
+		{Permission: "ALL", Keyspace: "a"},
+		{Permission: "SELECT", Keyspace: "a"},
+	})
+
+	assert.Equal(t, []cql.Grant{
+		{Permission: "ALTER", Keyspace: "a"},
+		{Permission: "AUTHORIZE", Keyspace: "a"},
+		{Permission: "CREATE", Keyspace: "a"},
+		{Permission: "DROP", Keyspace: "a"},
+		{Permission: "MODIFY", Keyspace: "a"},
+		{Permission: "SELECT", Keyspace: "a"},
+		{Permission: "SELECT", Keyspace: "b"},
+	}, grants)
+}
+
+func TestDiffGrants(t *testing.T) {
+	current := []cql.Grant{
+		{Permission: "SELECT", Keyspace: "a"},
+		{Permission: "MODIFY", Keyspace: "a"},
+		{Permission: "SELECT", Keyspace: "old"},
+	}
+	desired := []cql.Grant{
+		{Permission: "SELECT", Keyspace: "a"},
+		{Permission: "SELECT", Keyspace: "b"},
+	}
+
+	missing, extra := cql.DiffGrants(current, desired)
+
+	assert.Equal(t, []cql.Grant{{Permission: "SELECT", Keyspace: "b"}}, missing)
+	assert.Equal(t, []cql.Grant{
+		{Permission: "MODIFY", Keyspace: "a"},
+		{Permission: "SELECT", Keyspace: "old"},
+	}, extra)
+}
@@ -0,0 +1,98 @@
+package cql
+
+import (
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+// Node is a member of the ring as reported by system.local or system.peers
+type Node struct {
+	HostID     string
+	Address    string
+	Datacenter string
+	Rack       string
+}
+
+// Local returns the node the session is connected to
+func (c *Client) Local() (*Node, error) {
+	var hostID gocql.UUID
+	node := &Node{}
+
+	err := c.session.Query(
+		"SELECT host_id, broadcast_address, data_center, rack FROM system.local WHERE key = 'local'",
+	).Scan(&hostID, &node.Address, &node.Datacenter, &node.Rack)
+	if err != nil {
+		return nil, fmt.Errorf("could not read system.local: %v", err)
+	}
+	node.HostID = hostID.String()
+
+	return node, nil
+}
+
+// Peers returns the other nodes of the ring as seen by the node the session is connected to
+func (c *Client) Peers() ([]Node, error) {
+	var hostID gocql.UUID
+	var address, datacenter, rack string
+
+	nodes := []Node{}
+	iter := c.session.Query("SELECT host_id, peer, data_center, rack FROM system.peers").Iter()
+	for iter.Scan(&hostID, &address, &datacenter, &rack) {
+		nodes = append(nodes, Node{
+			HostID:     hostID.String(),
+			Address:    address,
+			Datacenter: datacenter,
+			Rack:       rack,
+		})
+	}
+
+	err := iter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not read system.peers: %v", err)
+	}
+
+	return nodes, nil
+}
+
+// Ring returns the local node and its peers
+func Ring(m Manager) ([]Node, error) {
+	local, err := m.Local()
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := m.Peers()
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Node{*local}, peers...), nil
+}
+
+// CompareHostIDs cross-checks the ring read over CQL against the host IDs the node backend reported for the
+// datacenter. It returns the host IDs that are not in the ring and the host IDs of ring nodes in the datacenter
+// that were not reported
+func CompareHostIDs(ring []Node, datacenter string, hostIDs []string) ([]string, []string) {
+	inRing := map[string]bool{}
+	for _, node := range ring {
+		inRing[node.HostID] = true
+	}
+
+	reported := map[string]bool{}
+	missing := []string{}
+	for _, hostID := range hostIDs {
+		reported[hostID] = true
+		if !inRing[hostID] {
+			missing = append(missing, hostID)
+		}
+	}
+
+	unreported := []string{}
+	for _, node := range ring {
+		if node.Datacenter == datacenter && !reported[node.HostID] {
+			unreported = append(unreported, node.HostID)
+		}
+	}
+
+	return missing, unreported
+}
@@ -0,0 +1,39 @@
+package cql_test
+
+import (
+	"testing"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRing(t *testing.T) {
+	manager := &cql.MockManager{
+		LocalNode: &cql.Node{HostID: "host-1", Datacenter: "dc1"},
+		PeerNodes: []cql.Node{{HostID: "host-2", Datacenter: "dc1"}},
+	}
+
+	ring, err := cql.Ring(manager)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []cql.Node{
+		{HostID: "host-1", Datacenter: "dc1"},
+		{HostID: "host-2", Datacenter: "dc1"},
+	}, ring)
+}
+
+func TestCompareHostIDs(t *testing.T) {
+	ring := []cql.Node{
+		{HostID: "host-1", Datacenter: "dc1"},
+		{HostID: "host-2", Datacenter: "dc1"},
+		{HostID: "host-3", Datacenter: "dc2"},
+	}
+
+	missing, unreported := cql.CompareHostIDs(ring, "dc1", []string{"host-1", "host-2"})
+	assert.Empty(t, missing)
+	assert.Empty(t, unreported)
+
+	missing, unreported = cql.CompareHostIDs(ring, "dc1", []string{"host-1", "host-4"})
+	assert.Equal(t, []string{"host-4"}, missing)
+	assert.Equal(t, []string{"host-2"}, unreported)
+}
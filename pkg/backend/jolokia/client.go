@@ -0,0 +1,258 @@
+package jolokia
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultPort is the default port of the jolokia agent, exposed as "metrics" on the cassandra pod
+	DefaultPort = 8778
+
+	defaultReadTimeout = 30 * time.Second
+
+	storageServiceMBean     = "org.apache.cassandra.db:type=StorageService"
+	endpointSnitchInfoMBean = "org.apache.cassandra.db:type=EndpointSnitchInfo"
+	runtimeMBean            = "java.lang:type=Runtime"
+	memoryMBean             = "java.lang:type=Memory"
+
+	attributeNotFound = "javax.management.AttributeNotFoundException"
+)
+
+var errNoPod = errors.New("jolokia client requires a pod to query")
+
+// request is a single jolokia read or exec request
+type request struct {
+	Type      string        `json:"type"`
+	MBean     string        `json:"mbean"`
+	Attribute interface{}   `json:"attribute,omitempty"`
+	Operation string        `json:"operation,omitempty"`
+	Arguments []interface{} `json:"arguments,omitempty"`
+}
+
+// response is a single jolokia response, bulk requests return one per request in the same order
+type response struct {
+	Value     json.RawMessage `json:"value"`
+	Status    int             `json:"status"`
+	Error     string          `json:"error,omitempty"`
+	ErrorType string          `json:"error_type,omitempty"`
+}
+
+// requestError is a request of a bulk request that failed, the error type is the java exception class
+type requestError struct {
+	request   request
+	status    int
+	message   string
+	errorType string
+}
+
+func (e *requestError) Error() string {
+	return fmt.Sprintf("jolokia request %s on %s failed with status %d: %s",
+		describe(e.request), e.request.MBean, e.status, e.message)
+}
+
+// isAttributeNotFound returns true when the error is a read of an attribute the mbean does not have
+func isAttributeNotFound(err error) bool {
+	e, ok := err.(*requestError)
+	return ok && e.errorType == attributeNotFound
+}
+
+// Client implements nodetool.NodeManager by talking to the jolokia agent running inside the cassandra
+// JVM instead of exec'ing nodetool in the pod
+type Client struct {
+	httpClient  *http.Client
+	readTimeout time.Duration
+	port        int
+	resolveURL  func(pod *corev1.Pod) string
+}
+
+var _ nodetool.NodeManager = &Client{}
+
+// Option is a function that sets the configuration on the Client
+type Option func(*Client)
+
+// WithPort sets the port the jolokia agent listens on
+func WithPort(port int) Option {
+	return func(c *Client) {
+		c.port = port
+	}
+}
+
+// WithHTTPClient sets the http client used for requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithReadTimeout sets the timeout for read requests, long running operations such as drain
+// and decommission are not bound by it
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.readTimeout = timeout
+	}
+}
+
+// WithURLResolver overrides how the base url of the jolokia agent is found for a pod
+func WithURLResolver(resolver func(pod *corev1.Pod) string) Option {
+	return func(c *Client) {
+		c.resolveURL = resolver
+	}
+}
+
+// NewClient creates a new jolokia client
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient:  &http.Client{},
+		readTimeout: defaultReadTimeout,
+		port:        DefaultPort,
+	}
+	c.resolveURL = c.podURL
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Client) podURL(pod *corev1.Pod) string {
+	return fmt.Sprintf("http://%s:%d", pod.Status.PodIP, c.port)
+}
+
+// do sends the requests as a single bulk request and returns the values in request order
+func (c *Client) do(ctx context.Context, pod *corev1.Pod, timeout time.Duration, requests ...request) ([]json.RawMessage, error) {
+	if pod == nil {
+		return nil, errNoPod
+	}
+
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.resolveURL(pod) + "/jolokia/"
+	logrus.Debugf("Sending %d jolokia requests to pod %s/%s", len(requests), pod.GetNamespace(), pod.GetName())
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// classified like the failures of nodetool so callers can branch on them regardless of the backend
+		kind := nodetool.ErrorKindJMXUnavailable
+		if ctx.Err() != nil {
+			kind = nodetool.ErrorKindTimeout
+		}
+		return nil, &nodetool.Error{
+			Kind:     kind,
+			Command:  describe(requests[0]),
+			ExitCode: -1,
+			Err:      fmt.Errorf("could not reach jolokia on pod %s: %v", pod.GetName(), err),
+		}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jolokia on pod %s returned http status %d", pod.GetName(), resp.StatusCode)
+	}
+
+	responses := []response{}
+	err = json.Unmarshal(respBody, &responses)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jolokia response from pod %s: %v", pod.GetName(), err)
+	}
+
+	if len(responses) != len(requests) {
+		return nil, fmt.Errorf("jolokia returned %d responses for %d requests", len(responses), len(requests))
+	}
+
+	values := make([]json.RawMessage, len(responses))
+	for i, r := range responses {
+		if r.Status != http.StatusOK {
+			return nil, &requestError{request: requests[i], status: r.Status, message: r.Error, errorType: r.ErrorType}
+		}
+		values[i] = r.Value
+	}
+
+	return values, nil
+}
+
+// readAttributes reads a set of attributes from a single mbean, keyed by attribute name
+func (c *Client) readAttributes(ctx context.Context, pod *corev1.Pod, mbean string, attributes ...string) (map[string]json.RawMessage, error) {
+	values, err := c.do(ctx, pod, c.readTimeout, readRequest(mbean, attributes...))
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]json.RawMessage{}
+	err = json.Unmarshal(values[0], &result)
+	return result, err
+}
+
+// exec executes an operation on an mbean, a timeout of 0 only bounds the operation by the context
+func (c *Client) exec(ctx context.Context, pod *corev1.Pod, timeout time.Duration, mbean, operation string, args ...interface{}) (json.RawMessage, error) {
+	values, err := c.do(ctx, pod, timeout, execRequest(mbean, operation, args...))
+	if err != nil {
+		return nil, err
+	}
+
+	return values[0], nil
+}
+
+func readRequest(mbean string, attributes ...string) request {
+	r := request{
+		Type:  "read",
+		MBean: mbean,
+	}
+
+	// a single attribute returns the bare value, multiple return an object keyed by attribute
+	if len(attributes) == 1 {
+		r.Attribute = attributes[0]
+	} else if len(attributes) > 1 {
+		r.Attribute = attributes
+	}
+
+	return r
+}
+
+func execRequest(mbean, operation string, args ...interface{}) request {
+	return request{
+		Type:      "exec",
+		MBean:     mbean,
+		Operation: operation,
+		Arguments: args,
+	}
+}
+
+func describe(r request) string {
+	if r.Type == "exec" {
+		return fmt.Sprintf("exec %s", r.Operation)
+	}
+	return fmt.Sprintf("read %v", r.Attribute)
+}
@@ -0,0 +1,432 @@
+package jolokia_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/jolokia"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	storageService     = "org.apache.cassandra.db:type=StorageService"
+	endpointSnitchInfo = "org.apache.cassandra.db:type=EndpointSnitchInfo"
+)
+
+// fakeJolokia answers jolokia bulk requests from a fixed set of attributes and operations
+type fakeJolokia struct {
+	// attributes are keyed by mbean/attribute
+	attributes map[string]interface{}
+	// operations are keyed by mbean/operation/arguments
+	operations map[string]interface{}
+	executed   []string
+}
+
+func (f *fakeJolokia) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requests := []map[string]interface{}{}
+	err := json.NewDecoder(r.Body).Decode(&requests)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	responses := []map[string]interface{}{}
+	for _, req := range requests {
+		mbean := req["mbean"].(string)
+
+		var value interface{}
+		found := false
+		switch req["type"] {
+		case "read":
+			switch attr := req["attribute"].(type) {
+			case string:
+				value, found = f.attributes[mbean+"/"+attr]
+			case []interface{}:
+				values := map[string]interface{}{}
+				found = true
+				for _, a := range attr {
+					v, ok := f.attributes[mbean+"/"+a.(string)]
+					found = found && ok
+					values[a.(string)] = v
+				}
+				value = values
+			}
+		case "exec":
+			args, _ := req["arguments"].([]interface{})
+			key := fmt.Sprintf("%s/%s/%v", mbean, req["operation"], args)
+			f.executed = append(f.executed, key)
+			value, found = f.operations[key]
+		}
+
+		if !found {
+			errorType := "javax.management.InstanceNotFoundException"
+			if req["type"] == "read" && f.knows(mbean) {
+				errorType = "javax.management.AttributeNotFoundException"
+			}
+			responses = append(responses, map[string]interface{}{
+				"status":     404,
+				"error":      errorType,
+				"error_type": errorType,
+			})
+			continue
+		}
+
+		responses = append(responses, map[string]interface{}{
+			"status": 200,
+			"value":  value,
+		})
+	}
+
+	json.NewEncoder(w).Encode(responses)
+}
+
+// knows returns true when the mbean has any attribute
+func (f *fakeJolokia) knows(mbean string) bool {
+	for key := range f.attributes {
+		if strings.HasPrefix(key, mbean+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func newTestClient(fake *fakeJolokia) (*jolokia.Client, *httptest.Server) {
+	server := httptest.NewServer(fake)
+	client := jolokia.NewClient(jolokia.WithURLResolver(func(pod *corev1.Pod) string {
+		return server.URL
+	}))
+	return client, server
+}
+
+func testPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-cassandra-0",
+			Namespace: "test-namespace",
+		},
+		Status: corev1.PodStatus{
+			PodIP: "10.0.0.1",
+		},
+	}
+}
+
+func TestClient_GetStatus(t *testing.T) {
+	fake := &fakeJolokia{
+		attributes: map[string]interface{}{
+			storageService + "/LiveNodes":        []string{"10.0.0.1", "10.0.0.2"},
+			storageService + "/UnreachableNodes": []string{"10.0.0.3"},
+			storageService + "/JoiningNodes":     []string{"10.0.0.2"},
+			storageService + "/LeavingNodes":     []string{},
+			storageService + "/MovingNodes":      []string{},
+			storageService + "/LoadMap": map[string]string{
+				"10.0.0.1": "43.16 GB",
+				"10.0.0.2": "1.2 MB",
+				"10.0.0.3": "40.01 GB",
+			},
+			storageService + "/HostIdMap": map[string]string{
+				"10.0.0.1": "3b920369-cd41-4b6b-8f5f-192f1202ee18",
+				"10.0.0.2": "4d1a5c32-9642-405e-bd7e-27c8400bf779",
+				"10.0.0.3": "f652fd91-3c7d-43bb-84a2-b6d55e578b49",
+			},
+			storageService + "/Ownership": map[string]float64{
+				"/10.0.0.1": 0.5,
+				"/10.0.0.2": 0,
+				"/10.0.0.3": 0.5,
+			},
+			storageService + "/TokenToEndpointMap": map[string]string{
+				"-9223372036854775808": "10.0.0.1",
+				"0":                    "10.0.0.3",
+				"100":                  "10.0.0.1",
+			},
+		},
+		operations: map[string]interface{}{
+			endpointSnitchInfo + "/getDatacenter(java.lang.String)/[10.0.0.1]": "us-central1",
+			endpointSnitchInfo + "/getDatacenter(java.lang.String)/[10.0.0.2]": "us-central1",
+			endpointSnitchInfo + "/getDatacenter(java.lang.String)/[10.0.0.3]": "us-central1",
+			endpointSnitchInfo + "/getRack(java.lang.String)/[10.0.0.1]":       "us-central1-a",
+			endpointSnitchInfo + "/getRack(java.lang.String)/[10.0.0.2]":       "us-central1-b",
+			endpointSnitchInfo + "/getRack(java.lang.String)/[10.0.0.3]":       "us-central1-c",
+		},
+	}
+	client, server := newTestClient(fake)
+	defer server.Close()
+
+	result, err := client.GetStatus(context.Background(), testPod())
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.Equal(t, &nodetool.Status{
+		Status:     nodetool.NodeStatusUp,
+		State:      nodetool.NodeStateNormal,
+		Address:    "10.0.0.1",
+		Load:       "43.16 GB",
+		TokenCount: 2,
+		Owns:       50,
+		HostID:     "3b920369-cd41-4b6b-8f5f-192f1202ee18",
+		Rack:       "us-central1-a",
+		Datacenter: "us-central1",
+	}, result["3b920369-cd41-4b6b-8f5f-192f1202ee18"])
+	assert.Equal(t, nodetool.NodeStateJoining, result["4d1a5c32-9642-405e-bd7e-27c8400bf779"].State)
+	assert.Equal(t, nodetool.NodeStatusDown, result["f652fd91-3c7d-43bb-84a2-b6d55e578b49"].Status)
+}
+
+func TestClient_GetInfo(t *testing.T) {
+	fake := &fakeJolokia{
+		attributes: map[string]interface{}{
+			storageService + "/LocalHostId":                                                  "3b920369-cd41-4b6b-8f5f-192f1202ee18",
+			storageService + "/GossipRunning":                                                true,
+			storageService + "/RPCServerRunning":                                             true,
+			storageService + "/NativeTransportRunning":                                       true,
+			storageService + "/LoadString":                                                   "43.16 GB",
+			storageService + "/CurrentGenerationNumber":                                      1529817347,
+			storageService + "/Tokens":                                                       []string{"-9223372036854775808", "100"},
+			"java.lang:type=Runtime/Uptime":                                                  1871647000,
+			"java.lang:type=Memory/HeapMemoryUsage":                                          map[string]int64{"used": 1024 * 1024 * 1024, "max": 4 * 1024 * 1024 * 1024},
+			"org.apache.cassandra.metrics:type=Storage,name=Exceptions/Count":                2,
+			"org.apache.cassandra.metrics:type=Cache,scope=KeyCache,name=Entries/Value":      100,
+			"org.apache.cassandra.metrics:type=Cache,scope=KeyCache,name=Size/Value":         2048,
+			"org.apache.cassandra.metrics:type=Cache,scope=KeyCache,name=Capacity/Value":     4096,
+			"org.apache.cassandra.metrics:type=Cache,scope=KeyCache,name=Hits/Count":         3,
+			"org.apache.cassandra.metrics:type=Cache,scope=KeyCache,name=Requests/Count":     4,
+			"org.apache.cassandra.metrics:type=Cache,scope=RowCache,name=Entries/Value":      0,
+			"org.apache.cassandra.metrics:type=Cache,scope=RowCache,name=Size/Value":         0,
+			"org.apache.cassandra.metrics:type=Cache,scope=RowCache,name=Capacity/Value":     0,
+			"org.apache.cassandra.metrics:type=Cache,scope=RowCache,name=Hits/Count":         0,
+			"org.apache.cassandra.metrics:type=Cache,scope=RowCache,name=Requests/Count":     0,
+			"org.apache.cassandra.metrics:type=Cache,scope=CounterCache,name=Entries/Value":  0,
+			"org.apache.cassandra.metrics:type=Cache,scope=CounterCache,name=Size/Value":     0,
+			"org.apache.cassandra.metrics:type=Cache,scope=CounterCache,name=Capacity/Value": 1024,
+			"org.apache.cassandra.metrics:type=Cache,scope=CounterCache,name=Hits/Count":     0,
+			"org.apache.cassandra.metrics:type=Cache,scope=CounterCache,name=Requests/Count": 0,
+		},
+		operations: map[string]interface{}{
+			endpointSnitchInfo + "/getDatacenter(java.lang.String)/[10.0.0.1]": "us-central1",
+			endpointSnitchInfo + "/getRack(java.lang.String)/[10.0.0.1]":       "us-central1-b",
+		},
+	}
+	client, server := newTestClient(fake)
+	defer server.Close()
+
+	result, err := client.GetInfo(context.Background(), testPod())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3b920369-cd41-4b6b-8f5f-192f1202ee18", result.ID)
+	assert.True(t, result.GossipActive)
+	assert.True(t, result.ThriftActive)
+	assert.True(t, result.NativeTransportActive)
+	assert.Equal(t, "43.16 GB", result.Load)
+	assert.Equal(t, int64(1529817347), result.GenerationNo)
+	assert.Equal(t, int64(1871647), result.UptimeSeconds)
+	assert.Equal(t, float64(1024), result.HeapUsedMB)
+	assert.Equal(t, float64(4096), result.HeapTotalMB)
+	assert.Equal(t, "us-central1", result.Datacenter)
+	assert.Equal(t, "us-central1-b", result.Rack)
+	assert.Equal(t, 2, result.Exceptions)
+	assert.Equal(t, int64(100), result.KeyCache.Entries)
+	assert.Equal(t, "2048 bytes", result.KeyCache.Size)
+	assert.Equal(t, 0.75, result.KeyCache.RecentHitRate)
+	assert.Equal(t, "1024 bytes", result.CounterCache.Capacity)
+	assert.Equal(t, 2, result.TokenCount)
+}
+
+func TestClient_GetHostID(t *testing.T) {
+	fake := &fakeJolokia{
+		attributes: map[string]interface{}{
+			storageService + "/LocalHostId": "3b920369-cd41-4b6b-8f5f-192f1202ee18",
+		},
+	}
+	client, server := newTestClient(fake)
+	defer server.Close()
+
+	result, err := client.GetHostID(context.Background(), testPod())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3b920369-cd41-4b6b-8f5f-192f1202ee18", result)
+}
+
+func TestClient_GetNetstats(t *testing.T) {
+	fake := &fakeJolokia{
+		attributes: map[string]interface{}{
+			storageService + "/OperationMode":                                            "JOINING",
+			"org.apache.cassandra.metrics:type=ReadRepair,name=Attempted/Count":          1177089787,
+			"org.apache.cassandra.metrics:type=ReadRepair,name=RepairedBlocking/Count":   377073,
+			"org.apache.cassandra.metrics:type=ReadRepair,name=RepairedBackground/Count": 331734,
+			"org.apache.cassandra.net:type=StreamManager/CurrentStreams": []interface{}{
+				map[string]interface{}{
+					"planId":      "4c3b4fa0-8d54-11e8-9a5c-5b5d2f3c6f1e",
+					"description": "Bootstrap",
+					"sessions": []interface{}{
+						map[string]interface{}{
+							"peer": "10.0.0.1",
+							"receivingSummaries": []interface{}{
+								map[string]interface{}{"files": 2, "totalSize": 3072},
+								map[string]interface{}{"files": 1, "totalSize": 1024},
+							},
+							"receivingFiles": []interface{}{
+								map[string]interface{}{"currentBytes": 1024, "totalBytes": 1024},
+								map[string]interface{}{"currentBytes": 512, "totalBytes": 2048},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	client, server := newTestClient(fake)
+	defer server.Close()
+
+	result, err := client.GetNetstats(context.Background(), testPod())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &nodetool.Netstats{
+		Mode:      nodetool.NodeModeJoining,
+		Streaming: true,
+		Sessions: []nodetool.StreamSession{
+			{
+				Operation:  "Bootstrap",
+				PlanID:     "4c3b4fa0-8d54-11e8-9a5c-5b5d2f3c6f1e",
+				Peer:       "10.0.0.1",
+				Direction:  nodetool.StreamDirectionReceiving,
+				TotalFiles: 3,
+				TotalBytes: 4096,
+				DoneFiles:  1,
+				DoneBytes:  1536,
+			},
+		},
+		AttemptedReadRepairOps:        1177089787,
+		MismatchBlockingReadRepairOps: 377073,
+		MismatchBgReadRepairOps:       331734,
+	}, result)
+}
+
+func TestClient_Cleanup(t *testing.T) {
+	fake := &fakeJolokia{
+		attributes: map[string]interface{}{
+			storageService + "/NonLocalStrategyKeyspaces": []string{"system_auth", "system_distributed", "app"},
+		},
+		operations: map[string]interface{}{
+			storageService + "/forceKeyspaceCleanup(int,java.lang.String,[Ljava.lang.String;)/[0 system_auth []]":        0,
+			storageService + "/forceKeyspaceCleanup(int,java.lang.String,[Ljava.lang.String;)/[0 system_distributed []]": 0,
+			storageService + "/forceKeyspaceCleanup(int,java.lang.String,[Ljava.lang.String;)/[0 app []]":                1,
+		},
+	}
+	client, server := newTestClient(fake)
+	defer server.Close()
+
+	err := client.Cleanup(context.Background(), testPod())
+
+	assert.EqualError(t, err, "cleanup of keyspace app on pod test-cluster-cassandra-0 failed with status 1")
+	assert.Len(t, fake.executed, 3)
+}
+
+func TestClient_Drain(t *testing.T) {
+	fake := &fakeJolokia{
+		attributes: map[string]interface{}{
+			storageService + "/RPCServerRunning":       false,
+			storageService + "/NativeTransportRunning": false,
+		},
+		operations: map[string]interface{}{
+			storageService + "/drain/[]": nil,
+		},
+	}
+	client, server := newTestClient(fake)
+	defer server.Close()
+
+	err := client.Drain(context.Background(), testPod())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{storageService + "/drain/[]"}, fake.executed)
+}
+
+func TestClient_DrainWithoutThrift(t *testing.T) {
+	// cassandra 4 has no RPCServerRunning attribute
+	fake := &fakeJolokia{
+		attributes: map[string]interface{}{
+			storageService + "/NativeTransportRunning": false,
+		},
+		operations: map[string]interface{}{
+			storageService + "/drain/[]": nil,
+		},
+	}
+	client, server := newTestClient(fake)
+	defer server.Close()
+
+	err := client.Drain(context.Background(), testPod())
+
+	assert.NoError(t, err)
+}
+
+func TestClient_DrainStillRunning(t *testing.T) {
+	fake := &fakeJolokia{
+		attributes: map[string]interface{}{
+			storageService + "/RPCServerRunning":       false,
+			storageService + "/NativeTransportRunning": true,
+		},
+		operations: map[string]interface{}{
+			storageService + "/drain/[]": nil,
+		},
+	}
+	client, server := newTestClient(fake)
+	defer server.Close()
+
+	err := client.Drain(context.Background(), testPod())
+
+	assert.EqualError(t, err, "node drain failed")
+}
+
+func TestClient_DecommissionFailed(t *testing.T) {
+	fake := &fakeJolokia{
+		attributes: map[string]interface{}{
+			storageService + "/OperationMode":                                            "NORMAL",
+			"org.apache.cassandra.metrics:type=ReadRepair,name=Attempted/Count":          0,
+			"org.apache.cassandra.metrics:type=ReadRepair,name=RepairedBlocking/Count":   0,
+			"org.apache.cassandra.metrics:type=ReadRepair,name=RepairedBackground/Count": 0,
+			"org.apache.cassandra.net:type=StreamManager/CurrentStreams":                 []interface{}{},
+		},
+		operations: map[string]interface{}{
+			storageService + "/decommission/[]": nil,
+		},
+	}
+	client, server := newTestClient(fake)
+	defer server.Close()
+
+	err := client.Decommission(context.Background(), testPod())
+
+	assert.EqualError(t, err, "node decommission failed")
+}
+
+func TestClient_UnknownMBean(t *testing.T) {
+	fake := &fakeJolokia{}
+	client, server := newTestClient(fake)
+	defer server.Close()
+
+	_, err := client.GetHostID(context.Background(), testPod())
+
+	assert.Error(t, err)
+}
+
+func TestClient_NoPod(t *testing.T) {
+	client := jolokia.NewClient()
+
+	_, err := client.GetInfo(context.Background(), nil)
+
+	assert.Error(t, err)
+}
+
+func TestClient_Unreachable(t *testing.T) {
+	fake := &fakeJolokia{}
+	client, server := newTestClient(fake)
+	server.Close()
+
+	_, err := client.GetHostID(context.Background(), testPod())
+
+	assert.True(t, nodetool.IsJMXUnavailable(err))
+}
@@ -0,0 +1,171 @@
+package jolokia
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	bytesPerMB = 1024 * 1024
+
+	exceptionsMBean   = "org.apache.cassandra.metrics:type=Storage,name=Exceptions"
+	cacheMBeanPattern = "org.apache.cassandra.metrics:type=Cache,scope=%s,name=%s"
+)
+
+// nodeAttributes are the StorageService attributes that describe the local node
+type nodeAttributes struct {
+	LocalHostID             string `json:"LocalHostId"`
+	GossipRunning           bool
+	NativeTransportRunning  bool
+	LoadString              string
+	CurrentGenerationNumber int64
+	Tokens                  []string
+}
+
+type memoryUsage struct {
+	Used int64 `json:"used"`
+	Max  int64 `json:"max"`
+}
+
+// GetInfo reads the same information about the node that nodetool info reports
+func (c *Client) GetInfo(ctx context.Context, node *corev1.Pod) (*nodetool.Info, error) {
+	if node == nil {
+		return nil, errNoPod
+	}
+
+	requests := []request{
+		readRequest(storageServiceMBean,
+			"LocalHostId",
+			"GossipRunning",
+			"NativeTransportRunning",
+			"LoadString",
+			"CurrentGenerationNumber",
+			"Tokens",
+		),
+		readRequest(runtimeMBean, "Uptime"),
+		readRequest(memoryMBean, "HeapMemoryUsage"),
+		execRequest(endpointSnitchInfoMBean, "getDatacenter(java.lang.String)", node.Status.PodIP),
+		execRequest(endpointSnitchInfoMBean, "getRack(java.lang.String)", node.Status.PodIP),
+		readRequest(exceptionsMBean, "Count"),
+	}
+	for _, scope := range []string{"KeyCache", "RowCache", "CounterCache"} {
+		requests = append(requests, cacheRequests(scope)...)
+	}
+
+	values, err := c.do(ctx, node, c.readTimeout, requests...)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := &nodeAttributes{}
+	var uptimeMillis int64
+	heap := &memoryUsage{}
+	info := &nodetool.Info{
+		// percent repaired is not exposed through the StorageService
+		PercentRepaired: -1,
+	}
+
+	err = unmarshalAll(values[:6],
+		attrs,
+		&uptimeMillis,
+		heap,
+		&info.Datacenter,
+		&info.Rack,
+		&info.Exceptions,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// thrift is read on its own, the attribute is gone in cassandra 4 and would fail the whole read
+	info.ThriftActive, err = c.thriftRunning(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	info.ID = attrs.LocalHostID
+	info.GossipActive = attrs.GossipRunning
+	info.NativeTransportActive = attrs.NativeTransportRunning
+	info.Load = attrs.LoadString
+	info.GenerationNo = attrs.CurrentGenerationNumber
+	info.TokenCount = len(attrs.Tokens)
+	info.UptimeSeconds = uptimeMillis / 1000
+	info.HeapUsedMB = float64(heap.Used) / bytesPerMB
+	info.HeapTotalMB = float64(heap.Max) / bytesPerMB
+
+	caches := values[6:]
+	for i, cache := range []*nodetool.CacheInfo{&info.KeyCache, &info.RowCache, &info.CounterCache} {
+		*cache, err = parseCache(caches[i*cacheRequestCount : (i+1)*cacheRequestCount])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return info, nil
+}
+
+// GetHostID returns the cassandra internal host ID
+func (c *Client) GetHostID(ctx context.Context, node *corev1.Pod) (string, error) {
+	value, err := c.do(ctx, node, c.readTimeout, readRequest(storageServiceMBean, "LocalHostId"))
+	if err != nil {
+		return "", err
+	}
+
+	hostID := ""
+	err = json.Unmarshal(value[0], &hostID)
+	return hostID, err
+}
+
+const cacheRequestCount = 5
+
+func cacheRequests(scope string) []request {
+	return []request{
+		readRequest(fmt.Sprintf(cacheMBeanPattern, scope, "Entries"), "Value"),
+		readRequest(fmt.Sprintf(cacheMBeanPattern, scope, "Size"), "Value"),
+		readRequest(fmt.Sprintf(cacheMBeanPattern, scope, "Capacity"), "Value"),
+		readRequest(fmt.Sprintf(cacheMBeanPattern, scope, "Hits"), "Count"),
+		readRequest(fmt.Sprintf(cacheMBeanPattern, scope, "Requests"), "Count"),
+	}
+}
+
+func parseCache(values []json.RawMessage) (nodetool.CacheInfo, error) {
+	cache := nodetool.CacheInfo{}
+
+	var size, capacity int64
+	err := unmarshalAll(values, &cache.Entries, &size, &capacity, &cache.Hits, &cache.Requests)
+	if err != nil {
+		return cache, err
+	}
+
+	cache.Size = fmt.Sprintf("%d bytes", size)
+	cache.Capacity = fmt.Sprintf("%d bytes", capacity)
+
+	// the HitRate gauge is NaN until the first request which jolokia cannot encode
+	// as json, so the rate is derived from the hit and request counters instead
+	cache.RecentHitRate = math.NaN()
+	if cache.Requests > 0 {
+		cache.RecentHitRate = float64(cache.Hits) / float64(cache.Requests)
+	}
+
+	return cache, nil
+}
+
+func unmarshalAll(values []json.RawMessage, into ...interface{}) error {
+	if len(values) < len(into) {
+		return fmt.Errorf("expected %d jolokia values, got %d", len(into), len(values))
+	}
+
+	for i, v := range into {
+		err := json.Unmarshal(values[i], v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
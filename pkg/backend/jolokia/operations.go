@@ -0,0 +1,221 @@
+package jolokia
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	readRepairMBeanPattern = "org.apache.cassandra.metrics:type=ReadRepair,name=%s"
+	streamManagerMBean     = "org.apache.cassandra.net:type=StreamManager"
+
+	// forceKeyspaceCleanup is overloaded, jolokia needs the signature to pick one
+	forceKeyspaceCleanupOperation = "forceKeyspaceCleanup(int,java.lang.String,[Ljava.lang.String;)"
+)
+
+// streamState is the composite data of a streaming plan of the StreamManager
+type streamState struct {
+	PlanID      string        `json:"planId"`
+	Description string        `json:"description"`
+	Sessions    []sessionInfo `json:"sessions"`
+}
+
+// sessionInfo is the composite data of the session of a streaming plan with a peer
+type sessionInfo struct {
+	Peer               string          `json:"peer"`
+	ReceivingSummaries []streamSummary `json:"receivingSummaries"`
+	SendingSummaries   []streamSummary `json:"sendingSummaries"`
+	ReceivingFiles     []progressInfo  `json:"receivingFiles"`
+	SendingFiles       []progressInfo  `json:"sendingFiles"`
+}
+
+// streamSummary is the composite data of the files of a table a session streams
+type streamSummary struct {
+	Files     int64 `json:"files"`
+	TotalSize int64 `json:"totalSize"`
+}
+
+// progressInfo is the composite data of the progress of a file a session streams
+type progressInfo struct {
+	CurrentBytes int64 `json:"currentBytes"`
+	TotalBytes   int64 `json:"totalBytes"`
+}
+
+// GetNetstats reads the operation mode, the streaming sessions and read repair statistics of the node. Thread
+// pool statistics are only available through nodetool
+func (c *Client) GetNetstats(ctx context.Context, node *corev1.Pod) (*nodetool.Netstats, error) {
+	values, err := c.do(ctx, node, c.readTimeout,
+		readRequest(storageServiceMBean, "OperationMode"),
+		readRequest(fmt.Sprintf(readRepairMBeanPattern, "Attempted"), "Count"),
+		readRequest(fmt.Sprintf(readRepairMBeanPattern, "RepairedBlocking"), "Count"),
+		readRequest(fmt.Sprintf(readRepairMBeanPattern, "RepairedBackground"), "Count"),
+		readRequest(streamManagerMBean, "CurrentStreams"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	netstats := &nodetool.Netstats{}
+	var mode string
+	var streams []streamState
+	err = unmarshalAll(values,
+		&mode,
+		&netstats.AttemptedReadRepairOps,
+		&netstats.MismatchBlockingReadRepairOps,
+		&netstats.MismatchBgReadRepairOps,
+		&streams,
+	)
+	if err != nil {
+		return nil, err
+	}
+	netstats.Mode = nodetool.NodeMode(mode)
+	netstats.Streaming = len(streams) > 0
+	for _, stream := range streams {
+		for _, session := range stream.Sessions {
+			netstats.Sessions = appendStreamSession(netstats.Sessions, stream, session.Peer,
+				nodetool.StreamDirectionReceiving, session.ReceivingSummaries, session.ReceivingFiles)
+			netstats.Sessions = appendStreamSession(netstats.Sessions, stream, session.Peer,
+				nodetool.StreamDirectionSending, session.SendingSummaries, session.SendingFiles)
+		}
+	}
+
+	return netstats, nil
+}
+
+// appendStreamSession adds a direction of a session that streams files, the totals and the progress are summed
+// the way nodetool netstats does
+func appendStreamSession(sessions []nodetool.StreamSession, stream streamState, peer string,
+	direction nodetool.StreamDirection, summaries []streamSummary, files []progressInfo) []nodetool.StreamSession {
+	if len(summaries) == 0 {
+		return sessions
+	}
+
+	session := nodetool.StreamSession{
+		Operation: stream.Description,
+		PlanID:    stream.PlanID,
+		Peer:      peer,
+		Direction: direction,
+	}
+	for _, summary := range summaries {
+		session.TotalFiles += summary.Files
+		session.TotalBytes += summary.TotalSize
+	}
+	for _, file := range files {
+		session.DoneBytes += file.CurrentBytes
+		if file.CurrentBytes == file.TotalBytes {
+			session.DoneFiles++
+		}
+	}
+
+	return append(sessions, session)
+}
+
+// Drain flushes the memtables and stops accepting writes on the node in preparation for restart
+func (c *Client) Drain(ctx context.Context, node *corev1.Pod) error {
+	_, err := c.exec(ctx, node, 0, storageServiceMBean, "drain")
+	if err != nil {
+		return err
+	}
+
+	values, err := c.do(ctx, node, c.readTimeout, readRequest(storageServiceMBean, "NativeTransportRunning"))
+	if err != nil {
+		return err
+	}
+
+	var binaryRunning bool
+	err = json.Unmarshal(values[0], &binaryRunning)
+	if err != nil {
+		return err
+	}
+
+	thriftRunning, err := c.thriftRunning(ctx, node)
+	if err != nil {
+		return err
+	}
+
+	if thriftRunning || binaryRunning {
+		return errors.New("node drain failed")
+	}
+
+	return nil
+}
+
+// thriftRunning reads whether the thrift server of the node is running, cassandra 4 removed thrift and the
+// attribute with it so a missing attribute is not running
+func (c *Client) thriftRunning(ctx context.Context, node *corev1.Pod) (bool, error) {
+	values, err := c.do(ctx, node, c.readTimeout, readRequest(storageServiceMBean, "RPCServerRunning"))
+	if isAttributeNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var running bool
+	err = json.Unmarshal(values[0], &running)
+	return running, err
+}
+
+// Decommission streams the data of the node to the rest of the ring and removes it from the ring
+func (c *Client) Decommission(ctx context.Context, node *corev1.Pod) error {
+	_, err := c.exec(ctx, node, 0, storageServiceMBean, "decommission")
+	if err != nil {
+		return err
+	}
+
+	netstats, err := c.GetNetstats(ctx, node)
+	if err != nil {
+		return err
+	}
+
+	if netstats.Mode != nodetool.NodeModeDecommissioned {
+		return errors.New("node decommission failed")
+	}
+
+	return nil
+}
+
+// Stop stops the cassandra daemon on the node in preparation for restart
+func (c *Client) Stop(ctx context.Context, node *corev1.Pod) error {
+	_, err := c.exec(ctx, node, c.readTimeout, storageServiceMBean, "stopDaemon")
+	return err
+}
+
+// Cleanup removes the data of the token ranges the node no longer owns from every keyspace that is not local to
+// the node, including the replicated system keyspaces like system_auth, like nodetool cleanup does
+func (c *Client) Cleanup(ctx context.Context, node *corev1.Pod) error {
+	values, err := c.do(ctx, node, c.readTimeout, readRequest(storageServiceMBean, "NonLocalStrategyKeyspaces"))
+	if err != nil {
+		return err
+	}
+
+	var keyspaces []string
+	err = json.Unmarshal(values[0], &keyspaces)
+	if err != nil {
+		return err
+	}
+
+	for _, keyspace := range keyspaces {
+		// 0 jobs uses all the compaction threads, no tables cleans up all the tables of the keyspace
+		result, err := c.exec(ctx, node, 0, storageServiceMBean, forceKeyspaceCleanupOperation, 0, keyspace, []string{})
+		if err != nil {
+			return err
+		}
+
+		var status int
+		err = json.Unmarshal(result, &status)
+		if err != nil {
+			return err
+		}
+		if status != 0 {
+			return fmt.Errorf("cleanup of keyspace %s on pod %s failed with status %d", keyspace, node.GetName(), status)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package jolokia
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ringAttributes are the StorageService attributes that describe the ring as seen by a node
+type ringAttributes struct {
+	LiveNodes          []string
+	UnreachableNodes   []string
+	JoiningNodes       []string
+	LeavingNodes       []string
+	MovingNodes        []string
+	LoadMap            map[string]string
+	HostIDMap          map[string]string `json:"HostIdMap"`
+	Ownership          map[string]float64
+	TokenToEndpointMap map[string]string
+}
+
+// GetStatus retrieves the status of all nodes within the cassandra cluster (ring) as seen by the node,
+// it is keyed by host ID like nodetool status
+func (c *Client) GetStatus(ctx context.Context, node *corev1.Pod) (map[string]*nodetool.Status, error) {
+	attrs, err := c.readAttributes(ctx, node, storageServiceMBean,
+		"LiveNodes",
+		"UnreachableNodes",
+		"JoiningNodes",
+		"LeavingNodes",
+		"MovingNodes",
+		"LoadMap",
+		"HostIdMap",
+		"Ownership",
+		"TokenToEndpointMap",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	ring := &ringAttributes{}
+	err = json.Unmarshal(raw, ring)
+	if err != nil {
+		return nil, fmt.Errorf("invalid StorageService attributes: %v", err)
+	}
+
+	endpoints := []string{}
+	for endpoint := range ring.HostIDMap {
+		endpoints = append(endpoints, endpoint)
+	}
+
+	locations, err := c.getLocations(ctx, node, endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	ownership := map[string]float64{}
+	for address, owns := range ring.Ownership {
+		ownership[stripHostname(address)] = owns
+	}
+
+	tokenCounts := map[string]int{}
+	for _, endpoint := range ring.TokenToEndpointMap {
+		tokenCounts[endpoint]++
+	}
+
+	statuses := map[string]*nodetool.Status{}
+	for _, endpoint := range endpoints {
+		hostID := ring.HostIDMap[endpoint]
+		statuses[hostID] = &nodetool.Status{
+			Status:     endpointStatus(endpoint, ring),
+			State:      endpointState(endpoint, ring),
+			Address:    endpoint,
+			Load:       ring.LoadMap[endpoint],
+			TokenCount: tokenCounts[endpoint],
+			Owns:       float32(ownership[endpoint] * 100),
+			HostID:     hostID,
+			Rack:       locations[endpoint].rack,
+			Datacenter: locations[endpoint].datacenter,
+		}
+	}
+
+	return statuses, nil
+}
+
+type location struct {
+	datacenter string
+	rack       string
+}
+
+// getLocations asks the snitch of the node for the datacenter and rack of each endpoint
+func (c *Client) getLocations(ctx context.Context, node *corev1.Pod, endpoints []string) (map[string]location, error) {
+	locations := map[string]location{}
+	if len(endpoints) == 0 {
+		return locations, nil
+	}
+
+	requests := []request{}
+	for _, endpoint := range endpoints {
+		requests = append(requests,
+			execRequest(endpointSnitchInfoMBean, "getDatacenter(java.lang.String)", endpoint),
+			execRequest(endpointSnitchInfoMBean, "getRack(java.lang.String)", endpoint),
+		)
+	}
+
+	values, err := c.do(ctx, node, c.readTimeout, requests...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, endpoint := range endpoints {
+		loc := location{}
+		err = json.Unmarshal(values[i*2], &loc.datacenter)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(values[i*2+1], &loc.rack)
+		if err != nil {
+			return nil, err
+		}
+		locations[endpoint] = loc
+	}
+
+	return locations, nil
+}
+
+func endpointStatus(endpoint string, ring *ringAttributes) nodetool.NodeStatus {
+	if contains(ring.LiveNodes, endpoint) {
+		return nodetool.NodeStatusUp
+	}
+	if contains(ring.UnreachableNodes, endpoint) {
+		return nodetool.NodeStatusDown
+	}
+	return nodetool.NodeStatusUnknown
+}
+
+func endpointState(endpoint string, ring *ringAttributes) nodetool.NodeState {
+	switch {
+	case contains(ring.JoiningNodes, endpoint):
+		return nodetool.NodeStateJoining
+	case contains(ring.LeavingNodes, endpoint):
+		return nodetool.NodeStateLeaving
+	case contains(ring.MovingNodes, endpoint):
+		return nodetool.NodeStateMoving
+	}
+	return nodetool.NodeStateNormal
+}
+
+// stripHostname turns the InetAddress string form `hostname/10.0.0.1` into `10.0.0.1`
+func stripHostname(address string) string {
+	return address[strings.LastIndex(address, "/")+1:]
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
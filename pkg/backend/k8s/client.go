@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"context"
+
 	"github.com/operator-framework/operator-sdk/pkg/sdk"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -19,6 +21,7 @@ type Client interface {
 	List(namespace string, into sdk.Object, opts ...sdk.ListOption) error
 	Create(object sdk.Object) error
 	Update(object sdk.Object) error
-	Run(pod *corev1.Pod, containerIdx int, command []string) (string, string, error)
+	Delete(object sdk.Object, opts ...sdk.DeleteOption) error
+	Run(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error)
 	Patch(object sdk.Object, pt types.PatchType, patch []byte) (err error)
 }
@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// ExecTimeoutError is returned by Run when the context is done before the command finished, the node the
+// command ran on may be unresponsive
+type ExecTimeoutError struct {
+	Namespace string
+	Pod       string
+	Command   string
+	// Err is the error of the context
+	Err error
+}
+
+func (e *ExecTimeoutError) Error() string {
+	return fmt.Sprintf("command `%s` on pod %s/%s did not finish: %v", e.Command, e.Namespace, e.Pod, e.Err)
+}
+
+// IsExecTimeout returns true when the error is an ExecTimeoutError
+func IsExecTimeout(err error) bool {
+	_, ok := err.(*ExecTimeoutError)
+	return ok
+}
+
+// abortableUpgrader keeps the connection of an exec stream so that closing it aborts the stream, the executor of
+// client-go does not take a context
+type abortableUpgrader struct {
+	spdy.Upgrader
+
+	mu      sync.Mutex
+	conn    httpstream.Connection
+	aborted bool
+}
+
+// NewConnection creates the connection of the stream, it is closed right away when the stream was aborted while
+// it was being established
+func (u *abortableUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	conn, err := u.Upgrader.NewConnection(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.conn = conn
+	if u.aborted {
+		conn.Close()
+	}
+
+	return conn, nil
+}
+
+// abort closes the connection of the stream
+func (u *abortableUpgrader) abort() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.aborted = true
+	if u.conn != nil {
+		u.conn.Close()
+	}
+}
@@ -0,0 +1,334 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// FakeClient is an in-memory kubernetes api for tests. It behaves like the OperatorSdkClient against a real
+// api server: Get and Delete of a missing object succeed without changes, Create and Update bump the resource
+// version and reject existing or missing objects and stale resource versions, List filters by namespace and
+// label selector, and Delete removes the dependents of the object through their owner references.
+type FakeClient struct {
+	// RunCallback answers the commands executed in pods, Run fails without it
+	RunCallback func(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error)
+
+	mu sync.Mutex
+	// objects are keyed by their go type and namespace/name
+	objects map[reflect.Type]map[string]sdk.Object
+	version int
+}
+
+var _ Client = &FakeClient{}
+
+// NewFakeClient creates a new FakeClient holding the objects
+func NewFakeClient(objects ...sdk.Object) *FakeClient {
+	c := &FakeClient{
+		objects: map[reflect.Type]map[string]sdk.Object{},
+	}
+
+	for _, object := range objects {
+		if err := c.Create(object.DeepCopyObject()); err != nil {
+			panic(fmt.Sprintf("could not add %T to the fake client: %v", object, err))
+		}
+	}
+
+	return c
+}
+
+// Get copies the stored object into the object with the same name and namespace
+func (c *FakeClient) Get(into sdk.Object, opts ...sdk.GetOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, err := objectKey(into)
+	if err != nil {
+		return err
+	}
+
+	stored, ok := c.objects[objectType(into)][key]
+	if !ok {
+		return nil
+	}
+
+	copyInto(stored, into)
+	return nil
+}
+
+// List copies the objects of the type of the items of the list in the namespace, or all namespaces when it is
+// empty, that match the label selector of the list options into the list
+func (c *FakeClient) List(namespace string, into sdk.Object, opts ...sdk.ListOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	items := reflect.ValueOf(into).Elem().FieldByName("Items")
+	if !items.IsValid() || items.Kind() != reflect.Slice {
+		return fmt.Errorf("%T is not a list", into)
+	}
+
+	selector, err := labels.Parse(listSelector(opts))
+	if err != nil {
+		return err
+	}
+
+	list := reflect.MakeSlice(items.Type(), 0, 0)
+	for _, key := range sortedKeys(c.objects[items.Type().Elem()]) {
+		object := c.objects[items.Type().Elem()][key]
+		accessor, _ := meta.Accessor(object)
+		if namespace != "" && accessor.GetNamespace() != namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(accessor.GetLabels())) {
+			continue
+		}
+		list = reflect.Append(list, reflect.ValueOf(object.DeepCopyObject()).Elem())
+	}
+	items.Set(list)
+
+	return nil
+}
+
+// Create stores the object, it fails when an object with the name exists
+func (c *FakeClient) Create(object sdk.Object) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, err := objectKey(object)
+	if err != nil {
+		return err
+	}
+
+	accessor, _ := meta.Accessor(object)
+	objects, ok := c.objects[objectType(object)]
+	if !ok {
+		objects = map[string]sdk.Object{}
+		c.objects[objectType(object)] = objects
+	}
+	if _, exists := objects[key]; exists {
+		return k8serrors.NewAlreadyExists(groupResource(object), accessor.GetName())
+	}
+
+	c.version++
+	accessor.SetResourceVersion(strconv.Itoa(c.version))
+	accessor.SetUID(types.UID(fmt.Sprintf("uid-%d", c.version)))
+	accessor.SetCreationTimestamp(metav1.Now())
+	objects[key] = object.DeepCopyObject()
+
+	return nil
+}
+
+// Update replaces the stored object, it fails when the object is missing or its resource version is stale
+func (c *FakeClient) Update(object sdk.Object) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, err := objectKey(object)
+	if err != nil {
+		return err
+	}
+
+	accessor, _ := meta.Accessor(object)
+	stored, ok := c.objects[objectType(object)][key]
+	if !ok {
+		return k8serrors.NewNotFound(groupResource(object), accessor.GetName())
+	}
+
+	storedAccessor, _ := meta.Accessor(stored)
+	if accessor.GetResourceVersion() != "" && accessor.GetResourceVersion() != storedAccessor.GetResourceVersion() {
+		return k8serrors.NewConflict(groupResource(object), accessor.GetName(),
+			errors.New("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+
+	c.version++
+	accessor.SetResourceVersion(strconv.Itoa(c.version))
+	accessor.SetUID(storedAccessor.GetUID())
+	accessor.SetCreationTimestamp(storedAccessor.GetCreationTimestamp())
+	c.objects[objectType(object)][key] = object.DeepCopyObject()
+
+	return nil
+}
+
+// Delete removes the object and its dependents
+func (c *FakeClient) Delete(object sdk.Object, opts ...sdk.DeleteOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, err := objectKey(object)
+	if err != nil {
+		return err
+	}
+
+	stored, ok := c.objects[objectType(object)][key]
+	if !ok {
+		return nil
+	}
+
+	c.delete(objectType(object), key, stored)
+	return nil
+}
+
+// delete removes the object and, like the garbage collector, the objects it is an owner of
+func (c *FakeClient) delete(t reflect.Type, key string, object sdk.Object) {
+	delete(c.objects[t], key)
+
+	owner, _ := meta.Accessor(object)
+	for dependentType, objects := range c.objects {
+		for dependentKey, dependent := range objects {
+			accessor, _ := meta.Accessor(dependent)
+			for _, ref := range accessor.GetOwnerReferences() {
+				if ref.UID == owner.GetUID() {
+					c.delete(dependentType, dependentKey, dependent)
+					break
+				}
+			}
+		}
+	}
+}
+
+// Patch applies a merge patch to the stored object, strategic merge patches are applied as merge patches
+func (c *FakeClient) Patch(object sdk.Object, pt types.PatchType, patch []byte) error {
+	if pt == types.JSONPatchType {
+		return fmt.Errorf("patch type %s is not supported by the fake client", pt)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, err := objectKey(object)
+	if err != nil {
+		return err
+	}
+
+	accessor, _ := meta.Accessor(object)
+	stored, ok := c.objects[objectType(object)][key]
+	if !ok {
+		return k8serrors.NewNotFound(groupResource(object), accessor.GetName())
+	}
+
+	original, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	var document, changes interface{}
+	if err = json.Unmarshal(original, &document); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(patch, &changes); err != nil {
+		return err
+	}
+	patched, err := json.Marshal(mergePatch(document, changes))
+	if err != nil {
+		return err
+	}
+
+	result := reflect.New(objectType(object)).Interface().(sdk.Object)
+	if err = json.Unmarshal(patched, result); err != nil {
+		return err
+	}
+
+	c.version++
+	resultAccessor, _ := meta.Accessor(result)
+	resultAccessor.SetResourceVersion(strconv.Itoa(c.version))
+	c.objects[objectType(object)][key] = result
+	copyInto(result, object)
+
+	return nil
+}
+
+// Run executes the command with the RunCallback
+func (c *FakeClient) Run(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+	if c.RunCallback == nil {
+		return "", "", errors.New("the fake client can not execute commands")
+	}
+	return c.RunCallback(ctx, pod, containerIdx, command)
+}
+
+// mergePatch applies a json merge patch to the document
+func mergePatch(document, patch interface{}) interface{} {
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	documentMap, ok := document.(map[string]interface{})
+	if !ok {
+		documentMap = map[string]interface{}{}
+	}
+	for key, value := range patchMap {
+		if value == nil {
+			delete(documentMap, key)
+			continue
+		}
+		documentMap[key] = mergePatch(documentMap[key], value)
+	}
+
+	return documentMap
+}
+
+// objectType is the go type the objects are stored by, the type meta is not set on every object
+func objectType(object sdk.Object) reflect.Type {
+	return reflect.TypeOf(object).Elem()
+}
+
+func objectKey(object sdk.Object) (string, error) {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return "", err
+	}
+	if accessor.GetName() == "" {
+		return "", fmt.Errorf("%T has no name", object)
+	}
+	return accessor.GetNamespace() + "/" + accessor.GetName(), nil
+}
+
+func groupResource(object sdk.Object) schema.GroupResource {
+	return schema.GroupResource{Resource: strings.ToLower(objectType(object).Name()) + "s"}
+}
+
+func copyInto(from, into sdk.Object) {
+	reflect.ValueOf(into).Elem().Set(reflect.ValueOf(from.DeepCopyObject()).Elem())
+}
+
+// listSelector returns the label selector of the list options, the options of the sdk only set unexported
+// fields so they are read through reflection
+func listSelector(opts []sdk.ListOption) string {
+	op := &sdk.ListOp{}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	listOptionsType := reflect.TypeOf(&metav1.ListOptions{})
+	value := reflect.ValueOf(op).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Type() == listOptionsType && !field.IsNil() {
+			return field.Elem().FieldByName("LabelSelector").String()
+		}
+	}
+	return ""
+}
+
+func sortedKeys(objects map[string]sdk.Object) []string {
+	keys := make([]string, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
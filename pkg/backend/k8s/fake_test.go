@@ -0,0 +1,106 @@
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testConfigMap(name string, labels map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    labels,
+		},
+		Data: map[string]string{"key": "value"},
+	}
+}
+
+func TestFakeClient_CreateGetUpdate(t *testing.T) {
+	client := k8s.NewFakeClient()
+
+	configMap := testConfigMap("test", nil)
+	assert.NoError(t, client.Create(configMap))
+	assert.NotEmpty(t, configMap.ResourceVersion)
+	assert.True(t, k8serrors.IsAlreadyExists(client.Create(testConfigMap("test", nil))))
+
+	stored := testConfigMap("test", nil)
+	assert.NoError(t, client.Get(stored))
+	assert.Equal(t, configMap.ResourceVersion, stored.ResourceVersion)
+	assert.Equal(t, configMap.UID, stored.UID)
+
+	stored.Data["key"] = "changed"
+	assert.NoError(t, client.Update(stored))
+	assert.NotEqual(t, configMap.ResourceVersion, stored.ResourceVersion)
+
+	// the first copy is stale now
+	assert.True(t, k8serrors.IsConflict(client.Update(configMap)))
+	assert.True(t, k8serrors.IsNotFound(client.Update(testConfigMap("missing", nil))))
+
+	// like the sdk client a missing object is not an error and leaves the object as it is
+	missing := testConfigMap("missing", nil)
+	assert.NoError(t, client.Get(missing))
+	assert.Empty(t, missing.ResourceVersion)
+}
+
+func TestFakeClient_List(t *testing.T) {
+	client := k8s.NewFakeClient(
+		testConfigMap("a", map[string]string{"cluster": "one"}),
+		testConfigMap("b", map[string]string{"cluster": "two"}),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "other", Labels: map[string]string{"cluster": "one"}}},
+	)
+
+	list := &corev1.ConfigMapList{}
+	assert.NoError(t, client.List("default", list))
+	assert.Len(t, list.Items, 2)
+
+	assert.NoError(t, client.List("", list, sdk.WithListOptions(&metav1.ListOptions{LabelSelector: "cluster=one"})))
+	if assert.Len(t, list.Items, 2) {
+		assert.Equal(t, "a", list.Items[0].Name)
+		assert.Equal(t, "c", list.Items[1].Name)
+	}
+
+	// objects of other types are not listed
+	pods := &corev1.PodList{}
+	assert.NoError(t, client.List("", pods))
+	assert.Empty(t, pods.Items)
+}
+
+func TestFakeClient_DeleteRemovesDependents(t *testing.T) {
+	client := k8s.NewFakeClient()
+
+	owner := testConfigMap("owner", nil)
+	assert.NoError(t, client.Create(owner))
+	dependent := testConfigMap("dependent", nil)
+	dependent.OwnerReferences = []metav1.OwnerReference{{Kind: "ConfigMap", Name: owner.Name, UID: owner.UID}}
+	assert.NoError(t, client.Create(dependent))
+	assert.NoError(t, client.Create(testConfigMap("unrelated", nil)))
+
+	assert.NoError(t, client.Delete(owner))
+	assert.NoError(t, client.Delete(owner))
+
+	list := &corev1.ConfigMapList{}
+	assert.NoError(t, client.List("default", list))
+	if assert.Len(t, list.Items, 1) {
+		assert.Equal(t, "unrelated", list.Items[0].Name)
+	}
+}
+
+func TestFakeClient_Patch(t *testing.T) {
+	client := k8s.NewFakeClient(testConfigMap("test", map[string]string{"cluster": "one", "team": "a"}))
+
+	patched := testConfigMap("test", nil)
+	err := client.Patch(patched, types.MergePatchType, []byte(`{"metadata":{"labels":{"team":null,"env":"dev"}},"data":{"other":"value"}}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"cluster": "one", "env": "dev"}, patched.Labels)
+	assert.Equal(t, map[string]string{"key": "value", "other": "value"}, patched.Data)
+	assert.True(t, k8serrors.IsNotFound(client.Patch(testConfigMap("missing", nil), types.MergePatchType, []byte(`{}`))))
+}
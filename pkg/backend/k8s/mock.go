@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"context"
+
 	"github.com/operator-framework/operator-sdk/pkg/sdk"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -16,8 +18,9 @@ type MockClient struct {
 	GetCallback    func(into sdk.Object, opts ...sdk.GetOption) error
 	CreateCallback func(object sdk.Object) error
 	UpdateCallback func(object sdk.Object) error
+	DeleteCallback func(object sdk.Object, opts ...sdk.DeleteOption) error
 	ListCallback   func(namespace string, into sdk.Object, opts ...sdk.ListOption) error
-	RunCallback    func(pod *corev1.Pod, containerIdx int, command []string) (string, string, error)
+	RunCallback    func(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error)
 }
 
 // Patch returns mock value
@@ -52,10 +55,18 @@ func (c *MockClient) Update(object sdk.Object) error {
 	return nil
 }
 
+// Delete returns mock value
+func (c *MockClient) Delete(object sdk.Object, opts ...sdk.DeleteOption) error {
+	if c.DeleteCallback != nil {
+		return c.DeleteCallback(object, opts...)
+	}
+	return nil
+}
+
 // Run returns mock values
-func (c *MockClient) Run(pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+func (c *MockClient) Run(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
 	if c.RunCallback != nil {
-		return c.RunCallback(pod, containerIdx, command)
+		return c.RunCallback(ctx, pod, containerIdx, command)
 	}
 	return c.RunStdOut, c.RunStdErr, c.RunErr
 }
@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"k8s.io/apimachinery/pkg/types"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
 )
 
 // OperatorSdkClient is a class that allows for the operator-sdk to be injected for unit testing purposes
@@ -57,13 +59,23 @@ func (c *OperatorSdkClient) Update(object sdk.Object) error {
 	return sdk.Update(object)
 }
 
+// Delete resource in kube
+func (c *OperatorSdkClient) Delete(object sdk.Object, opts ...sdk.DeleteOption) error {
+	err := sdk.Delete(object, opts...)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
 // List resources from kube
 func (c *OperatorSdkClient) List(namespace string, into sdk.Object, opts ...sdk.ListOption) error {
 	return sdk.List(namespace, into, opts...)
 }
 
-// Run executes a command inside a container inside a pod
-func (c *OperatorSdkClient) Run(pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+// Run executes a command inside a container inside a pod, the stream is aborted and an ExecTimeoutError returned
+// when the context is done before the command finished
+func (c *OperatorSdkClient) Run(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
 	containerName := pod.Spec.Containers[containerIdx].Name
 	fullCommand := strings.Join(command, " ")
 	logrus.Debugf("Executing command `%s` on pod %s/%s:%s",
@@ -93,8 +105,12 @@ func (c *OperatorSdkClient) Run(pod *corev1.Pod, containerIdx int, command []str
 		TTY:       false,
 	}, parameterCodec)
 
-	//c.config.Timeout = 15 * time.Second
-	exec, err := remotecommand.NewSPDYExecutor(c.config, "POST", request.URL())
+	transport, upgrader, err := spdy.RoundTripperFor(c.config)
+	if err != nil {
+		return "", "", fmt.Errorf("Could not execute command on pod: %v", err)
+	}
+	abortable := &abortableUpgrader{Upgrader: upgrader}
+	exec, err := remotecommand.NewSPDYExecutorForTransports(transport, abortable, "POST", request.URL())
 	if err != nil {
 		return "", "", fmt.Errorf("Could not execute command on pod: %v", err)
 	}
@@ -102,11 +118,27 @@ func (c *OperatorSdkClient) Run(pod *corev1.Pod, containerIdx int, command []str
 	formattedString := strings.Join(command[1:], "\n")
 	stdin := strings.NewReader(formattedString)
 	var stdout, stderr bytes.Buffer
-	err = exec.Stream(remotecommand.StreamOptions{
-		Stdin:  stdin,
-		Stdout: &stdout,
-		Stderr: &stderr,
-	})
+	done := make(chan error, 1)
+	go func() {
+		done <- exec.Stream(remotecommand.StreamOptions{
+			Stdin:  stdin,
+			Stdout: &stdout,
+			Stderr: &stderr,
+		})
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// the stream returns once its connection is closed, the output is dropped
+		abortable.abort()
+		return "", "", &ExecTimeoutError{
+			Namespace: pod.GetNamespace(),
+			Pod:       pod.GetName(),
+			Command:   fullCommand,
+			Err:       ctx.Err(),
+		}
+	}
 
 	return stdout.String(), stderr.String(), err
 }
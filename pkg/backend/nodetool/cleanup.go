@@ -0,0 +1,14 @@
+package nodetool
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Cleanup triggers a nodetool cleanup on the node which removes the data of the token ranges the node no longer
+// owns after nodes joined the ring
+func (e *Executor) Cleanup(ctx context.Context, node *corev1.Pod) error {
+	_, err := e.run(ctx, node, "cleanup", []string{})
+	return err
+}
@@ -1,29 +1,41 @@
 package nodetool
 
 import (
+	"context"
 	"errors"
+
+	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 )
 
 // Decommission triggers a nodetool decommission on the node to
 // begin the process of scaling down or replacing the node
-func (e *Executor) Decommission(node *corev1.Pod) error {
-	_, err := e.run(node, "decommission", []string{})
+func (e *Executor) Decommission(ctx context.Context, node *corev1.Pod) error {
+	_, err := e.run(ctx, node, "decommission", []string{})
+	if IsNodeNotInRing(err) {
+		// an earlier decommission finished, the node already left the ring
+		logrus.Infof("Pod %s is not a member of the ring, it is already decommissioned", node.GetName())
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	hostID, err := e.GetHostID(node)
+	hostID, err := e.GetHostID(ctx, node)
 	if err != nil {
 		return err
 	}
 
-	statuses, err := e.GetStatus(node)
+	statuses, err := e.GetStatus(ctx, node)
+	if IsNodeNotInRing(err) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	if _, ok := statuses[hostID]; !ok {
+	// a decommissioned node left the ring, or is still leaving it while the ring learns about it
+	if status, ok := statuses[hostID]; ok && status.State != NodeStateLeaving {
 		return errors.New("node decommission failed")
 	}
 
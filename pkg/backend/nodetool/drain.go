@@ -1,24 +1,30 @@
 package nodetool
 
 import (
+	"context"
 	"errors"
+
+	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 )
 
 // Drain triggers the nodetool drain operation on a cassandra node
 // in preparation for restart
-func (e *Executor) Drain(node *corev1.Pod) error {
-	_, err := e.run(node, "drain", []string{})
-	if err != nil {
+func (e *Executor) Drain(ctx context.Context, node *corev1.Pod) error {
+	_, err := e.run(ctx, node, "drain", []string{})
+	if IsOperationInProgress(err) {
+		// the node is already draining or drained, the state of the transports tells whether it is done
+		logrus.Infof("Drain of pod %s is already in progress: %v", node.GetName(), err)
+	} else if err != nil {
 		return err
 	}
 
-	statusthrift, err := e.run(node, "statusthrift", []string{})
+	statusthrift, err := e.run(ctx, node, "statusthrift", []string{})
 	if err != nil {
 		return err
 	}
 
-	statusbinary, err := e.run(node, "statusbinary", []string{})
+	statusbinary, err := e.run(ctx, node, "statusbinary", []string{})
 	if err != nil {
 		return err
 	}
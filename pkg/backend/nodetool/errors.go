@@ -0,0 +1,142 @@
+package nodetool
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+)
+
+// ErrorKind classifies why a nodetool command failed
+type ErrorKind string
+
+// ErrorKinds enumerated
+const (
+	// ErrorKindJMXUnavailable means nodetool could not connect to the JMX port of the node, the JVM is not running
+	// or not responding
+	ErrorKindJMXUnavailable ErrorKind = "JMXUnavailable"
+	// ErrorKindNodeNotInRing means the node is not, or no longer, a member of the token ring
+	ErrorKindNodeNotInRing ErrorKind = "NodeNotInRing"
+	// ErrorKindOperationInProgress means the node is busy with an operation that conflicts with the command
+	ErrorKindOperationInProgress ErrorKind = "OperationInProgress"
+	// ErrorKindTimeout means the command did not finish before its timeout
+	ErrorKindTimeout ErrorKind = "Timeout"
+	// ErrorKindUnknown is any other failure
+	ErrorKindUnknown ErrorKind = "Unknown"
+)
+
+// stderrPatterns are the lower case messages nodetool and cassandra print for each kind of error. They are only
+// matched against the lines of stderr nodetool prints its errors on, after the "error:" or "nodetool:" prefix.
+var stderrPatterns = []struct {
+	kind     ErrorKind
+	patterns []string
+}{
+	{ErrorKindJMXUnavailable, []string{"failed to connect to '"}},
+	{ErrorKindNodeNotInRing, []string{"local node is not a member of the token ring yet"}},
+	{ErrorKindOperationInProgress, []string{
+		"drain is already in progress",
+		"node is still bootstrapping",
+		"data is currently moving to this node; unable to leave the ring",
+	}},
+}
+
+// errorPrefixes start the lines of stderr nodetool prints its errors on
+var errorPrefixes = []string{"error:", "nodetool:"}
+
+// Error is returned when a nodetool command fails
+type Error struct {
+	Kind    ErrorKind
+	Command string
+	// ExitCode of nodetool, -1 when the command did not exit
+	ExitCode int
+	Stderr   string
+	// Err is the error of the exec when there is one
+	Err error
+}
+
+func (e *Error) Error() string {
+	detail := strings.TrimSpace(e.Stderr)
+	if detail == "" && e.Err != nil {
+		detail = e.Err.Error()
+	}
+	return fmt.Sprintf("nodetool %s failed (%s, exit code %d): %s", e.Command, e.Kind, e.ExitCode, detail)
+}
+
+// exitStatus is implemented by the error the exec returns when the command exits with a non zero code
+type exitStatus interface {
+	ExitStatus() int
+}
+
+// classify turns the result of a nodetool command into an Error, it returns nil when the command succeeded.
+// Output on stderr of a successful command such as JVM warnings is tolerated.
+func classify(command, stderr string, err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	if k8s.IsExecTimeout(err) {
+		return &Error{Kind: ErrorKindTimeout, Command: command, ExitCode: -1, Stderr: stderr, Err: err}
+	}
+
+	exitCode := -1
+	if status, ok := err.(exitStatus); ok {
+		exitCode = status.ExitStatus()
+	}
+
+	return &Error{Kind: stderrKind(stderr), Command: command, ExitCode: exitCode, Stderr: stderr, Err: err}
+}
+
+func stderrKind(stderr string) ErrorKind {
+	for _, line := range strings.Split(strings.ToLower(stderr), "\n") {
+		message, ok := errorMessage(strings.TrimSpace(line))
+		if !ok {
+			continue
+		}
+		for _, p := range stderrPatterns {
+			for _, pattern := range p.patterns {
+				if strings.Contains(message, pattern) {
+					return p.kind
+				}
+			}
+		}
+	}
+	return ErrorKindUnknown
+}
+
+// errorMessage returns the message of a line nodetool printed an error on
+func errorMessage(line string) (string, bool) {
+	for _, prefix := range errorPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix)), true
+		}
+	}
+	return "", false
+}
+
+// KindOf returns the kind of a nodetool error, errors that were not returned by a nodetool command are unknown
+func KindOf(err error) ErrorKind {
+	if e, ok := err.(*Error); ok {
+		return e.Kind
+	}
+	return ErrorKindUnknown
+}
+
+// IsJMXUnavailable returns true when nodetool could not connect to the node
+func IsJMXUnavailable(err error) bool {
+	return KindOf(err) == ErrorKindJMXUnavailable
+}
+
+// IsNodeNotInRing returns true when the node is not a member of the ring
+func IsNodeNotInRing(err error) bool {
+	return KindOf(err) == ErrorKindNodeNotInRing
+}
+
+// IsOperationInProgress returns true when the node is busy with a conflicting operation
+func IsOperationInProgress(err error) bool {
+	return KindOf(err) == ErrorKindOperationInProgress
+}
+
+// IsTimeout returns true when the command did not finish before its timeout
+func IsTimeout(err error) bool {
+	return KindOf(err) == ErrorKindTimeout
+}
@@ -2,27 +2,206 @@ package nodetool
 
 import (
 	"bufio"
+	"context"
+	"fmt"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 )
 
+// Info is the result of the nodetool info command
+type Info struct {
+	ID                    string
+	GossipActive          bool
+	ThriftActive          bool
+	NativeTransportActive bool
+	Load                  string
+	GenerationNo          int64
+	UptimeSeconds         int64
+	HeapUsedMB            float64
+	HeapTotalMB           float64
+	OffHeapMB             float64
+	Datacenter            string
+	Rack                  string
+	Exceptions            int
+	KeyCache              CacheInfo
+	RowCache              CacheInfo
+	CounterCache          CacheInfo
+	// PercentRepaired is only reported by cassandra 3.x and later, it is -1 when not reported
+	PercentRepaired float64
+	TokenCount      int
+}
+
+// CacheInfo contains the statistics for one of the key, row or counter caches
+type CacheInfo struct {
+	Entries           int64
+	Size              string
+	Capacity          string
+	Hits              int64
+	Requests          int64
+	RecentHitRate     float64
+	SavePeriodSeconds int
+}
+
+// HeapUsedPercent returns the percentage of the total heap that is in use
+func (i *Info) HeapUsedPercent() float64 {
+	if i.HeapTotalMB == 0 {
+		return 0
+	}
+	return i.HeapUsedMB / i.HeapTotalMB * 100
+}
+
+// GetInfo triggers nodetool info which provides information about the node
+func (e *Executor) GetInfo(ctx context.Context, node *corev1.Pod) (*Info, error) {
+	output, err := e.run(ctx, node, "info", []string{})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseInfo(output)
+}
+
 // GetHostID returns the cassandra internal host ID
-func (n *Executor) GetHostID(node *corev1.Pod) (string, error) {
-	output, err := n.run(node, "info", []string{})
+func (n *Executor) GetHostID(ctx context.Context, node *corev1.Pod) (string, error) {
+	info, err := n.GetInfo(ctx, node)
 	if err != nil {
 		return "", err
 	}
 
-	nodeID := ""
+	return info.ID, nil
+}
+
+func parseInfo(output string) (*Info, error) {
+	info := &Info{
+		PercentRepaired: -1,
+	}
+
 	scanner := bufio.NewScanner(strings.NewReader(output))
 	for scanner.Scan() {
-		line := strings.Split(scanner.Text(), ":")
-		if strings.TrimSpace(line[0]) == "ID" {
-			nodeID = strings.TrimSpace(line[1])
-			break
+		line := strings.SplitN(scanner.Text(), ":", 2)
+		if len(line) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(line[0])
+		value := strings.TrimSpace(line[1])
+
+		var err error
+		switch key {
+		case "ID":
+			info.ID = value
+		case "Gossip active":
+			info.GossipActive, err = strconv.ParseBool(value)
+		case "Thrift active":
+			info.ThriftActive, err = strconv.ParseBool(value)
+		case "Native Transport active":
+			info.NativeTransportActive, err = strconv.ParseBool(value)
+		case "Load":
+			info.Load = value
+		case "Generation No":
+			info.GenerationNo, err = strconv.ParseInt(value, 10, 64)
+		case "Uptime (seconds)":
+			info.UptimeSeconds, err = strconv.ParseInt(value, 10, 64)
+		case "Heap Memory (MB)":
+			info.HeapUsedMB, info.HeapTotalMB, err = parseHeapMemory(value)
+		case "Off Heap Memory (MB)":
+			info.OffHeapMB, err = strconv.ParseFloat(value, 64)
+		case "Data Center":
+			info.Datacenter = value
+		case "Rack":
+			info.Rack = value
+		case "Exceptions":
+			info.Exceptions, err = strconv.Atoi(value)
+		case "Key Cache":
+			info.KeyCache, err = parseCacheInfo(value)
+		case "Row Cache":
+			info.RowCache, err = parseCacheInfo(value)
+		case "Counter Cache":
+			info.CounterCache, err = parseCacheInfo(value)
+		case "Percent Repaired":
+			info.PercentRepaired, err = strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		case "Token":
+			var count int
+			count, err = parseTokenCount(value)
+			info.TokenCount += count
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid format for nodetool info field '%s': %v", key, err)
+		}
+	}
+
+	return info, nil
+}
+
+// parseHeapMemory parses the used and total heap in the format `1584.43 / 6104.00`
+func parseHeapMemory(value string) (float64, float64, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected used / total, got '%s'", value)
+	}
+
+	used, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	total, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return used, total, nil
+}
+
+// parseCacheInfo parses the cache line in the format
+// `entries 0, size 0 bytes, capacity 50 MB, 0 hits, 0 requests, NaN recent hit rate, 7200 save period in seconds`
+func parseCacheInfo(value string) (CacheInfo, error) {
+	cache := CacheInfo{}
+
+	var err error
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.HasPrefix(part, "entries "):
+			cache.Entries, err = strconv.ParseInt(strings.TrimPrefix(part, "entries "), 10, 64)
+		case strings.HasPrefix(part, "size "):
+			cache.Size = strings.TrimPrefix(part, "size ")
+		case strings.HasPrefix(part, "capacity "):
+			cache.Capacity = strings.TrimPrefix(part, "capacity ")
+		case strings.HasSuffix(part, " hits"):
+			cache.Hits, err = strconv.ParseInt(strings.TrimSuffix(part, " hits"), 10, 64)
+		case strings.HasSuffix(part, " requests"):
+			cache.Requests, err = strconv.ParseInt(strings.TrimSuffix(part, " requests"), 10, 64)
+		case strings.HasSuffix(part, " recent hit rate"):
+			cache.RecentHitRate, err = strconv.ParseFloat(strings.TrimSuffix(part, " recent hit rate"), 64)
+		case strings.HasSuffix(part, " save period in seconds"):
+			cache.SavePeriodSeconds, err = strconv.Atoi(strings.TrimSuffix(part, " save period in seconds"))
+		}
+
+		if err != nil {
+			return cache, err
+		}
+	}
+
+	return cache, nil
+}
+
+// parseTokenCount parses the token count from `(invoke with -T/--tokens to see all 256 tokens)`,
+// when called with -T each token is on its own line and this returns 1 per line. Any other note in parentheses,
+// like `(node is not joined to the cluster)`, counts as no tokens.
+func parseTokenCount(value string) (int, error) {
+	if !strings.HasPrefix(value, "(") {
+		return 1, nil
+	}
+
+	fields := strings.Fields(value)
+	for i, field := range fields {
+		if field == "all" && i+1 < len(fields) {
+			return strconv.Atoi(fields[i+1])
 		}
 	}
 
-	return nodeID, nil
+	return 0, nil
 }
@@ -1,6 +1,7 @@
 package nodetool_test
 
 import (
+	"context"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
 	"testing"
 
@@ -27,6 +28,24 @@ Key Cache              : entries 1867103, size 237.81 MB, capacity 256 MB, 45455
 Row Cache              : entries 0, size 0 bytes, capacity 0 bytes, 0 hits, 0 requests, NaN recent hit rate, 0 save period in seconds
 Counter Cache          : entries 0, size 0 bytes, capacity 50 MB, 0 hits, 0 requests, NaN recent hit rate, 7200 save period in seconds
 Token                  : (invoke with -T/--tokens to see all 256 tokens)
+`
+
+	testInfoNotJoinedOutput = `ID                     : 9a1c2d3e-5f60-4b7a-8c9d-0e1f2a3b4c5d
+Gossip active          : true
+Thrift active          : false
+Native Transport active: false
+Load                   : 102.4 KB
+Generation No          : 1529817347
+Uptime (seconds)       : 312
+Heap Memory (MB)       : 412.10 / 6104.00
+Off Heap Memory (MB)   : 0.00
+Data Center            : us-central1
+Rack                   : us-central1-b
+Exceptions             : 0
+Key Cache              : entries 0, size 0 bytes, capacity 256 MB, 0 hits, 0 requests, NaN recent hit rate, 14400 save period in seconds
+Row Cache              : entries 0, size 0 bytes, capacity 0 bytes, 0 hits, 0 requests, NaN recent hit rate, 0 save period in seconds
+Counter Cache          : entries 0, size 0 bytes, capacity 50 MB, 0 hits, 0 requests, NaN recent hit rate, 7200 save period in seconds
+Token                  : (node is not joined to the cluster)
 `
 )
 
@@ -45,8 +64,123 @@ func TestGetHostID_Success(t *testing.T) {
 	}
 	obj := nodetool.NewExecutor(mockClient)
 
-	result, err := obj.GetHostID(testPod)
+	result, err := obj.GetHostID(context.Background(), testPod)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "3b920369-cd41-4b6b-8f5f-192f1202ee18", result)
 }
+
+func TestGetInfo_Success(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+	mockClient := &k8s.MockClient{
+		RunStdOut: testInfoOutput,
+	}
+	obj := nodetool.NewExecutor(mockClient)
+
+	result, err := obj.GetInfo(context.Background(), testPod)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3b920369-cd41-4b6b-8f5f-192f1202ee18", result.ID)
+	assert.True(t, result.GossipActive)
+	assert.True(t, result.ThriftActive)
+	assert.True(t, result.NativeTransportActive)
+	assert.Equal(t, "43.16 GB", result.Load)
+	assert.Equal(t, int64(1529817347), result.GenerationNo)
+	assert.Equal(t, int64(1871647), result.UptimeSeconds)
+	assert.Equal(t, 1584.43, result.HeapUsedMB)
+	assert.Equal(t, 6104.00, result.HeapTotalMB)
+	assert.Equal(t, 33.42, result.OffHeapMB)
+	assert.Equal(t, "us-central1", result.Datacenter)
+	assert.Equal(t, "us-central1-b", result.Rack)
+	assert.Equal(t, 0, result.Exceptions)
+	assert.Equal(t, int64(1867103), result.KeyCache.Entries)
+	assert.Equal(t, "237.81 MB", result.KeyCache.Size)
+	assert.Equal(t, "256 MB", result.KeyCache.Capacity)
+	assert.Equal(t, int64(45455336581), result.KeyCache.Hits)
+	assert.Equal(t, int64(45903812808), result.KeyCache.Requests)
+	assert.Equal(t, 0.990, result.KeyCache.RecentHitRate)
+	assert.Equal(t, 14400, result.KeyCache.SavePeriodSeconds)
+	assert.Equal(t, int64(0), result.RowCache.Entries)
+	assert.Equal(t, "50 MB", result.CounterCache.Capacity)
+	assert.Equal(t, 7200, result.CounterCache.SavePeriodSeconds)
+	assert.Equal(t, float64(-1), result.PercentRepaired)
+	assert.Equal(t, 256, result.TokenCount)
+}
+
+func TestGetInfo_PercentRepaired(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+	mockClient := &k8s.MockClient{
+		RunStdOut: testInfoOutput + "Percent Repaired       : 87.5%\n",
+	}
+	obj := nodetool.NewExecutor(mockClient)
+
+	result, err := obj.GetInfo(context.Background(), testPod)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 87.5, result.PercentRepaired)
+}
+
+func TestGetInfo_InvalidHeap(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+	mockClient := &k8s.MockClient{
+		RunStdOut: "Heap Memory (MB)       : 1584.43\n",
+	}
+	obj := nodetool.NewExecutor(mockClient)
+
+	result, err := obj.GetInfo(context.Background(), testPod)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestGetInfo_NotJoined(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+	mockClient := &k8s.MockClient{
+		RunStdOut: testInfoNotJoinedOutput,
+	}
+	obj := nodetool.NewExecutor(mockClient)
+
+	result, err := obj.GetInfo(context.Background(), testPod)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "9a1c2d3e-5f60-4b7a-8c9d-0e1f2a3b4c5d", result.ID)
+	assert.Equal(t, int64(312), result.UptimeSeconds)
+	assert.Equal(t, 0, result.TokenCount)
+
+	hostID, err := obj.GetHostID(context.Background(), testPod)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "9a1c2d3e-5f60-4b7a-8c9d-0e1f2a3b4c5d", hostID)
+}
@@ -0,0 +1,24 @@
+package nodetool
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodeManager is the contract for querying and managing a cassandra node. It is
+// implemented by the nodetool Executor, which execs nodetool inside the pod, and by
+// the jolokia client, which reads the MBeans directly over HTTP. The operations are aborted when the context
+// is done
+type NodeManager interface {
+	GetStatus(ctx context.Context, node *corev1.Pod) (map[string]*Status, error)
+	GetInfo(ctx context.Context, node *corev1.Pod) (*Info, error)
+	GetHostID(ctx context.Context, node *corev1.Pod) (string, error)
+	GetNetstats(ctx context.Context, node *corev1.Pod) (*Netstats, error)
+	Drain(ctx context.Context, node *corev1.Pod) error
+	Decommission(ctx context.Context, node *corev1.Pod) error
+	Stop(ctx context.Context, node *corev1.Pod) error
+	Cleanup(ctx context.Context, node *corev1.Pod) error
+}
+
+var _ NodeManager = &Executor{}
@@ -2,7 +2,9 @@ package nodetool
 
 import (
 	"bufio"
+	"context"
 	corev1 "k8s.io/api/core/v1"
+	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
@@ -12,11 +14,48 @@ const (
 	na = "n/a"
 )
 
+var (
+	// a streaming plan is printed as its description followed by its id
+	streamPlanPattern = regexp.MustCompile(`^(.+) ([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})$`)
+	// the peers of a plan are indented by 4 spaces, the address may be preceded by a hostname and followed by the
+	// address the session connects to
+	streamPeerPattern = regexp.MustCompile(`^    \S*/([^\s/]+)(?: \(using .*\))?$`)
+	// the progress of each direction of a session follows its peer
+	streamProgressPattern = regexp.MustCompile(`^(Receiving|Sending) (\d+) files, (\d+) bytes total\. Already (?:received|sent) (\d+) files, (\d+) bytes total`)
+)
+
+// StreamDirection is the direction of the files of a streaming session, seen from the node
+type StreamDirection string
+
+const (
+	// StreamDirectionReceiving files are received from the peer
+	StreamDirectionReceiving StreamDirection = "Receiving"
+	// StreamDirectionSending files are sent to the peer
+	StreamDirectionSending StreamDirection = "Sending"
+)
+
+// StreamSession is the progress of the files a node sends to or receives from a peer
+type StreamSession struct {
+	// Operation of the streaming plan, like Bootstrap, Unbootstrap, Rebuild or Repair
+	Operation  string
+	PlanID     string
+	Peer       string
+	Direction  StreamDirection
+	TotalFiles int64
+	TotalBytes int64
+	DoneFiles  int64
+	DoneBytes  int64
+}
+
 //while true; do date; diff <(nodetool -h localhost netstats) <(sleep 5 && nodetool -h localhost netstats); done
 
 // Netstats is the result of the nodetool netstats command
 type Netstats struct {
 	Mode NodeMode
+	// Streaming is true while the node sends or receives files in a streaming session
+	Streaming bool
+	// Sessions are the directions of the streaming sessions with each peer
+	Sessions []StreamSession
 	// The number of successfully completed read repair operations
 	AttemptedReadRepairOps int
 	// The number of read repair operations since server restart that blocked a query.
@@ -37,8 +76,8 @@ type ThreadPoolNetstat struct {
 }
 
 // GetNetstats triggers nodetool netstats which provides information about the host
-func (e *Executor) GetNetstats(node *corev1.Pod) (*Netstats, error) {
-	out, err := e.run(node, "netstats", []string{})
+func (e *Executor) GetNetstats(ctx context.Context, node *corev1.Pod) (*Netstats, error) {
+	out, err := e.run(ctx, node, "netstats", []string{})
 	if err != nil || out == "" {
 		return nil, err
 	}
@@ -46,12 +85,34 @@ func (e *Executor) GetNetstats(node *corev1.Pod) (*Netstats, error) {
 	scanner := bufio.NewScanner(strings.NewReader(out))
 
 	netstat := &Netstats{}
+	var operation, planID, peer string
 	for scanner.Scan() {
 		line := scanner.Text()
 		if line == "" {
 			continue
 		}
 
+		// the peers of a streaming session are followed by the files sent to or received from them
+		if match := streamPlanPattern.FindStringSubmatch(line); match != nil {
+			operation, planID, peer = match[1], match[2], ""
+			continue
+		}
+		if match := streamPeerPattern.FindStringSubmatch(line); match != nil {
+			peer = match[1]
+			continue
+		}
+		if match := streamProgressPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			session, err := parseStreamSession(match)
+			if err != nil {
+				return nil, err
+			}
+			session.Operation, session.PlanID, session.Peer = operation, planID, peer
+
+			netstat.Streaming = true
+			netstat.Sessions = append(netstat.Sessions, *session)
+			continue
+		}
+
 		splitLine := strings.Split(line, ":")
 		if len(splitLine) == 2 {
 			key := splitLine[0]
@@ -107,6 +168,19 @@ func (e *Executor) GetNetstats(node *corev1.Pod) (*Netstats, error) {
 	return netstat, nil
 }
 
+// parseStreamSession parses the totals and the progress of a direction of a streaming session
+func parseStreamSession(match []string) (*StreamSession, error) {
+	session := &StreamSession{Direction: StreamDirection(match[1])}
+	for i, value := range []*int64{&session.TotalFiles, &session.TotalBytes, &session.DoneFiles, &session.DoneBytes} {
+		parsed, err := strconv.ParseInt(match[i+2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		*value = parsed
+	}
+	return session, nil
+}
+
 func processThreadPool(line string) (*ThreadPoolNetstat, error) {
 	var err error
 
@@ -1,6 +1,7 @@
 package nodetool_test
 
 import (
+	"context"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
 	"reflect"
@@ -23,6 +24,59 @@ Small messages                  n/a         1   125497835413     76041
 Gossip messages                 n/a         0        7281116         0
 `
 
+	streaming = `
+Mode: JOINING
+Bootstrap 4c3b4fa0-8d54-11e8-9a5c-5b5d2f3c6f1e
+    /10.0.0.1
+        Receiving 12 files, 1234567 bytes total. Already received 3 files, 45678 bytes total
+            /var/lib/cassandra/data/app/users-1b2c/mc-3-big-Data.db 1234/5678 bytes(21%) received from idx:0/10.0.0.1
+    cassandra-1.cassandra/10.0.0.2 (using /192.168.0.2)
+        Receiving 4 files, 2048 bytes total. Already received 4 files, 2048 bytes total
+        Sending 1 files, 512 bytes total. Already sent 0 files, 128 bytes total
+            /var/lib/cassandra/data/app/users-1b2c/mc-5-big-Data.db 128/512 bytes(25%) sent to idx:0/10.0.0.2
+Read Repair Statistics:
+Attempted: 0
+Mismatch (Blocking): 0
+Mismatch (Background): 0
+`
+
+	streamingResult = &nodetool.Netstats{
+		Mode:      nodetool.NodeModeJoining,
+		Streaming: true,
+		Sessions: []nodetool.StreamSession{
+			{
+				Operation:  "Bootstrap",
+				PlanID:     "4c3b4fa0-8d54-11e8-9a5c-5b5d2f3c6f1e",
+				Peer:       "10.0.0.1",
+				Direction:  nodetool.StreamDirectionReceiving,
+				TotalFiles: 12,
+				TotalBytes: 1234567,
+				DoneFiles:  3,
+				DoneBytes:  45678,
+			},
+			{
+				Operation:  "Bootstrap",
+				PlanID:     "4c3b4fa0-8d54-11e8-9a5c-5b5d2f3c6f1e",
+				Peer:       "10.0.0.2",
+				Direction:  nodetool.StreamDirectionReceiving,
+				TotalFiles: 4,
+				TotalBytes: 2048,
+				DoneFiles:  4,
+				DoneBytes:  2048,
+			},
+			{
+				Operation:  "Bootstrap",
+				PlanID:     "4c3b4fa0-8d54-11e8-9a5c-5b5d2f3c6f1e",
+				Peer:       "10.0.0.2",
+				Direction:  nodetool.StreamDirectionSending,
+				TotalFiles: 1,
+				TotalBytes: 512,
+				DoneFiles:  0,
+				DoneBytes:  128,
+			},
+		},
+	}
+
 	valid1Result = &nodetool.Netstats{
 		Mode:                          nodetool.NodeModeNormal,
 		AttemptedReadRepairOps:        1177089787,
@@ -105,6 +159,23 @@ func TestExecutor_GetNetstats(t *testing.T) {
 			want:    valid1Result,
 			wantErr: false,
 		},
+		{
+			name: "Streaming",
+			args: args{
+				retVal: streaming,
+				node: &corev1.Pod{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: "cassandra",
+							},
+						},
+					},
+				},
+			},
+			want:    streamingResult,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -112,13 +183,13 @@ func TestExecutor_GetNetstats(t *testing.T) {
 				RunStdOut: tt.args.retVal,
 			}
 			e := nodetool.NewExecutor(mockClient)
-			got, err := e.GetNetstats(tt.args.node)
+			got, err := e.GetNetstats(context.Background(), tt.args.node)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Executor.GetNetstats() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Executor.GetNetstats(context.Background(), ) error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Executor.GetNetstats() = %v, want %v", got, tt.want)
+				t.Errorf("Executor.GetNetstats(context.Background(), ) = %v, want %v", got, tt.want)
 			}
 		})
 	}
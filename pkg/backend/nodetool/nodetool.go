@@ -1,38 +1,97 @@
 package nodetool
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/pantheon-systems/cassandra-operator/pkg/metrics"
+	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 )
 
 const (
 	cassandraPodName = "cassandra"
 	nodetoolFullPath = "/usr/bin/nodetool"
+
+	// DefaultCommandTimeout bounds the nodetool commands that query the node
+	DefaultCommandTimeout = 30 * time.Second
+	// DefaultDrainTimeout bounds nodetool drain which flushes all memtables
+	DefaultDrainTimeout = 15 * time.Minute
+	// DefaultDecommissionTimeout bounds nodetool decommission which streams the data of the node to the ring
+	DefaultDecommissionTimeout = 6 * time.Hour
+	// DefaultCleanupTimeout bounds nodetool cleanup which rewrites the sstables of the node
+	DefaultCleanupTimeout = 6 * time.Hour
 )
 
 // PodExecutor implements logic for executing commands inside pods
 // allows for constraint based arguments to allow unit testing
 type podExecutor interface {
-	Run(pod *corev1.Pod, containerIdx int, command []string) (string, string, error)
+	Run(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error)
 }
 
 // Executor implements the logic for executing nodetool commands inside
 // kubernetes pods
 type Executor struct {
 	executor podExecutor
+	// timeouts of the commands that take longer than the default timeout
+	timeouts       map[string]time.Duration
+	defaultTimeout time.Duration
+}
+
+// ExecutorOption is a function that sets the configuration on the Executor
+type ExecutorOption func(*Executor)
+
+// WithCommandTimeout sets the timeout of a nodetool command such as drain
+func WithCommandTimeout(command string, timeout time.Duration) ExecutorOption {
+	return func(e *Executor) {
+		e.timeouts[command] = timeout
+	}
+}
+
+// WithDefaultTimeout sets the timeout of the commands without a timeout of their own
+func WithDefaultTimeout(timeout time.Duration) ExecutorOption {
+	return func(e *Executor) {
+		e.defaultTimeout = timeout
+	}
 }
 
 // NewExecutor creates a new Nodetool for running commands on cassandra cluster
-func NewExecutor(executor podExecutor) *Executor {
-	return &Executor{
+func NewExecutor(executor podExecutor, opts ...ExecutorOption) *Executor {
+	e := &Executor{
 		executor: executor,
+		timeouts: map[string]time.Duration{
+			"drain":        DefaultDrainTimeout,
+			"decommission": DefaultDecommissionTimeout,
+			"cleanup":      DefaultCleanupTimeout,
+		},
+		defaultTimeout: DefaultCommandTimeout,
 	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// timeout returns how long the command may run
+func (n *Executor) timeout(command string) time.Duration {
+	if timeout, ok := n.timeouts[command]; ok {
+		return timeout
+	}
+	return n.defaultTimeout
 }
 
 // run executes a nodetool command on a specified pod(node), if pod is nil, the first found ready
-// pod will have the nodetool command executed instead
-func (n *Executor) run(execPod *corev1.Pod, command string, options []string) (string, error) {
+// pod will have the nodetool command executed instead. The command is aborted when the context is done or
+// its timeout passed
+func (n *Executor) run(ctx context.Context, execPod *corev1.Pod, command string, options []string) (output string, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveNodetool(command, start, err)
+	}()
+
 	if execPod == nil {
 		return "", fmt.Errorf("NodetoolExecutor requires a pod to execute on")
 	}
@@ -42,13 +101,16 @@ func (n *Executor) run(execPod *corev1.Pod, command string, options []string) (s
 		return "", fmt.Errorf("No container named %s in pod %s", cassandraPodName, execPod.GetName())
 	}
 
-	outputStdOut, outputStdErr, err := n.executor.Run(execPod, containerIdx, append([]string{nodetoolFullPath, command}, options...))
-	if err != nil {
-		return "", err
+	ctx, cancel := context.WithTimeout(ctx, n.timeout(command))
+	defer cancel()
+
+	outputStdOut, outputStdErr, err := n.executor.Run(ctx, execPod, containerIdx, append([]string{nodetoolFullPath, command}, options...))
+	if nodetoolErr := classify(command, outputStdErr, err); nodetoolErr != nil {
+		return "", nodetoolErr
 	}
 
 	if len(outputStdErr) > 0 {
-		return "", fmt.Errorf(outputStdErr)
+		logrus.Debugf("Ignoring output of nodetool %s on stderr of pod %s: %s", command, execPod.GetName(), outputStdErr)
 	}
 
 	return outputStdOut, nil
@@ -0,0 +1,292 @@
+package nodetool_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// hangingClient never finishes a command before its context is done, like a wedged JVM
+func hangingClient(deadlines map[string]time.Duration) *k8s.MockClient {
+	return &k8s.MockClient{
+		RunCallback: func(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+			deadline, _ := ctx.Deadline()
+			deadlines[command[1]] = time.Until(deadline)
+
+			<-ctx.Done()
+			return "", "", &k8s.ExecTimeoutError{Pod: pod.GetName(), Command: strings.Join(command, " "), Err: ctx.Err()}
+		},
+	}
+}
+
+func TestExecutor_CommandTimeouts(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+	deadlines := map[string]time.Duration{}
+	obj := nodetool.NewExecutor(hangingClient(deadlines),
+		nodetool.WithDefaultTimeout(10*time.Millisecond),
+		nodetool.WithCommandTimeout("drain", 50*time.Millisecond),
+	)
+
+	_, err := obj.GetInfo(context.Background(), testPod)
+	assert.True(t, nodetool.IsTimeout(err))
+
+	start := time.Now()
+	err = obj.Drain(context.Background(), testPod)
+	assert.True(t, nodetool.IsTimeout(err))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	assert.True(t, deadlines["info"] <= 10*time.Millisecond)
+	assert.True(t, deadlines["drain"] > 10*time.Millisecond)
+}
+
+func TestExecutor_Cancel(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+	obj := nodetool.NewExecutor(hangingClient(map[string]time.Duration{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	// the default timeout of decommission is hours, the cancellation aborts it
+	err := obj.Decommission(ctx, testPod)
+	assert.True(t, nodetool.IsTimeout(err))
+	assert.Equal(t, context.Canceled, err.(*nodetool.Error).Err.(*k8s.ExecTimeoutError).Err)
+}
+
+// exitError is the error the exec returns for a command that exited with a non zero code
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("command terminated with exit code %d", int(e))
+}
+
+func (e exitError) ExitStatus() int {
+	return int(e)
+}
+
+func TestExecutor_ErrorKinds(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		stderr   string
+		err      error
+		kind     nodetool.ErrorKind
+		exitCode int
+	}{
+		{
+			name:     "jmx unavailable",
+			stderr:   "nodetool: Failed to connect to '127.0.0.1:7199' - ConnectException: 'Connection refused (Connection refused)'.",
+			err:      exitError(1),
+			kind:     nodetool.ErrorKindJMXUnavailable,
+			exitCode: 1,
+		},
+		{
+			name:     "not in ring",
+			stderr:   "error: Unsupported operation: local node is not a member of the token ring yet",
+			err:      exitError(2),
+			kind:     nodetool.ErrorKindNodeNotInRing,
+			exitCode: 2,
+		},
+		{
+			name:     "in progress",
+			stderr:   "error: Node is still bootstrapping",
+			err:      exitError(1),
+			kind:     nodetool.ErrorKindOperationInProgress,
+			exitCode: 1,
+		},
+		{
+			name:     "unknown with a connection refused in the message",
+			stderr:   "error: Failed to repair: connection refused by 10.0.0.2 while another session was in progress",
+			err:      exitError(2),
+			kind:     nodetool.ErrorKindUnknown,
+			exitCode: 2,
+		},
+		{
+			name:     "unknown",
+			stderr:   "error: java.lang.OutOfMemoryError: Java heap space",
+			err:      exitError(2),
+			kind:     nodetool.ErrorKindUnknown,
+			exitCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := nodetool.NewExecutor(&k8s.MockClient{RunStdErr: tt.stderr, RunErr: tt.err})
+
+			_, err := obj.GetInfo(context.Background(), testPod)
+
+			nodetoolErr, ok := err.(*nodetool.Error)
+			if assert.True(t, ok) {
+				assert.Equal(t, tt.kind, nodetoolErr.Kind)
+				assert.Equal(t, tt.exitCode, nodetoolErr.ExitCode)
+			}
+		})
+	}
+}
+
+func TestExecutor_ToleratesStderrNoise(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+	obj := nodetool.NewExecutor(&k8s.MockClient{
+		RunStdOut: testInfoOutput,
+		RunStdErr: "OpenJDK 64-Bit Server VM warning: Cannot open file /var/log/cassandra/gc.log due to Permission denied",
+	})
+
+	result, err := obj.GetHostID(context.Background(), testPod)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3b920369-cd41-4b6b-8f5f-192f1202ee18", result)
+
+	// a successful command is not classified by its stderr
+	obj = nodetool.NewExecutor(&k8s.MockClient{
+		RunStdOut: testInfoOutput,
+		RunStdErr: "error: Node is still bootstrapping",
+	})
+
+	_, err = obj.GetHostID(context.Background(), testPod)
+
+	assert.NoError(t, err)
+}
+
+func TestExecutor_DrainInProgress(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+	obj := nodetool.NewExecutor(&k8s.MockClient{
+		RunCallback: func(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+			if command[1] == "drain" {
+				return "", "error: Drain is already in progress", exitError(2)
+			}
+			return "not running", "", nil
+		},
+	})
+
+	assert.NoError(t, obj.Drain(context.Background(), testPod))
+}
+
+func TestExecutor_DecommissionNotInRing(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+	var commands []string
+	obj := nodetool.NewExecutor(&k8s.MockClient{
+		RunCallback: func(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+			commands = append(commands, command[1])
+			return "", "error: Unsupported operation: local node is not a member of the token ring yet", exitError(2)
+		},
+	})
+
+	assert.NoError(t, obj.Decommission(context.Background(), testPod))
+	assert.Equal(t, []string{"decommission"}, commands)
+}
+
+func TestExecutor_DecommissionJMXUnavailable(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+	obj := nodetool.NewExecutor(&k8s.MockClient{
+		RunStdErr: "nodetool: Failed to connect to '127.0.0.1:7199' - ConnectException: 'Connection refused (Connection refused)'.",
+		RunErr:    exitError(1),
+	})
+
+	err := obj.Decommission(context.Background(), testPod)
+
+	assert.True(t, nodetool.IsJMXUnavailable(err))
+}
+
+func TestExecutor_Decommission(t *testing.T) {
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		status  string
+		wantErr bool
+	}{
+		{name: "left-the-ring", status: TestStatusOutput},
+		{name: "leaving-the-ring", status: strings.Replace(TestStatusOutput, "379874b8-3d69-4dce-a3a9-692fff8acd33", "3b920369-cd41-4b6b-8f5f-192f1202ee18", 1)},
+		{name: "still-in-the-ring", status: strings.Replace(TestStatusOutput, "30bfd332-9113-4e0f-b453-0e90d9a00bdc", "3b920369-cd41-4b6b-8f5f-192f1202ee18", 1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := nodetool.NewExecutor(&k8s.MockClient{
+				RunCallback: func(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+					switch command[1] {
+					case "info":
+						return testInfoOutput, "", nil
+					case "status":
+						return tt.status, "", nil
+					}
+					return "", "", nil
+				},
+			})
+
+			err := obj.Decommission(context.Background(), testPod)
+
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
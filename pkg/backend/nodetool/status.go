@@ -2,6 +2,7 @@ package nodetool
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -24,8 +25,8 @@ type Status struct {
 }
 
 // GetStatus retrieves the status of a node within the cassandra cluster (ring)
-func (n *Executor) GetStatus(node *corev1.Pod) (map[string]*Status, error) {
-	output, err := n.run(node, "status", []string{})
+func (n *Executor) GetStatus(ctx context.Context, node *corev1.Pod) (map[string]*Status, error) {
+	output, err := n.run(ctx, node, "status", []string{})
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package nodetool_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
 	"testing"
@@ -79,7 +80,7 @@ UJ  104.197.117.166  44.9 GB    256          36.5%             30bfd332-9113-4e0
 func TestGetStatus_PodNil(t *testing.T) {
 	mockClient := &k8s.MockClient{}
 	obj := nodetool.NewExecutor(mockClient)
-	statuses, err := obj.GetStatus(nil)
+	statuses, err := obj.GetStatus(context.Background(), nil)
 
 	assert.Error(t, err)
 	assert.Nil(t, statuses)
@@ -101,7 +102,7 @@ func TestGetStatus_NoCassandraContainer(t *testing.T) {
 
 	mockClient := &k8s.MockClient{}
 	obj := nodetool.NewExecutor(mockClient)
-	statuses, err := obj.GetStatus(testPod)
+	statuses, err := obj.GetStatus(context.Background(), testPod)
 
 	assert.Error(t, err)
 	assert.Nil(t, statuses)
@@ -114,7 +115,7 @@ func TestGetStatus_ExecutorError(t *testing.T) {
 		RunErr: fmt.Errorf("Some fake error"),
 	}
 	obj := nodetool.NewExecutor(mockClient)
-	statuses, err := obj.GetStatus(testPod)
+	statuses, err := obj.GetStatus(context.Background(), testPod)
 
 	assert.Error(t, err)
 	assert.Nil(t, statuses)
@@ -127,7 +128,7 @@ func TestGetStatus_ExecutorStdError(t *testing.T) {
 		RunStdErr: "Some fake error",
 	}
 	obj := nodetool.NewExecutor(mockClient)
-	statuses, err := obj.GetStatus(testPod)
+	statuses, err := obj.GetStatus(context.Background(), testPod)
 
 	assert.Error(t, err)
 	assert.Nil(t, statuses)
@@ -151,13 +152,13 @@ func TestGetStatus_InvalidFieldCount(t *testing.T) {
 		RunErr:    nil,
 	}
 	obj := nodetool.NewExecutor(mockClient)
-	statuses, err := obj.GetStatus(testPod)
+	statuses, err := obj.GetStatus(context.Background(), testPod)
 
 	assert.Error(t, err)
 	assert.Nil(t, statuses)
 
 	mockClient.RunStdOut = testMissingColStatusOutput
-	statuses, err = obj.GetStatus(testPod)
+	statuses, err = obj.GetStatus(context.Background(), testPod)
 
 	assert.Error(t, err)
 	assert.Nil(t, statuses)
@@ -181,13 +182,13 @@ func TestGetStatus_InvalidValues(t *testing.T) {
 		RunErr:    nil,
 	}
 	obj := nodetool.NewExecutor(mockClient)
-	statuses, err := obj.GetStatus(testPod)
+	statuses, err := obj.GetStatus(context.Background(), testPod)
 
 	assert.Error(t, err)
 	assert.Nil(t, statuses)
 
 	mockClient.RunStdOut = testInvalidValueOwnsStatusOutput
-	statuses, err = obj.GetStatus(testPod)
+	statuses, err = obj.GetStatus(context.Background(), testPod)
 
 	assert.Error(t, err)
 	assert.Nil(t, statuses)
@@ -211,7 +212,7 @@ func TestGetStatus_SuccessWithNewLine(t *testing.T) {
 		RunErr:    nil,
 	}
 	obj := nodetool.NewExecutor(mockClient)
-	statuses, err := obj.GetStatus(testPod)
+	statuses, err := obj.GetStatus(context.Background(), testPod)
 
 	assert.NoError(t, err)
 	assert.Len(t, statuses, 7)
@@ -1,12 +1,13 @@
 package nodetool
 
 import (
+	"context"
 	corev1 "k8s.io/api/core/v1"
 )
 
 // Stop triggers the nodetool stop operation on a cassandra node
 // in preparation for restart
-func (e *Executor) Stop(node *corev1.Pod) error {
-	_, err := e.run(node, "stop", []string{})
+func (e *Executor) Stop(ctx context.Context, node *corev1.Pod) error {
+	_, err := e.run(ctx, node, "stop", []string{})
 	return err
 }
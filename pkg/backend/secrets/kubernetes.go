@@ -0,0 +1,74 @@
+package secrets
+
+import (
+	"fmt"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KubernetesProvider implements SecretProvider with kubernetes secrets, names are secret names in the namespace
+type KubernetesProvider struct {
+	client k8s.Client
+}
+
+var _ SecretProvider = &KubernetesProvider{}
+
+// NewKubernetesProvider creates a new KubernetesProvider
+func NewKubernetesProvider(client k8s.Client) *KubernetesProvider {
+	return &KubernetesProvider{
+		client: client,
+	}
+}
+
+// Keystore returns the data of the secret
+func (p *KubernetesProvider) Keystore(namespace, name string) (map[string][]byte, error) {
+	secret, err := p.get(namespace, name)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+
+	if secret.Data == nil {
+		return map[string][]byte{}, nil
+	}
+	return secret.Data, nil
+}
+
+// Credentials returns the credentials in the username and password keys of the secret
+func (p *KubernetesProvider) Credentials(namespace, name string) (*cql.Credentials, error) {
+	secret, err := p.get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == nil {
+		return nil, fmt.Errorf("secret %s/%s does not exist", namespace, name)
+	}
+
+	return cql.NewCredentialsFromSecret(secret)
+}
+
+// get returns the secret or nil if it does not exist
+func (p *KubernetesProvider) get(namespace, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	err := p.client.Get(secret)
+	if err != nil {
+		return nil, fmt.Errorf("could not get secret %s/%s: %v", namespace, name, err)
+	}
+
+	if secret.ResourceVersion == "" {
+		return nil, nil
+	}
+	return secret, nil
+}
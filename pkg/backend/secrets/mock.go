@@ -0,0 +1,27 @@
+package secrets
+
+import (
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+)
+
+// MockProvider implements a mock of the secret provider
+type MockProvider struct {
+	KeystoreCallback    func(namespace, name string) (map[string][]byte, error)
+	CredentialsCallback func(namespace, name string) (*cql.Credentials, error)
+}
+
+// Keystore returns mock values
+func (p *MockProvider) Keystore(namespace, name string) (map[string][]byte, error) {
+	if p.KeystoreCallback != nil {
+		return p.KeystoreCallback(namespace, name)
+	}
+	return nil, nil
+}
+
+// Credentials returns mock values
+func (p *MockProvider) Credentials(namespace, name string) (*cql.Credentials, error) {
+	if p.CredentialsCallback != nil {
+		return p.CredentialsCallback(namespace, name)
+	}
+	return nil, nil
+}
@@ -0,0 +1,15 @@
+package secrets
+
+import (
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+)
+
+// SecretProvider reads the keystore material of the nodes and the credentials of the operator from where
+// they are stored
+type SecretProvider interface {
+	// Keystore returns the keystore files stored under the name keyed by file name, it returns nil if
+	// nothing is stored under the name
+	Keystore(namespace, name string) (map[string][]byte, error)
+	// Credentials returns the username and password stored under the name
+	Credentials(namespace, name string) (*cql.Credentials, error)
+}
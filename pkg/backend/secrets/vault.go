@@ -0,0 +1,309 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultVaultKVMount is the default mount of the KV version 2 secrets engine
+	DefaultVaultKVMount = "secret"
+	// DefaultVaultAuthMount is the default mount of the kubernetes auth method
+	DefaultVaultAuthMount = "kubernetes"
+	// DefaultServiceAccountTokenFile is the token of the service account the operator runs as
+	DefaultServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	vaultTokenHeader = "X-Vault-Token"
+
+	// tokenRenewMargin logs in again before the token expires so a read never races the expiry
+	tokenRenewMargin = 30 * time.Second
+)
+
+// errVaultPermissionDenied is returned when vault rejects the token, it is retried once with a new token
+var errVaultPermissionDenied = errors.New("vault denied the request")
+
+// vaultLoginRequest is the body of a login with the kubernetes auth method
+type vaultLoginRequest struct {
+	Role string `json:"role"`
+	JWT  string `json:"jwt"`
+}
+
+// vaultLoginResponse is the response of a login
+type vaultLoginResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+// vaultKVResponse is the response of a read from the KV version 2 secrets engine
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+// vaultErrorResponse is the body vault returns with an error status
+type vaultErrorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// VaultProvider implements SecretProvider with the KV version 2 secrets engine of vault, names are paths in
+// the engine and the namespace is not used. The operator logs in with the kubernetes auth method.
+type VaultProvider struct {
+	httpClient *http.Client
+	address    string
+	role       string
+	kvMount    string
+	authMount  string
+	tokenFile  string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+var _ SecretProvider = &VaultProvider{}
+
+// VaultOption is a function that sets the configuration on the VaultProvider
+type VaultOption func(*VaultProvider)
+
+// WithKVMount sets the mount of the KV version 2 secrets engine
+func WithKVMount(mount string) VaultOption {
+	return func(p *VaultProvider) {
+		p.kvMount = mount
+	}
+}
+
+// WithAuthMount sets the mount of the kubernetes auth method
+func WithAuthMount(mount string) VaultOption {
+	return func(p *VaultProvider) {
+		p.authMount = mount
+	}
+}
+
+// WithTokenFile sets the file with the service account token the operator logs in with
+func WithTokenFile(tokenFile string) VaultOption {
+	return func(p *VaultProvider) {
+		p.tokenFile = tokenFile
+	}
+}
+
+// WithVaultHTTPClient sets the http client used for requests
+func WithVaultHTTPClient(httpClient *http.Client) VaultOption {
+	return func(p *VaultProvider) {
+		p.httpClient = httpClient
+	}
+}
+
+// NewVaultProvider creates a new VaultProvider that logs in to the vault at the address with the role
+func NewVaultProvider(address, role string, opts ...VaultOption) *VaultProvider {
+	p := &VaultProvider{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		address:    strings.TrimRight(address, "/"),
+		role:       role,
+		kvMount:    DefaultVaultKVMount,
+		authMount:  DefaultVaultAuthMount,
+		tokenFile:  DefaultServiceAccountTokenFile,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Keystore returns the base64 decoded values stored at the path
+func (p *VaultProvider) Keystore(namespace, path string) (map[string][]byte, error) {
+	values, err := p.read(path)
+	if err != nil || values == nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for key, value := range values {
+		files[key], err = base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("key %s of vault secret %s is not base64 encoded: %v", key, path, err)
+		}
+	}
+
+	return files, nil
+}
+
+// Credentials returns the username and password stored at the path
+func (p *VaultProvider) Credentials(namespace, path string) (*cql.Credentials, error) {
+	values, err := p.read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if values == nil {
+		return nil, fmt.Errorf("vault secret %s does not exist", path)
+	}
+
+	for _, key := range []string{cql.UsernameKey, cql.PasswordKey} {
+		if values[key] == "" {
+			return nil, fmt.Errorf("vault secret %s is missing key '%s'", path, key)
+		}
+	}
+
+	return &cql.Credentials{
+		Username: values[cql.UsernameKey],
+		Password: values[cql.PasswordKey],
+	}, nil
+}
+
+// read returns the latest version of the secret at the path or nil if it does not exist, a token that is no
+// longer accepted is replaced once
+func (p *VaultProvider) read(path string) (map[string]string, error) {
+	values, err := p.readWithToken(path)
+	if err == errVaultPermissionDenied {
+		logrus.Debugf("Vault denied reading %s, logging in again", path)
+		p.resetToken()
+		values, err = p.readWithToken(path)
+	}
+
+	return values, err
+}
+
+func (p *VaultProvider) readWithToken(path string) (map[string]string, error) {
+	token, err := p.getToken()
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/data/%s", p.address, p.kvMount, strings.TrimLeft(path, "/"))
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(vaultTokenHeader, token)
+
+	status, body, err := p.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	case http.StatusForbidden:
+		return nil, errVaultPermissionDenied
+	default:
+		return nil, vaultError(fmt.Sprintf("reading %s", path), status, body)
+	}
+
+	response := &vaultKVResponse{}
+	err = json.Unmarshal(body, response)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault response for %s: %v", path, err)
+	}
+
+	values := map[string]string{}
+	for key, value := range response.Data.Data {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("key %s of vault secret %s is not a string", key, path)
+		}
+		values[key] = s
+	}
+
+	return values, nil
+}
+
+// getToken returns the current token and logs in when there is none or it is about to expire
+func (p *VaultProvider) getToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	jwt, err := ioutil.ReadFile(p.tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("could not read service account token: %v", err)
+	}
+
+	body, err := json.Marshal(vaultLoginRequest{Role: p.role, JWT: strings.TrimSpace(string(jwt))})
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/v1/auth/%s/login", p.address, p.authMount)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	status, respBody, err := p.do(req)
+	if err != nil {
+		return "", err
+	}
+
+	if status != http.StatusOK {
+		return "", vaultError(fmt.Sprintf("logging in as %s", p.role), status, respBody)
+	}
+
+	response := &vaultLoginResponse{}
+	err = json.Unmarshal(respBody, response)
+	if err != nil {
+		return "", fmt.Errorf("invalid vault login response: %v", err)
+	}
+
+	if response.Auth.ClientToken == "" {
+		return "", errors.New("vault login did not return a token")
+	}
+
+	logrus.Debugf("Logged in to vault as %s for %ds", p.role, response.Auth.LeaseDuration)
+	p.token = response.Auth.ClientToken
+	p.tokenExpiry = time.Now().Add(time.Duration(response.Auth.LeaseDuration)*time.Second - tokenRenewMargin)
+
+	return p.token, nil
+}
+
+func (p *VaultProvider) resetToken() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.token = ""
+}
+
+func (p *VaultProvider) do(req *http.Request) (int, []byte, error) {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not reach vault: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return resp.StatusCode, body, nil
+}
+
+// vaultError returns an error with the messages vault returned
+func vaultError(action string, status int, body []byte) error {
+	response := &vaultErrorResponse{}
+	if json.Unmarshal(body, response) == nil && len(response.Errors) > 0 {
+		return fmt.Errorf("vault returned http status %d %s: %s", status, action, strings.Join(response.Errors, ", "))
+	}
+	return fmt.Errorf("vault returned http status %d %s", status, action)
+}
@@ -0,0 +1,189 @@
+package secrets_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/secrets"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	serviceAccountJWT = "service-account-jwt"
+	vaultRole         = "cassandra-operator"
+)
+
+// fakeVault answers kubernetes auth logins and reads from a KV version 2 engine mounted at secret
+type fakeVault struct {
+	// secrets are keyed by path in the engine
+	secrets map[string]map[string]interface{}
+	// revoked tokens are denied as if they expired
+	revoked map[string]bool
+	logins  int
+}
+
+func newFakeVault() *fakeVault {
+	return &fakeVault{
+		secrets: map[string]map[string]interface{}{},
+		revoked: map[string]bool{},
+	}
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/auth/kubernetes/login":
+		login := map[string]string{}
+		err := json.NewDecoder(r.Body).Decode(&login)
+		if err != nil || login["jwt"] != serviceAccountJWT || login["role"] != vaultRole {
+			writeVaultJSON(w, http.StatusBadRequest, map[string]interface{}{"errors": []string{"invalid role or jwt"}})
+			return
+		}
+
+		f.logins++
+		writeVaultJSON(w, http.StatusOK, map[string]interface{}{
+			"auth": map[string]interface{}{
+				"client_token":   fmt.Sprintf("token-%d", f.logins),
+				"lease_duration": 3600,
+			},
+		})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+		token := r.Header.Get("X-Vault-Token")
+		if !strings.HasPrefix(token, "token-") || f.revoked[token] {
+			writeVaultJSON(w, http.StatusForbidden, map[string]interface{}{"errors": []string{"permission denied"}})
+			return
+		}
+
+		data, ok := f.secrets[strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")]
+		if !ok {
+			writeVaultJSON(w, http.StatusNotFound, map[string]interface{}{"errors": []string{}})
+			return
+		}
+
+		writeVaultJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     data,
+				"metadata": map[string]interface{}{"version": 1},
+			},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeVaultJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func newTestVaultProvider(t *testing.T, vault *fakeVault) (*secrets.VaultProvider, func()) {
+	server := httptest.NewServer(vault)
+
+	dir, err := ioutil.TempDir("", "vault")
+	assert.NoError(t, err)
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(t, ioutil.WriteFile(tokenFile, []byte(serviceAccountJWT+"\n"), 0600))
+
+	provider := secrets.NewVaultProvider(server.URL, vaultRole, secrets.WithTokenFile(tokenFile))
+	return provider, func() {
+		server.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestVaultProvider_Keystore(t *testing.T) {
+	vault := newFakeVault()
+	vault.secrets["cassandra/test-cluster/keystore"] = map[string]interface{}{
+		"keystore.jks":   base64.StdEncoding.EncodeToString([]byte{0xfe, 0xed, 0xfe, 0xed}),
+		"truststore.jks": base64.StdEncoding.EncodeToString([]byte("truststore")),
+	}
+	provider, cleanup := newTestVaultProvider(t, vault)
+	defer cleanup()
+
+	files, err := provider.Keystore("testnamespace", "cassandra/test-cluster/keystore")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xfe, 0xed, 0xfe, 0xed}, files["keystore.jks"])
+	assert.Equal(t, []byte("truststore"), files["truststore.jks"])
+
+	// the token is reused until it expires
+	_, err = provider.Keystore("testnamespace", "cassandra/test-cluster/keystore")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, vault.logins)
+}
+
+func TestVaultProvider_KeystoreNotFound(t *testing.T) {
+	provider, cleanup := newTestVaultProvider(t, newFakeVault())
+	defer cleanup()
+
+	files, err := provider.Keystore("testnamespace", "cassandra/missing")
+	assert.NoError(t, err)
+	assert.Nil(t, files)
+}
+
+func TestVaultProvider_KeystoreNotBase64(t *testing.T) {
+	vault := newFakeVault()
+	vault.secrets["cassandra/keystore"] = map[string]interface{}{"keystore.jks": "not base64!"}
+	provider, cleanup := newTestVaultProvider(t, vault)
+	defer cleanup()
+
+	_, err := provider.Keystore("testnamespace", "cassandra/keystore")
+	assert.EqualError(t, err, "key keystore.jks of vault secret cassandra/keystore is not base64 encoded: illegal base64 data at input byte 3")
+}
+
+func TestVaultProvider_Credentials(t *testing.T) {
+	vault := newFakeVault()
+	vault.secrets["cassandra/superuser"] = map[string]interface{}{"username": "admin", "password": "secret"}
+	vault.secrets["cassandra/incomplete"] = map[string]interface{}{"username": "admin"}
+	provider, cleanup := newTestVaultProvider(t, vault)
+	defer cleanup()
+
+	credentials, err := provider.Credentials("testnamespace", "cassandra/superuser")
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", credentials.Username)
+	assert.Equal(t, "secret", credentials.Password)
+
+	_, err = provider.Credentials("testnamespace", "cassandra/incomplete")
+	assert.EqualError(t, err, "vault secret cassandra/incomplete is missing key 'password'")
+
+	_, err = provider.Credentials("testnamespace", "cassandra/missing")
+	assert.EqualError(t, err, "vault secret cassandra/missing does not exist")
+}
+
+func TestVaultProvider_LogsInAgainWhenDenied(t *testing.T) {
+	vault := newFakeVault()
+	vault.secrets["cassandra/superuser"] = map[string]interface{}{"username": "admin", "password": "secret"}
+	provider, cleanup := newTestVaultProvider(t, vault)
+	defer cleanup()
+
+	_, err := provider.Credentials("testnamespace", "cassandra/superuser")
+	assert.NoError(t, err)
+
+	vault.revoked["token-1"] = true
+	credentials, err := provider.Credentials("testnamespace", "cassandra/superuser")
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", credentials.Username)
+	assert.Equal(t, 2, vault.logins)
+}
+
+func TestVaultProvider_LoginFailed(t *testing.T) {
+	server := httptest.NewServer(newFakeVault())
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "vault")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(t, ioutil.WriteFile(tokenFile, []byte("other-jwt"), 0600))
+
+	provider := secrets.NewVaultProvider(server.URL, vaultRole, secrets.WithTokenFile(tokenFile))
+	_, err = provider.Keystore("testnamespace", "cassandra/keystore")
+	assert.EqualError(t, err, "vault returned http status 400 logging in as cassandra-operator: invalid role or jwt")
+}
@@ -0,0 +1,393 @@
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultJoinDuration is how long a new node streams data before it becomes normal
+	DefaultJoinDuration = time.Minute
+	// DefaultStreamDuration is how long a decommissioned node streams its data to the ring before it leaves
+	DefaultStreamDuration = 2 * time.Minute
+
+	replicationFactor = 3
+	tokensPerNode     = 256
+	// streamFiles and streamBytes are what a node streams to or from each peer when it joins or leaves the ring
+	streamFiles = 10
+	streamBytes = 1073741824
+
+	connectionRefused = "nodetool: Failed to connect to '127.0.0.1:7199' - ConnectException: 'Connection refused (Connection refused)'."
+	notInRing         = "error: Unsupported operation: local node is not a member of the token ring yet"
+)
+
+// ExitError is returned for a nodetool command that exits with a non zero code, like the error of the exec
+type ExitError int
+
+func (e ExitError) Error() string {
+	return fmt.Sprintf("command terminated with exit code %d", int(e))
+}
+
+// ExitStatus returns the exit code of the command
+func (e ExitError) ExitStatus() int {
+	return int(e)
+}
+
+// node is a cassandra node of the ring, it runs in the pod of the same name
+type node struct {
+	pod     string
+	address string
+	hostID  string
+	// up is false when the JVM is stopped or unreachable
+	up      bool
+	state   nodetool.NodeState
+	mode    nodetool.NodeMode
+	drained bool
+	// until is when the node finishes joining or leaving the ring
+	until   time.Time
+	started time.Time
+	// cleanups counts the nodetool cleanups run on the node
+	cleanups int
+}
+
+// Ring is a simulated cassandra ring that answers the nodetool commands executed in the pods of its nodes. Time
+// only passes with Advance: new nodes join the ring after the join duration and decommissioned nodes leave it
+// after the stream duration. Run has the signature of the RunCallback of the fake kubernetes client.
+type Ring struct {
+	datacenter     string
+	rack           string
+	joinDuration   time.Duration
+	streamDuration time.Duration
+
+	mu    sync.Mutex
+	now   time.Time
+	nodes map[string]*node
+	// members are the pods of the nodes in the ring, in the order they were added
+	members []string
+	hostIDs int
+}
+
+// Option is a function that sets the configuration on the Ring
+type Option func(*Ring)
+
+// WithLocation sets the datacenter and rack of the nodes
+func WithLocation(datacenter, rack string) Option {
+	return func(r *Ring) {
+		r.datacenter = datacenter
+		r.rack = rack
+	}
+}
+
+// WithJoinDuration sets how long a new node takes to join the ring
+func WithJoinDuration(d time.Duration) Option {
+	return func(r *Ring) {
+		r.joinDuration = d
+	}
+}
+
+// WithStreamDuration sets how long a decommissioned node takes to leave the ring
+func WithStreamDuration(d time.Duration) Option {
+	return func(r *Ring) {
+		r.streamDuration = d
+	}
+}
+
+// NewRing creates an empty Ring
+func NewRing(opts ...Option) *Ring {
+	r := &Ring{
+		datacenter:     "us-central1",
+		rack:           "us-central1-a",
+		joinDuration:   DefaultJoinDuration,
+		streamDuration: DefaultStreamDuration,
+		now:            time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC),
+		nodes:          map[string]*node{},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// AddNode starts a node in the pod that joins the ring, the first node of the ring is a seed and normal at once
+func (r *Ring) AddNode(pod, address string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hostIDs++
+	n := &node{
+		pod:     pod,
+		address: address,
+		hostID:  fmt.Sprintf("00000000-0000-4000-8000-%012d", r.hostIDs),
+		up:      true,
+		state:   nodetool.NodeStateJoining,
+		mode:    nodetool.NodeModeJoining,
+		until:   r.now.Add(r.joinDuration),
+		started: r.now,
+	}
+	if len(r.members) == 0 {
+		n.state, n.mode = nodetool.NodeStateNormal, nodetool.NodeModeNormal
+	}
+
+	r.nodes[pod] = n
+	r.members = append(r.members, pod)
+}
+
+// MarkDown stops the JVM of the node, nodetool can not connect to it and the ring sees it down
+func (r *Ring) MarkDown(pod string) {
+	r.setUp(pod, false)
+}
+
+// MarkUp restarts the JVM of the node
+func (r *Ring) MarkUp(pod string) {
+	r.setUp(pod, true)
+}
+
+func (r *Ring) setUp(pod string, up bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n, ok := r.nodes[pod]; ok {
+		n.up = up
+		n.drained = false
+		n.started = r.now
+	}
+}
+
+// Advance moves the time of the ring forward, joining nodes become normal and leaving nodes leave the ring
+func (r *Ring) Advance(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.now = r.now.Add(d)
+	for _, n := range r.nodes {
+		if !n.up || r.now.Before(n.until) {
+			continue
+		}
+
+		switch n.state {
+		case nodetool.NodeStateJoining:
+			n.state, n.mode = nodetool.NodeStateNormal, nodetool.NodeModeNormal
+		case nodetool.NodeStateLeaving:
+			n.mode = nodetool.NodeModeDecommissioned
+			r.removeMember(n.pod)
+		}
+	}
+}
+
+func (r *Ring) removeMember(pod string) {
+	for i, member := range r.members {
+		if member == pod {
+			r.members = append(r.members[:i], r.members[i+1:]...)
+			return
+		}
+	}
+}
+
+// Ready returns true when the node of the pod serves requests, which the readiness probe of the pod checks
+func (r *Ring) Ready(pod string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.nodes[pod]
+	return ok && n.up && !n.drained && r.isMember(pod) &&
+		(n.state == nodetool.NodeStateNormal || n.state == nodetool.NodeStateLeaving)
+}
+
+// State returns the state of the node in the ring and whether it is a member of the ring
+func (r *Ring) State(pod string) (nodetool.NodeState, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.nodes[pod]
+	if !ok || !r.isMember(pod) {
+		return nodetool.NodeStateUnknown, false
+	}
+	return n.state, true
+}
+
+// Cleanups returns how often nodetool cleanup ran on the node
+func (r *Ring) Cleanups(pod string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n, ok := r.nodes[pod]; ok {
+		return n.cleanups
+	}
+	return 0
+}
+
+func (r *Ring) isMember(pod string) bool {
+	for _, member := range r.members {
+		if member == pod {
+			return true
+		}
+	}
+	return false
+}
+
+// Run answers a nodetool command executed in the pod
+func (r *Ring) Run(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.nodes[pod.GetName()]
+	if !ok || !n.up {
+		return "", connectionRefused, ExitError(1)
+	}
+	if len(command) < 2 {
+		return "", "error: no command", ExitError(1)
+	}
+
+	switch command[1] {
+	case "status":
+		return r.status(), "", nil
+	case "info":
+		return r.info(n), "", nil
+	case "netstats":
+		return r.netstats(n), "", nil
+	case "cleanup":
+		n.cleanups++
+		return "", "", nil
+	case "statusthrift", "statusbinary":
+		if n.drained {
+			return "not running", "", nil
+		}
+		return "running", "", nil
+	case "drain":
+		n.drained = true
+		n.mode = "DRAINED"
+		return "", "", nil
+	case "stop":
+		n.up = false
+		return "", "", nil
+	case "decommission":
+		if !r.isMember(n.pod) || n.state != nodetool.NodeStateNormal {
+			return "", notInRing, ExitError(2)
+		}
+		n.state, n.mode = nodetool.NodeStateLeaving, nodetool.NodeModeLeaving
+		n.until = r.now.Add(r.streamDuration)
+		return "", "", nil
+	}
+
+	return "", fmt.Sprintf("nodetool: Found unexpected parameters: [%s]", command[1]), ExitError(1)
+}
+
+// netstats prints nodetool netstats of the node, a joining node receives from every normal member and a leaving
+// node sends to every normal member
+func (r *Ring) netstats(n *node) string {
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "Mode: %s\n", n.mode)
+
+	streaming := false
+	for _, pod := range r.members {
+		peer := r.nodes[pod]
+		switch {
+		case n.up && peer.up && n.state == nodetool.NodeStateJoining && peer.state == nodetool.NodeStateNormal:
+			r.printSession(out, "Bootstrap", n, peer, "Receiving", "received", r.progress(n, r.joinDuration))
+		case n.up && peer.up && n.state == nodetool.NodeStateNormal && peer.state == nodetool.NodeStateJoining:
+			r.printSession(out, "Bootstrap", peer, peer, "Sending", "sent", r.progress(peer, r.joinDuration))
+		case n.up && peer.up && n.state == nodetool.NodeStateLeaving && peer.state == nodetool.NodeStateNormal:
+			r.printSession(out, "Unbootstrap", n, peer, "Sending", "sent", r.progress(n, r.streamDuration))
+		case n.up && peer.up && n.state == nodetool.NodeStateNormal && peer.state == nodetool.NodeStateLeaving:
+			r.printSession(out, "Unbootstrap", peer, peer, "Receiving", "received", r.progress(peer, r.streamDuration))
+		default:
+			continue
+		}
+		streaming = true
+	}
+	if !streaming {
+		fmt.Fprintln(out, "Not sending any streams.")
+	}
+
+	fmt.Fprintln(out, "Read Repair Statistics:")
+	fmt.Fprintln(out, "Attempted: 0")
+	fmt.Fprintln(out, "Mismatch (Blocking): 0")
+	fmt.Fprintln(out, "Mismatch (Background): 0")
+
+	return out.String()
+}
+
+// printSession prints the streaming session the node that joins or leaves the ring has with a peer
+func (r *Ring) printSession(out *bytes.Buffer, description string, moving, peer *node, direction, done string, progress float64) {
+	files := int(progress * streamFiles)
+	fmt.Fprintf(out, "%s %s\n", description, moving.hostID)
+	fmt.Fprintf(out, "    /%s\n", peer.address)
+	fmt.Fprintf(out, "        %s %d files, %d bytes total. Already %s %d files, %d bytes total\n",
+		direction, streamFiles, streamBytes, done, files, int64(progress*streamBytes))
+}
+
+// progress returns how much of the data a joining or leaving node streamed, from 0 to 1
+func (r *Ring) progress(n *node, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 1
+	}
+
+	remaining := n.until.Sub(r.now)
+	if remaining <= 0 {
+		return 1
+	}
+	if remaining > duration {
+		return 0
+	}
+	return 1 - float64(remaining)/float64(duration)
+}
+
+// status prints nodetool status of the members of the ring
+func (r *Ring) status() string {
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "Datacenter: %s\n", r.datacenter)
+	fmt.Fprintf(out, "%s\n", bytes.Repeat([]byte("="), len(r.datacenter)+12))
+	fmt.Fprintln(out, "Status=Up/Down")
+	fmt.Fprintln(out, "|/ State=Normal/Leaving/Joining/Moving")
+	fmt.Fprintln(out, "--  Address          Load       Tokens       Owns (effective)  Host ID                               Rack")
+
+	members := append([]string{}, r.members...)
+	sort.Strings(members)
+	owns := 100.0
+	if len(members) > replicationFactor {
+		owns = 100.0 * replicationFactor / float64(len(members))
+	}
+
+	for _, pod := range members {
+		n := r.nodes[pod]
+		status := "U"
+		if !n.up {
+			status = "D"
+		}
+		fmt.Fprintf(out, "%s%s  %-15s  %-9s  %-11d  %-16s  %s  %s\n",
+			status, string(n.state)[:1], n.address, "1.5 GB", tokensPerNode, fmt.Sprintf("%.1f%%", owns), n.hostID, r.rack)
+	}
+
+	return out.String()
+}
+
+// info prints nodetool info of the node
+func (r *Ring) info(n *node) string {
+	running := !n.drained
+	return fmt.Sprintf(`ID                     : %s
+Gossip active          : %t
+Thrift active          : %t
+Native Transport active: %t
+Load                   : 1.5 GB
+Generation No          : %d
+Uptime (seconds)       : %d
+Heap Memory (MB)       : 1024.00 / 4096.00
+Off Heap Memory (MB)   : 12.50
+Data Center            : %s
+Rack                   : %s
+Exceptions             : 0
+Key Cache              : entries 10, size 1 KB, capacity 100 MB, 100 hits, 110 requests, 0.909 recent hit rate, 14400 save period in seconds
+Row Cache              : entries 0, size 0 bytes, capacity 0 bytes, 0 hits, 0 requests, NaN recent hit rate, 0 save period in seconds
+Counter Cache          : entries 0, size 0 bytes, capacity 50 MB, 0 hits, 0 requests, NaN recent hit rate, 7200 save period in seconds
+Token                  : (invoke with -T/--tokens to see all %d tokens)
+`, n.hostID, running, running, running, n.started.Unix(), int64(r.now.Sub(n.started).Seconds()), r.datacenter, r.rack, tokensPerNode)
+}
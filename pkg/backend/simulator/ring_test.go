@@ -0,0 +1,137 @@
+package simulator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/simulator"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+}
+
+func newTestRing() (*simulator.Ring, *nodetool.Executor) {
+	ring := simulator.NewRing(simulator.WithJoinDuration(time.Minute), simulator.WithStreamDuration(2*time.Minute))
+	client := k8s.NewFakeClient()
+	client.RunCallback = ring.Run
+	return ring, nodetool.NewExecutor(client)
+}
+
+func TestRing_Join(t *testing.T) {
+	ring, executor := newTestRing()
+	ring.AddNode("node-0", "10.0.0.1")
+	ring.AddNode("node-1", "10.0.0.2")
+
+	statuses, err := executor.GetStatus(context.Background(), testPod("node-0"))
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+
+	info, err := executor.GetInfo(context.Background(), testPod("node-1"))
+	assert.NoError(t, err)
+	if assert.Contains(t, statuses, info.ID) {
+		assert.Equal(t, nodetool.NodeStateJoining, statuses[info.ID].State)
+		assert.Equal(t, nodetool.NodeStatusUp, statuses[info.ID].Status)
+		assert.Equal(t, "10.0.0.2", statuses[info.ID].Address)
+	}
+	assert.True(t, ring.Ready("node-0"))
+	assert.False(t, ring.Ready("node-1"))
+
+	ring.Advance(time.Minute)
+
+	statuses, err = executor.GetStatus(context.Background(), testPod("node-0"))
+	assert.NoError(t, err)
+	assert.Equal(t, nodetool.NodeStateNormal, statuses[info.ID].State)
+	assert.True(t, ring.Ready("node-1"))
+}
+
+func TestRing_Decommission(t *testing.T) {
+	ring, executor := newTestRing()
+	ring.AddNode("node-0", "10.0.0.1")
+	ring.AddNode("node-1", "10.0.0.2")
+	ring.Advance(time.Minute)
+
+	assert.NoError(t, executor.Decommission(context.Background(), testPod("node-1")))
+	state, member := ring.State("node-1")
+	assert.True(t, member)
+	assert.Equal(t, nodetool.NodeStateLeaving, state)
+
+	netstats, err := executor.GetNetstats(context.Background(), testPod("node-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, nodetool.NodeModeLeaving, netstats.Mode)
+
+	// streaming takes two minutes
+	ring.Advance(time.Minute)
+	_, member = ring.State("node-1")
+	assert.True(t, member)
+	ring.Advance(time.Minute)
+	_, member = ring.State("node-1")
+	assert.False(t, member)
+
+	netstats, err = executor.GetNetstats(context.Background(), testPod("node-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, nodetool.NodeModeDecommissioned, netstats.Mode)
+
+	statuses, err := executor.GetStatus(context.Background(), testPod("node-0"))
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 1)
+
+	// decommissioning again finds the node outside of the ring
+	assert.NoError(t, executor.Decommission(context.Background(), testPod("node-1")))
+}
+
+func TestRing_NodeDown(t *testing.T) {
+	ring, executor := newTestRing()
+	ring.AddNode("node-0", "10.0.0.1")
+	ring.AddNode("node-1", "10.0.0.2")
+	ring.Advance(time.Minute)
+
+	ring.MarkDown("node-1")
+
+	_, err := executor.GetInfo(context.Background(), testPod("node-1"))
+	assert.True(t, nodetool.IsJMXUnavailable(err))
+	assert.False(t, ring.Ready("node-1"))
+
+	statuses, err := executor.GetStatus(context.Background(), testPod("node-0"))
+	assert.NoError(t, err)
+	down := 0
+	for _, status := range statuses {
+		if status.Status == nodetool.NodeStatusDown {
+			down++
+		}
+	}
+	assert.Equal(t, 1, down)
+
+	ring.MarkUp("node-1")
+	assert.True(t, ring.Ready("node-1"))
+}
+
+func TestRing_DrainAndStop(t *testing.T) {
+	ring, executor := newTestRing()
+	ring.AddNode("node-0", "10.0.0.1")
+
+	assert.NoError(t, executor.Drain(context.Background(), testPod("node-0")))
+	assert.False(t, ring.Ready("node-0"))
+	assert.NoError(t, executor.Stop(context.Background(), testPod("node-0")))
+
+	_, err := executor.GetInfo(context.Background(), testPod("node-0"))
+	assert.True(t, nodetool.IsJMXUnavailable(err))
+}
@@ -0,0 +1,169 @@
+package certs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	keySize = 2048
+
+	certificatePEMType = "CERTIFICATE"
+	privateKeyPEMType  = "RSA PRIVATE KEY"
+
+	// clockSkew backdates certificates so they are valid on nodes whose clock is slightly behind
+	clockSkew = 5 * time.Minute
+)
+
+var serialNumberLimit = new(big.Int).Lsh(big.NewInt(1), 128)
+
+// KeyPair is a certificate and its private key
+type KeyPair struct {
+	Certificate *x509.Certificate
+	Key         *rsa.PrivateKey
+}
+
+// Authority is the certificate authority that issues the node certificates of a cluster
+type Authority struct {
+	KeyPair
+}
+
+// NewAuthority generates a self signed certificate authority
+func NewAuthority(commonName string, validity time.Duration) (*Authority, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := newTemplate(commonName, validity)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Authority{KeyPair{Certificate: cert, Key: key}}, nil
+}
+
+// ParseAuthority loads a certificate authority from its PEM encoded certificate and private key
+func ParseAuthority(certPEM, keyPEM []byte) (*Authority, error) {
+	cert, err := ParseCertificatePEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cert.IsCA {
+		return nil, fmt.Errorf("certificate %s is not a certificate authority", cert.Subject.CommonName)
+	}
+
+	key, err := ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Authority{KeyPair{Certificate: cert, Key: key}}, nil
+}
+
+// Issue signs a new certificate for the DNS names that can be used as both server and client certificate,
+// cassandra nodes are both when encrypting internode traffic
+func (a *Authority) Issue(commonName string, dnsNames []string, validity time.Duration) (*KeyPair, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := newTemplate(commonName, validity)
+	if err != nil {
+		return nil, err
+	}
+	template.DNSNames = dnsNames
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+
+	// a certificate can not outlive the authority that signed it
+	if template.NotAfter.After(a.Certificate.NotAfter) {
+		template.NotAfter = a.Certificate.NotAfter
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.Certificate, &key.PublicKey, a.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyPair{Certificate: cert, Key: key}, nil
+}
+
+// CertificatePEM returns the PEM encoded certificate
+func (k *KeyPair) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: certificatePEMType, Bytes: k.Certificate.Raw})
+}
+
+// KeyPEM returns the PEM encoded private key
+func (k *KeyPair) KeyPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMType, Bytes: x509.MarshalPKCS1PrivateKey(k.Key)})
+}
+
+// ExpiresWithin returns true if the certificate is no longer valid after the duration
+func (k *KeyPair) ExpiresWithin(now time.Time, d time.Duration) bool {
+	return !now.Add(d).Before(k.Certificate.NotAfter)
+}
+
+// ParseCertificatePEM parses the first certificate in the PEM data
+func ParseCertificatePEM(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != certificatePEMType {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// ParsePrivateKeyPEM parses the first RSA private key in the PEM data
+func ParsePrivateKeyPEM(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != privateKeyPEMType {
+		return nil, errors.New("no PEM encoded RSA private key found")
+	}
+
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+func newTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName: commonName,
+		},
+		NotBefore: now.Add(-clockSkew),
+		NotAfter:  now.Add(validity),
+	}, nil
+}
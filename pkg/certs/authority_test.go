@@ -0,0 +1,77 @@
+package certs_test
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/certs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthority_Issue(t *testing.T) {
+	ca, err := certs.NewAuthority("test-cluster-ca", 24*time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	dnsNames := []string{
+		"test-cluster-cassandra-0.test-cluster-cassandra-headless.default.svc.cluster.local",
+		"test-cluster-cassandra.default.svc.cluster.local",
+	}
+	node, err := ca.Issue("test-cluster-cassandra-0", dnsNames, 48*time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, dnsNames, node.Certificate.DNSNames)
+	assert.False(t, node.Certificate.IsCA)
+	assert.False(t, node.Certificate.NotAfter.After(ca.Certificate.NotAfter), "node certificate outlives the CA")
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Certificate)
+	for _, usage := range []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth} {
+		_, err = node.Certificate.Verify(x509.VerifyOptions{
+			DNSName:   dnsNames[1],
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{usage},
+		})
+		assert.NoError(t, err)
+	}
+}
+
+func TestParseAuthority(t *testing.T) {
+	ca, err := certs.NewAuthority("test-cluster-ca", time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	parsed, err := certs.ParseAuthority(ca.CertificatePEM(), ca.KeyPEM())
+	if assert.NoError(t, err) {
+		assert.Equal(t, ca.Certificate.Raw, parsed.Certificate.Raw)
+		assert.Equal(t, ca.Key, parsed.Key)
+	}
+
+	node, err := ca.Issue("test-cluster-cassandra-0", nil, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = certs.ParseAuthority(node.CertificatePEM(), node.KeyPEM())
+	assert.Error(t, err, "a node certificate is not an authority")
+
+	_, err = certs.ParseAuthority([]byte("garbage"), ca.KeyPEM())
+	assert.Error(t, err)
+}
+
+func TestKeyPair_ExpiresWithin(t *testing.T) {
+	ca, err := certs.NewAuthority("test-cluster-ca", 10*24*time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	now := time.Now()
+	assert.False(t, ca.ExpiresWithin(now, 9*24*time.Hour))
+	assert.True(t, ca.ExpiresWithin(now, 10*24*time.Hour))
+	assert.True(t, ca.ExpiresWithin(now.Add(11*24*time.Hour), 0))
+}
@@ -0,0 +1,183 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// The java keystore format is not covered by the standard library, it is written here following
+// sun.security.provider.JavaKeyStore and sun.security.provider.KeyProtector which every JVM can read
+const (
+	jksMagic   = 0xfeedfeed
+	jksVersion = 2
+
+	jksPrivateKeyTag  = 1
+	jksTrustedCertTag = 2
+
+	jksCertificateType = "X.509"
+	jksIntegritySalt   = "Mighty Aphrodite"
+
+	keyProtectorSaltLen = 20
+)
+
+// keyProtectorOID identifies the proprietary sun key protection algorithm
+var keyProtectorOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 42, 2, 17, 1, 1}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// EncodeKeystore returns a JKS keystore with the private key and its certificate chain under the alias
+func EncodeKeystore(password, alias string, key *rsa.PrivateKey, chain []*x509.Certificate, created time.Time) ([]byte, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("a keystore entry requires a certificate chain")
+	}
+
+	plain, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	protected, err := protectKey(password, plain)
+	if err != nil {
+		return nil, err
+	}
+
+	w := newJKSWriter(1)
+	w.writeUint32(jksPrivateKeyTag)
+	w.writeUTF(alias)
+	w.writeTimestamp(created)
+	w.writeBytes(protected)
+	w.writeUint32(uint32(len(chain)))
+	for _, cert := range chain {
+		w.writeCertificate(cert)
+	}
+
+	return w.finish(password), nil
+}
+
+// EncodeTruststore returns a JKS keystore with the certificate as a trusted entry under the alias
+func EncodeTruststore(password, alias string, cert *x509.Certificate, created time.Time) ([]byte, error) {
+	w := newJKSWriter(1)
+	w.writeUint32(jksTrustedCertTag)
+	w.writeUTF(alias)
+	w.writeTimestamp(created)
+	w.writeCertificate(cert)
+
+	return w.finish(password), nil
+}
+
+// KeystorePasswordMatches returns true if the integrity checksum of the keystore matches the password
+func KeystorePasswordMatches(password string, data []byte) bool {
+	if len(data) < sha1.Size {
+		return false
+	}
+
+	body := data[:len(data)-sha1.Size]
+	return bytes.Equal(integrityChecksum(password, body), data[len(data)-sha1.Size:])
+}
+
+// protectKey encrypts the PKCS#8 encoded key the way KeyProtector does, the key is XORed with a SHA1 keystream
+// seeded by a random salt and the password and followed by a SHA1 checksum of the plain key
+func protectKey(password string, plain []byte) ([]byte, error) {
+	salt := make([]byte, keyProtectorSaltLen)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	passwd := passwordBytes(password)
+
+	xorKey := make([]byte, 0, len(plain)+sha1.Size)
+	digest := salt
+	for len(xorKey) < len(plain) {
+		sum := sha1.Sum(append(append([]byte{}, passwd...), digest...))
+		digest = sum[:]
+		xorKey = append(xorKey, digest...)
+	}
+
+	protected := make([]byte, 0, keyProtectorSaltLen+len(plain)+sha1.Size)
+	protected = append(protected, salt...)
+	for i := range plain {
+		protected = append(protected, plain[i]^xorKey[i])
+	}
+	check := sha1.Sum(append(append([]byte{}, passwd...), plain...))
+	protected = append(protected, check[:]...)
+
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  keyProtectorOID,
+			Parameters: asn1.RawValue{Tag: asn1.TagNull},
+		},
+		EncryptedData: protected,
+	})
+}
+
+// passwordBytes encodes the password as big endian UTF-16 like a java char array
+func passwordBytes(password string) []byte {
+	b := []byte{}
+	for _, r := range password {
+		b = append(b, byte(r>>8), byte(r))
+	}
+	return b
+}
+
+type jksWriter struct {
+	buf bytes.Buffer
+}
+
+func newJKSWriter(entries int) *jksWriter {
+	w := &jksWriter{}
+	w.writeUint32(jksMagic)
+	w.writeUint32(jksVersion)
+	w.writeUint32(uint32(entries))
+	return w
+}
+
+func (w *jksWriter) writeUint32(v uint32) {
+	binary.Write(&w.buf, binary.BigEndian, v)
+}
+
+// writeUTF writes the string like DataOutputStream.writeUTF, aliases and certificate types are ASCII
+// for which modified UTF-8 and UTF-8 are the same
+func (w *jksWriter) writeUTF(s string) {
+	binary.Write(&w.buf, binary.BigEndian, uint16(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *jksWriter) writeTimestamp(t time.Time) {
+	binary.Write(&w.buf, binary.BigEndian, t.UnixNano()/int64(time.Millisecond))
+}
+
+func (w *jksWriter) writeBytes(b []byte) {
+	w.writeUint32(uint32(len(b)))
+	w.buf.Write(b)
+}
+
+func (w *jksWriter) writeCertificate(cert *x509.Certificate) {
+	w.writeUTF(jksCertificateType)
+	w.writeBytes(cert.Raw)
+}
+
+// finish appends the integrity checksum over the password, the salt and the keystore contents
+func (w *jksWriter) finish(password string) []byte {
+	w.buf.Write(integrityChecksum(password, w.buf.Bytes()))
+	return w.buf.Bytes()
+}
+
+func integrityChecksum(password string, body []byte) []byte {
+	h := sha1.New()
+	h.Write(passwordBytes(password))
+	h.Write([]byte(jksIntegritySalt))
+	h.Write(body)
+	return h.Sum(nil)
+}
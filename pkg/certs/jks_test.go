@@ -0,0 +1,234 @@
+package certs_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/certs"
+	"github.com/stretchr/testify/assert"
+)
+
+// jksEntry is a decoded keystore entry, the decoding follows sun.security.provider.JavaKeyStore
+type jksEntry struct {
+	Tag          uint32
+	Alias        string
+	Created      int64
+	ProtectedKey []byte
+	Certificates [][]byte
+}
+
+type jksReader struct {
+	*bytes.Reader
+	err error
+}
+
+func (r *jksReader) read(into interface{}) {
+	if r.err == nil {
+		r.err = binary.Read(r, binary.BigEndian, into)
+	}
+}
+
+func (r *jksReader) readN(n int) []byte {
+	b := make([]byte, n)
+	if r.err == nil && n > 0 {
+		_, r.err = io.ReadFull(r, b)
+	}
+	return b
+}
+
+func (r *jksReader) readUTF() string {
+	var l uint16
+	r.read(&l)
+	return string(r.readN(int(l)))
+}
+
+func (r *jksReader) readBytes() []byte {
+	var l uint32
+	r.read(&l)
+	return r.readN(int(l))
+}
+
+func (r *jksReader) readCertificate() []byte {
+	if certType := r.readUTF(); r.err == nil && certType != "X.509" {
+		r.err = fmt.Errorf("unexpected certificate type %s", certType)
+	}
+	return r.readBytes()
+}
+
+func decodeJKS(password string, data []byte) ([]jksEntry, error) {
+	if len(data) < sha1.Size {
+		return nil, errors.New("keystore too short")
+	}
+
+	body, digest := data[:len(data)-sha1.Size], data[len(data)-sha1.Size:]
+	h := sha1.New()
+	h.Write(utf16Password(password))
+	h.Write([]byte("Mighty Aphrodite"))
+	h.Write(body)
+	if !bytes.Equal(h.Sum(nil), digest) {
+		return nil, errors.New("keystore integrity check failed")
+	}
+
+	r := &jksReader{Reader: bytes.NewReader(body)}
+	var magic, version, count uint32
+	r.read(&magic)
+	r.read(&version)
+	r.read(&count)
+	if r.err == nil && (magic != 0xfeedfeed || version != 2) {
+		return nil, fmt.Errorf("not a JKS v2 keystore: %x %d", magic, version)
+	}
+
+	entries := []jksEntry{}
+	for i := uint32(0); i < count && r.err == nil; i++ {
+		e := jksEntry{}
+		r.read(&e.Tag)
+		e.Alias = r.readUTF()
+		r.read(&e.Created)
+
+		switch e.Tag {
+		case 1:
+			e.ProtectedKey = r.readBytes()
+			var chain uint32
+			r.read(&chain)
+			for j := uint32(0); j < chain && r.err == nil; j++ {
+				e.Certificates = append(e.Certificates, r.readCertificate())
+			}
+		case 2:
+			e.Certificates = append(e.Certificates, r.readCertificate())
+		default:
+			return nil, fmt.Errorf("unknown entry tag %d", e.Tag)
+		}
+		entries = append(entries, e)
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("trailing data in keystore")
+	}
+
+	return entries, nil
+}
+
+// recoverKey reverses sun.security.provider.KeyProtector
+func recoverKey(password string, protectedKey []byte) ([]byte, error) {
+	info := struct {
+		Algorithm     pkix.AlgorithmIdentifier
+		EncryptedData []byte
+	}{}
+	_, err := asn1.Unmarshal(protectedKey, &info)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Algorithm.Algorithm.Equal(asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 42, 2, 17, 1, 1}) {
+		return nil, fmt.Errorf("unexpected key protection algorithm %v", info.Algorithm.Algorithm)
+	}
+	if len(info.EncryptedData) < 40 {
+		return nil, errors.New("protected key too short")
+	}
+
+	passwd := utf16Password(password)
+	salt := info.EncryptedData[:20]
+	encrypted := info.EncryptedData[20 : len(info.EncryptedData)-20]
+	check := info.EncryptedData[len(info.EncryptedData)-20:]
+
+	plain := make([]byte, len(encrypted))
+	digest := salt
+	for i := 0; i < len(encrypted); i += 20 {
+		sum := sha1.Sum(append(append([]byte{}, passwd...), digest...))
+		digest = sum[:]
+		for j := 0; j < 20 && i+j < len(encrypted); j++ {
+			plain[i+j] = encrypted[i+j] ^ digest[j]
+		}
+	}
+
+	sum := sha1.Sum(append(append([]byte{}, passwd...), plain...))
+	if !bytes.Equal(sum[:], check) {
+		return nil, errors.New("wrong password or corrupted key")
+	}
+
+	return plain, nil
+}
+
+func utf16Password(password string) []byte {
+	b := []byte{}
+	for _, r := range password {
+		b = append(b, byte(r>>8), byte(r))
+	}
+	return b
+}
+
+func TestEncodeKeystore(t *testing.T) {
+	ca, err := certs.NewAuthority("test-cluster-ca", time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+	node, err := ca.Issue("test-cluster-cassandra-0", nil, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	created := time.Unix(1500000000, 0)
+	data, err := certs.EncodeKeystore("changeit", "cassandra", node.Key, []*x509.Certificate{node.Certificate, ca.Certificate}, created)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = decodeJKS("wrong", data)
+	assert.Error(t, err, "integrity check passed with the wrong password")
+
+	entries, err := decodeJKS("changeit", data)
+	if !assert.NoError(t, err) || !assert.Len(t, entries, 1) {
+		return
+	}
+	assert.Equal(t, uint32(1), entries[0].Tag)
+	assert.Equal(t, "cassandra", entries[0].Alias)
+	assert.Equal(t, int64(1500000000000), entries[0].Created)
+	assert.Equal(t, [][]byte{node.Certificate.Raw, ca.Certificate.Raw}, entries[0].Certificates)
+
+	plain, err := recoverKey("changeit", entries[0].ProtectedKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+	key, err := x509.ParsePKCS8PrivateKey(plain)
+	if assert.NoError(t, err) {
+		assert.Equal(t, node.Key, key)
+	}
+
+	_, err = certs.EncodeKeystore("changeit", "cassandra", node.Key, nil, created)
+	assert.Error(t, err, "a key entry requires a chain")
+}
+
+func TestEncodeTruststore(t *testing.T) {
+	ca, err := certs.NewAuthority("test-cluster-ca", time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	data, err := certs.EncodeTruststore("changeit", "ca", ca.Certificate, time.Unix(1500000000, 0))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	entries, err := decodeJKS("changeit", data)
+	if !assert.NoError(t, err) || !assert.Len(t, entries, 1) {
+		return
+	}
+	assert.Equal(t, uint32(2), entries[0].Tag)
+	assert.Equal(t, "ca", entries[0].Alias)
+	assert.Equal(t, [][]byte{ca.Certificate.Raw}, entries[0].Certificates)
+
+	assert.True(t, certs.KeystorePasswordMatches("changeit", data))
+	assert.False(t, certs.KeystorePasswordMatches("wrong", data))
+	assert.False(t, certs.KeystorePasswordMatches("changeit", nil))
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	superuserName                   = "admin"
+	superuserSecretNameTemplate     = "%s-cassandra-superuser"
+	superuserBootstrappedAnnotation = "database.panth.io/superuser-bootstrapped"
+	systemAuthKeyspace              = "system_auth"
+)
+
+// authEnabled returns true if the operator manages authentication for the cluster
+func authEnabled(cc *v1alpha1.CassandraCluster) bool {
+	return cc.Spec.Auth != nil && cc.Spec.Auth.Enabled
+}
+
+// authSecretName returns the name of the secret with the credentials the operator logs in with, it is
+// empty when the operator connects without credentials. Credentials read from vault are copied into a secret.
+func authSecretName(cc *v1alpha1.CassandraCluster) string {
+	if resource.VaultCredentials(cc) {
+		return resource.VaultCredentialsSecretName(cc)
+	}
+	if cc.Spec.Auth == nil {
+		return ""
+	}
+	if cc.Spec.Auth.SecretName != "" {
+		return cc.Spec.Auth.SecretName
+	}
+	if cc.Spec.Auth.Enabled {
+		return fmt.Sprintf(superuserSecretNameTemplate, cc.GetName())
+	}
+	return ""
+}
+
+// convergeSuperuserSecret generates the credentials of the superuser that replaces the default one
+func (c *ClusterController) convergeSuperuserSecret() error {
+	logrus.Debugln("Converging superuser secret")
+	_, err := resource.NewCredentialsSecret(
+		authSecretName(c.cluster),
+		c.cluster.GetNamespace(),
+		superuserName,
+		map[string]string{"cluster": c.cluster.GetName()},
+		resource.OwnerOf(c.cluster.TypeMeta, c.cluster.ObjectMeta),
+	).Reconcile(c.driver)
+
+	return err
+}
+
+// convergeSuperuser replaces the default cassandra superuser with the one in the superuser secret, this is done once
+// and recorded with an annotation on the secret
+func (c *ClusterController) convergeSuperuser() error {
+	secret := &corev1.Secret{
+		TypeMeta: resource.GetSecretTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      authSecretName(c.cluster),
+			Namespace: c.cluster.GetNamespace(),
+		},
+	}
+	err := c.driver.Get(secret)
+	if err != nil {
+		return err
+	}
+
+	if secret.Annotations[superuserBootstrappedAnnotation] == "true" {
+		return nil
+	}
+
+	credentials, err := cql.NewCredentialsFromSecret(secret)
+	if err != nil {
+		return err
+	}
+
+	if credentials.Username == cql.DefaultSuperuser {
+		return fmt.Errorf("the superuser of cluster %s can not be the default '%s' superuser", c.cluster.GetName(), cql.DefaultSuperuser)
+	}
+
+	// a previous bootstrap may have finished without being able to record it
+	if !c.defaultSuperuserDisabled(credentials) {
+		logrus.Infof("Replacing the default superuser of cluster %s with %s", c.cluster.GetName(), credentials.Username)
+		err = c.createSuperuser(credentials)
+		if err != nil {
+			return err
+		}
+
+		err = c.disableDefaultSuperuser(credentials)
+		if err != nil {
+			return err
+		}
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[superuserBootstrappedAnnotation] = "true"
+
+	return c.driver.Update(secret)
+}
+
+// createSuperuser logs in as the default superuser to create the new superuser and replicate system_auth
+// so logging in does not depend on a single node
+func (c *ClusterController) createSuperuser(credentials *cql.Credentials) error {
+	session, err := connectAs(c.driver, c.cqlConnector, c.cluster, cql.DefaultCredentials)
+	if err != nil {
+		return fmt.Errorf("could not log in as the default superuser of cluster %s: %v", c.cluster.GetName(), err)
+	}
+	defer session.Close()
+
+	err = session.CreateRole(cql.Role{Name: credentials.Username, Login: true, Superuser: true}, credentials.Password)
+	if err != nil {
+		return err
+	}
+
+	local, err := session.Local()
+	if err != nil {
+		return err
+	}
+
+	replication := map[string]int{local.Datacenter: defaultReplication(c.cluster.Spec.Size)}
+	err = session.EnsureKeyspace(systemAuthKeyspace, replication, true)
+	if err != nil {
+		return err
+	}
+
+	if c.cluster.Spec.Repair == nil || c.cluster.Spec.Repair.Image == "" {
+		logrus.Warnf("Cluster %s has no repair image, %s has to be repaired by hand", c.cluster.GetName(), systemAuthKeyspace)
+		return nil
+	}
+
+	// system_auth is replicated once in the life of the cluster
+	_, err = resource.NewKeyspaceRepairJob(c.cluster, systemAuthKeyspace, string(c.cluster.GetUID())).Reconcile(c.driver)
+	return err
+}
+
+// disableDefaultSuperuser logs in as the new superuser, a role can not alter its own superuser status
+func (c *ClusterController) disableDefaultSuperuser(credentials *cql.Credentials) error {
+	session, err := connectAs(c.driver, c.cqlConnector, c.cluster, credentials)
+	if err != nil {
+		return fmt.Errorf("could not log in as %s on cluster %s: %v", credentials.Username, c.cluster.GetName(), err)
+	}
+	defer session.Close()
+
+	password, err := cql.GeneratePassword()
+	if err != nil {
+		return err
+	}
+
+	return session.AlterRole(cql.Role{Name: cql.DefaultSuperuser}, password)
+}
+
+// defaultSuperuserDisabled returns true if the new superuser can log in and the default superuser can not
+func (c *ClusterController) defaultSuperuserDisabled(credentials *cql.Credentials) bool {
+	session, err := connectAs(c.driver, c.cqlConnector, c.cluster, credentials)
+	if err != nil {
+		return false
+	}
+	defer session.Close()
+
+	role, err := session.GetRole(cql.DefaultSuperuser)
+	if err != nil {
+		return false
+	}
+
+	return role == nil || !role.Login
+}
@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ringStatusReporter is the part of the node backend needed to find the live replicas in each datacenter
+type ringStatusReporter interface {
+	GetStatus(ctx context.Context, node *corev1.Pod) (map[string]*nodetool.Status, error)
+}
+
+// KeyspaceController reconciles CassandraKeyspace resources over CQL
+type KeyspaceController struct {
+	k8sDriver    k8s.Client
+	cqlConnector cql.Connector
+	ringReporter ringStatusReporter
+}
+
+// NewKeyspaceController builds a new KeyspaceController
+func NewKeyspaceController(k8sDriver k8s.Client, cqlConnector cql.Connector, ringReporter ringStatusReporter) *KeyspaceController {
+	return &KeyspaceController{
+		k8sDriver:    k8sDriver,
+		cqlConnector: cqlConnector,
+		ringReporter: ringReporter,
+	}
+}
+
+// Sync creates or alters the keyspace to match the spec and records the outcome in the status
+func (c *KeyspaceController) Sync(ctx context.Context, ks *v1alpha1.CassandraKeyspace) error {
+	status := ks.Status.DeepCopy()
+
+	err := c.sync(ctx, ks, status)
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(status, &ks.Status) {
+		return nil
+	}
+
+	return c.writeStatus(ks, status)
+}
+
+// writeStatus records the status on the keyspace resource
+func (c *KeyspaceController) writeStatus(ks *v1alpha1.CassandraKeyspace, status *v1alpha1.KeyspaceStatus) error {
+	status.DeepCopyInto(&ks.Status)
+	return c.k8sDriver.Update(ks)
+}
+
+func (c *KeyspaceController) sync(ctx context.Context, ks *v1alpha1.CassandraKeyspace, status *v1alpha1.KeyspaceStatus) error {
+	keyspace := ks.GetKeyspaceName()
+
+	err := cql.ValidateIdentifier(keyspace)
+	if err != nil {
+		setKeyspacePhase(status, v1alpha1.KeyspacePhaseRejected, err.Error())
+		return nil
+	}
+
+	if len(ks.Spec.Replication) == 0 {
+		setKeyspacePhase(status, v1alpha1.KeyspacePhaseRejected, "replication must list at least one datacenter")
+		return nil
+	}
+
+	cluster := &v1alpha1.CassandraCluster{
+		TypeMeta: resource.GetCassandraClusterTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ks.Spec.Cluster,
+			Namespace: ks.GetNamespace(),
+		},
+	}
+	err = c.k8sDriver.Get(cluster)
+	if err != nil {
+		return err
+	}
+
+	if cluster.ResourceVersion == "" {
+		setKeyspacePhase(status, v1alpha1.KeyspacePhasePending, fmt.Sprintf("cluster %s does not exist", ks.Spec.Cluster))
+		return nil
+	}
+
+	if cluster.Spec.Paused {
+		setKeyspacePhase(status, v1alpha1.KeyspacePhasePending, fmt.Sprintf("cluster %s is paused", cluster.GetName()))
+		return nil
+	}
+
+	if cluster.Status.Phase != v1alpha1.ClusterPhaseRunning {
+		setKeyspacePhase(status, v1alpha1.KeyspacePhasePending, fmt.Sprintf("waiting for cluster %s to be running", cluster.GetName()))
+		return nil
+	}
+
+	if keyspace == clusterKeyspace(cluster) {
+		setKeyspacePhase(status, v1alpha1.KeyspacePhaseRejected, fmt.Sprintf("keyspace %s is managed by cluster %s, set its replication on the cluster", keyspace, cluster.GetName()))
+		return nil
+	}
+
+	session, err := connectCluster(c.k8sDriver, c.cqlConnector, cluster)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	current, err := session.GetKeyspace(keyspace)
+	if err != nil {
+		return err
+	}
+
+	if current != nil {
+		live, err := c.getLiveNodes(ctx, cluster)
+		if err != nil {
+			return err
+		}
+
+		err = checkReplicationReduction(current.Replication, ks.Spec.Replication, live)
+		if err != nil {
+			setKeyspacePhase(status, v1alpha1.KeyspacePhaseRejected, err.Error())
+			return nil
+		}
+	}
+
+	// the replication last applied by this resource is the baseline for detecting an increase, the repair is
+	// recorded before the keyspace is altered so it is started by a later sync if starting it fails
+	if status.PendingRepair == "" && status.Replication != nil && replicationIncreased(status.Replication, ks.Spec.Replication) {
+		status.PendingRepair = fmt.Sprintf("%s-%d", ks.GetUID(), ks.GetGeneration())
+		err = c.writeStatus(ks, status)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = session.EnsureKeyspace(keyspace, ks.Spec.Replication, ks.GetDurableWrites())
+	if err != nil {
+		return err
+	}
+
+	message := ""
+	switch {
+	case status.PendingRepair == "":
+	case cluster.Spec.Repair == nil || cluster.Spec.Repair.Image == "":
+		logrus.Warnf("Cluster %s has no repair image, %s has to be repaired by hand", cluster.GetName(), keyspace)
+		message = fmt.Sprintf("replication was increased, cluster %s has no repair image so the keyspace must be repaired by hand", cluster.GetName())
+		status.PendingRepair = ""
+	default:
+		logrus.Infof("Replication of keyspace %s was increased, starting repair", keyspace)
+		job, err := resource.NewKeyspaceRepairJob(cluster, keyspace, status.PendingRepair).Reconcile(c.k8sDriver)
+		if err != nil {
+			return fmt.Errorf("could not start repair of keyspace %s: %v", keyspace, err)
+		}
+		status.RepairJob = job.(metav1.Object).GetName()
+		status.PendingRepair = ""
+	}
+
+	status.Replication = map[string]int{}
+	for dc, rf := range ks.Spec.Replication {
+		status.Replication[dc] = rf
+	}
+	setKeyspacePhase(status, v1alpha1.KeyspacePhaseReady, message)
+
+	return nil
+}
+
+// getLiveNodes counts the nodes that are up and normal in each datacenter as seen by a ready node of the cluster
+func (c *KeyspaceController) getLiveNodes(ctx context.Context, cluster *v1alpha1.CassandraCluster) (map[string]int, error) {
+	if len(cluster.Status.Members.Ready) == 0 {
+		return nil, fmt.Errorf("cluster %s has no ready nodes", cluster.GetName())
+	}
+
+	pod := &corev1.Pod{
+		TypeMeta: resource.GetPodTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.Status.Members.Ready[0],
+			Namespace: cluster.GetNamespace(),
+		},
+	}
+	err := c.k8sDriver.Get(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := c.ringReporter.GetStatus(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	live := map[string]int{}
+	for _, node := range statuses {
+		if node.Status == nodetool.NodeStatusUp && node.State == nodetool.NodeStateNormal {
+			live[node.Datacenter]++
+		}
+	}
+
+	return live, nil
+}
+
+// checkReplicationReduction refuses to lower the replication factor of a datacenter below the number of replicas
+// that are live in it, lowering it to match nodes that are gone is allowed
+func checkReplicationReduction(current, desired, live map[string]int) error {
+	for dc, rf := range desired {
+		currentRF, ok := current[dc]
+		if !ok || rf >= currentRF {
+			continue
+		}
+
+		liveReplicas := currentRF
+		if live[dc] < liveReplicas {
+			liveReplicas = live[dc]
+		}
+
+		if rf < liveReplicas {
+			return fmt.Errorf("refusing to reduce replication in %s from %d to %d, %d replicas are live", dc, currentRF, rf, liveReplicas)
+		}
+	}
+
+	return nil
+}
+
+func replicationIncreased(applied, desired map[string]int) bool {
+	for dc, rf := range desired {
+		if rf > applied[dc] {
+			return true
+		}
+	}
+	return false
+}
+
+func setKeyspacePhase(status *v1alpha1.KeyspaceStatus, phase v1alpha1.KeyspacePhase, message string) {
+	if phase != status.Phase || message != status.Message {
+		logrus.Debugf("Keyspace phase %s: %s", phase, message)
+	}
+	status.Phase = phase
+	status.Message = message
+}
@@ -0,0 +1,299 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/operator-framework/operator-sdk/pkg/util/k8sutil"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	"github.com/pantheon-systems/cassandra-operator/pkg/controller"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const nts = "org.apache.cassandra.locator.NetworkTopologyStrategy"
+
+type keyspaceFixture struct {
+	cluster   *v1alpha1.CassandraCluster
+	current   *cql.Keyspace
+	live      int
+	ensured   map[string]int
+	created   []sdk.Object
+	createErr error
+	updated   *v1alpha1.CassandraKeyspace
+	keyspace  *v1alpha1.CassandraKeyspace
+	// pendingOnEnsure is the pending repair recorded when the keyspace was altered
+	pendingOnEnsure string
+}
+
+func newKeyspaceFixture(replication map[string]int) *keyspaceFixture {
+	return &keyspaceFixture{
+		cluster: &v1alpha1.CassandraCluster{
+			TypeMeta: metav1.TypeMeta{Kind: "CassandraCluster"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-cluster",
+				Namespace:       "test-namespace",
+				ResourceVersion: "1",
+			},
+			Spec: v1alpha1.ClusterSpec{
+				Repair: &v1alpha1.RepairPolicy{Image: "repair:latest"},
+			},
+			Status: v1alpha1.ClusterStatus{
+				Phase:   v1alpha1.ClusterPhaseRunning,
+				Members: v1alpha1.NodesStatus{Ready: []string{"test-cluster-cassandra-0"}},
+			},
+		},
+		live: 3,
+		keyspace: &v1alpha1.CassandraKeyspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "app_data", Namespace: "test-namespace", UID: "test-uid", Generation: 1},
+			Spec: v1alpha1.KeyspaceSpec{
+				Cluster:     "test-cluster",
+				Replication: replication,
+			},
+		},
+	}
+}
+
+func (f *keyspaceFixture) sync(t *testing.T) error {
+	k8sDriver := &k8s.MockClient{
+		GetCallback: func(into sdk.Object, opts ...sdk.GetOption) error {
+			if into.GetObjectKind().GroupVersionKind().Kind == "CassandraCluster" {
+				return k8sutil.RuntimeObjectIntoRuntimeObject(f.cluster, into)
+			}
+			return nil
+		},
+		CreateCallback: func(object sdk.Object) error {
+			if f.createErr != nil {
+				return f.createErr
+			}
+			f.created = append(f.created, object)
+			return nil
+		},
+		UpdateCallback: func(object sdk.Object) error {
+			f.updated = object.(*v1alpha1.CassandraKeyspace).DeepCopy()
+			return nil
+		},
+	}
+
+	manager := &cql.MockManager{
+		GetKeyspaceCallback: func(keyspace string) (*cql.Keyspace, error) {
+			return f.current, nil
+		},
+		EnsureKeyspaceCallback: func(keyspace string, replication map[string]int, durableWrites bool) error {
+			assert.Equal(t, "app_data", keyspace)
+			assert.True(t, durableWrites)
+			f.ensured = replication
+			if f.updated != nil {
+				f.pendingOnEnsure = f.updated.Status.PendingRepair
+			}
+			return nil
+		},
+	}
+
+	ringReporter := &MockClusterClient{
+		GetStatusCallback: func(node *corev1.Pod) (map[string]*nodetool.Status, error) {
+			statuses := map[string]*nodetool.Status{}
+			for i := 0; i < f.live; i++ {
+				hostID := fmt.Sprintf("host-%d", i)
+				statuses[hostID] = &nodetool.Status{
+					HostID:     hostID,
+					Datacenter: "dc1",
+					Status:     nodetool.NodeStatusUp,
+					State:      nodetool.NodeStateNormal,
+				}
+			}
+			return statuses, nil
+		},
+	}
+
+	return controller.NewKeyspaceController(k8sDriver, &cql.MockConnector{Manager: manager}, ringReporter).Sync(context.Background(), f.keyspace)
+}
+
+func TestKeyspaceController_ClusterNotRunning(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 3})
+	f.cluster.Status.Phase = v1alpha1.ClusterPhaseCreating
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Nil(t, f.ensured)
+	assert.Equal(t, v1alpha1.KeyspacePhasePending, f.updated.Status.Phase)
+}
+
+func TestKeyspaceController_ClusterPaused(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 3})
+	f.cluster.Spec.Paused = true
+	f.current = &cql.Keyspace{Name: "app_data", Strategy: nts, Replication: map[string]int{"dc1": 2}, DurableWrites: true}
+	f.keyspace.Status.Replication = map[string]int{"dc1": 2}
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Nil(t, f.ensured)
+	assert.Empty(t, f.created)
+	assert.Equal(t, v1alpha1.KeyspacePhasePending, f.updated.Status.Phase)
+	assert.Empty(t, f.updated.Status.PendingRepair)
+}
+
+func TestKeyspaceController_InvalidName(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 3})
+	f.keyspace.Name = "app-data"
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Nil(t, f.ensured)
+	assert.Equal(t, v1alpha1.KeyspacePhaseRejected, f.updated.Status.Phase)
+}
+
+func TestKeyspaceController_Create(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 3})
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"dc1": 3}, f.ensured)
+	assert.Empty(t, f.created)
+	assert.Equal(t, v1alpha1.KeyspacePhaseReady, f.updated.Status.Phase)
+	assert.Equal(t, map[string]int{"dc1": 3}, f.updated.Status.Replication)
+}
+
+func TestKeyspaceController_IncreaseStartsRepair(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 3})
+	f.current = &cql.Keyspace{Name: "app_data", Strategy: nts, Replication: map[string]int{"dc1": 2}, DurableWrites: true}
+	f.keyspace.Status.Replication = map[string]int{"dc1": 2}
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"dc1": 3}, f.ensured)
+	assert.NotEmpty(t, f.pendingOnEnsure)
+	if assert.Len(t, f.created, 1) {
+		job := f.created[0].(*batchv1.Job)
+		assert.Contains(t, job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "KEYSPACE", Value: "app_data"})
+		assert.Equal(t, job.GetName(), f.updated.Status.RepairJob)
+	}
+	assert.Empty(t, f.updated.Status.PendingRepair)
+	assert.Equal(t, v1alpha1.KeyspacePhaseReady, f.updated.Status.Phase)
+}
+
+func TestKeyspaceController_IncreaseWithoutRepairImage(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 3})
+	f.cluster.Spec.Repair = nil
+	f.current = &cql.Keyspace{Name: "app_data", Strategy: nts, Replication: map[string]int{"dc1": 2}, DurableWrites: true}
+	f.keyspace.Status.Replication = map[string]int{"dc1": 2}
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"dc1": 3}, f.ensured)
+	assert.Empty(t, f.created)
+	assert.Empty(t, f.updated.Status.PendingRepair)
+	assert.Equal(t, v1alpha1.KeyspacePhaseReady, f.updated.Status.Phase)
+	assert.Contains(t, f.updated.Status.Message, "repaired by hand")
+}
+
+func TestKeyspaceController_IncreaseOverKeyspaceCreatedElsewhere(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 3})
+	f.current = &cql.Keyspace{Name: "app_data", Strategy: nts, Replication: map[string]int{"dc1": 2}, DurableWrites: true}
+
+	err := f.sync(t)
+
+	// only the replication applied by the resource is a baseline
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"dc1": 3}, f.ensured)
+	assert.Empty(t, f.created)
+}
+
+func TestKeyspaceController_RepairFailureIsRetried(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 3})
+	f.current = &cql.Keyspace{Name: "app_data", Strategy: nts, Replication: map[string]int{"dc1": 2}, DurableWrites: true}
+	f.keyspace.Status.Replication = map[string]int{"dc1": 2}
+	f.createErr = errors.New("jobs are forbidden")
+
+	err := f.sync(t)
+
+	assert.Error(t, err)
+	assert.Equal(t, map[string]int{"dc1": 3}, f.ensured)
+	assert.NotEmpty(t, f.updated.Status.PendingRepair)
+
+	// the keyspace was altered, the recorded repair is started by the next sync
+	f.current.Replication = map[string]int{"dc1": 3}
+	f.keyspace = f.updated
+	f.createErr = nil
+
+	err = f.sync(t)
+
+	assert.NoError(t, err)
+	if assert.Len(t, f.created, 1) {
+		assert.Equal(t, f.created[0].(*batchv1.Job).GetName(), f.updated.Status.RepairJob)
+	}
+	assert.Empty(t, f.updated.Status.PendingRepair)
+	assert.Equal(t, map[string]int{"dc1": 3}, f.updated.Status.Replication)
+}
+
+func TestKeyspaceController_EachIncreaseStartsRepair(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 3})
+	f.current = &cql.Keyspace{Name: "app_data", Strategy: nts, Replication: map[string]int{"dc1": 3}, DurableWrites: true}
+	f.keyspace.Status.Replication = map[string]int{"dc1": 2}
+
+	// 2 -> 3, 3 -> 2 and 2 -> 3 again
+	assert.NoError(t, f.sync(t))
+	f.keyspace = f.updated
+	f.keyspace.Generation = 2
+	f.keyspace.Spec.Replication = map[string]int{"dc1": 2}
+	f.live = 2
+	assert.NoError(t, f.sync(t))
+	f.keyspace = f.updated
+	f.keyspace.Generation = 3
+	f.keyspace.Spec.Replication = map[string]int{"dc1": 3}
+	f.current.Replication = map[string]int{"dc1": 2}
+	assert.NoError(t, f.sync(t))
+
+	if assert.Len(t, f.created, 2) {
+		assert.NotEqual(t, f.created[0].(*batchv1.Job).GetName(), f.created[1].(*batchv1.Job).GetName())
+	}
+}
+
+func TestKeyspaceController_RejectsClusterKeyspace(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 3})
+	f.cluster.Spec.KeyspaceName = "app_data"
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Nil(t, f.ensured)
+	assert.Equal(t, v1alpha1.KeyspacePhaseRejected, f.updated.Status.Phase)
+}
+
+func TestKeyspaceController_RefusesReductionBelowLiveReplicas(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 2})
+	f.current = &cql.Keyspace{Name: "app_data", Strategy: nts, Replication: map[string]int{"dc1": 3}, DurableWrites: true}
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Nil(t, f.ensured)
+	assert.Equal(t, v1alpha1.KeyspacePhaseRejected, f.updated.Status.Phase)
+}
+
+func TestKeyspaceController_AllowsReductionToLiveReplicas(t *testing.T) {
+	f := newKeyspaceFixture(map[string]int{"dc1": 2})
+	f.current = &cql.Keyspace{Name: "app_data", Strategy: nts, Replication: map[string]int{"dc1": 3}, DurableWrites: true}
+	f.live = 2
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"dc1": 2}, f.ensured)
+	assert.Empty(t, f.created)
+	assert.Equal(t, v1alpha1.KeyspacePhaseReady, f.updated.Status.Phase)
+}
@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RoleController reconciles CassandraRole resources over CQL
+type RoleController struct {
+	k8sDriver    k8s.Client
+	cqlConnector cql.Connector
+}
+
+// NewRoleController builds a new RoleController
+func NewRoleController(k8sDriver k8s.Client, cqlConnector cql.Connector) *RoleController {
+	return &RoleController{
+		k8sDriver:    k8sDriver,
+		cqlConnector: cqlConnector,
+	}
+}
+
+// Sync creates or alters the role and its permissions to match the spec, the password is generated into the
+// secret of the role and rotated when the rotate annotation is set
+func (c *RoleController) Sync(role *v1alpha1.CassandraRole) error {
+	status := role.Status.DeepCopy()
+	_, rotate := role.Annotations[v1alpha1.RotatePasswordAnnotation]
+
+	rotated, err := c.sync(role, status, rotate)
+	if err != nil {
+		return err
+	}
+
+	if !rotated && reflect.DeepEqual(status, &role.Status) {
+		return nil
+	}
+
+	if rotated {
+		delete(role.Annotations, v1alpha1.RotatePasswordAnnotation)
+	}
+	status.DeepCopyInto(&role.Status)
+
+	return c.k8sDriver.Update(role)
+}
+
+// Delete drops the role from the cluster, the secret is garbage collected with the resource. The drop is retried
+// while the cluster is paused.
+func (c *RoleController) Delete(role *v1alpha1.CassandraRole) error {
+	cluster, err := c.getCluster(role)
+	if err != nil || cluster == nil || cluster.Status.Phase != v1alpha1.ClusterPhaseRunning || !authEnabled(cluster) {
+		return err
+	}
+
+	if cluster.Spec.Paused {
+		return fmt.Errorf("cluster %s is paused, role %s is dropped once it is resumed", cluster.GetName(), role.GetRoleName())
+	}
+
+	session, err := connectCluster(c.k8sDriver, c.cqlConnector, cluster)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	return session.DropRole(role.GetRoleName())
+}
+
+func (c *RoleController) sync(role *v1alpha1.CassandraRole, status *v1alpha1.RoleStatus, rotate bool) (bool, error) {
+	roleName := role.GetRoleName()
+	if roleName == cql.DefaultSuperuser {
+		setRolePhase(status, v1alpha1.RolePhaseRejected, fmt.Sprintf("the default '%s' role can not be managed", cql.DefaultSuperuser))
+		return false, nil
+	}
+
+	grants, err := desiredGrants(role)
+	if err != nil {
+		setRolePhase(status, v1alpha1.RolePhaseRejected, err.Error())
+		return false, nil
+	}
+
+	cluster, err := c.getCluster(role)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case cluster == nil:
+		setRolePhase(status, v1alpha1.RolePhasePending, fmt.Sprintf("cluster %s does not exist", role.Spec.Cluster))
+		return false, nil
+	case cluster.Spec.Paused:
+		setRolePhase(status, v1alpha1.RolePhasePending, fmt.Sprintf("cluster %s is paused", cluster.GetName()))
+		return false, nil
+	case !authEnabled(cluster):
+		setRolePhase(status, v1alpha1.RolePhasePending, fmt.Sprintf("authentication is not enabled on cluster %s", cluster.GetName()))
+		return false, nil
+	case cluster.Status.Phase != v1alpha1.ClusterPhaseRunning:
+		setRolePhase(status, v1alpha1.RolePhasePending, fmt.Sprintf("waiting for cluster %s to be running", cluster.GetName()))
+		return false, nil
+	}
+
+	credentials, passwordChanged, err := c.convergeSecret(role, rotate)
+	if err != nil {
+		return false, err
+	}
+
+	if credentials.Username != roleName {
+		setRolePhase(status, v1alpha1.RolePhaseRejected,
+			fmt.Sprintf("secret %s holds the credentials of %s", role.GetSecretName(), credentials.Username))
+		return false, nil
+	}
+
+	session, err := connectCluster(c.k8sDriver, c.cqlConnector, cluster)
+	if err != nil {
+		return false, err
+	}
+	defer session.Close()
+
+	desired := cql.Role{Name: roleName, Login: role.GetLogin(), Superuser: role.Spec.Superuser}
+	current, err := session.GetRole(roleName)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case current == nil:
+		err = session.CreateRole(desired, credentials.Password)
+	case passwordChanged:
+		err = session.AlterRole(desired, credentials.Password)
+	case *current != desired:
+		err = session.AlterRole(desired, "")
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = c.convergePermissions(session, roleName, grants)
+	if err != nil {
+		return false, err
+	}
+
+	setRolePhase(status, v1alpha1.RolePhaseReady, "")
+	return rotate, nil
+}
+
+// convergeSecret makes sure the secret of the role exists and replaces the password when rotating, it
+// returns if the password in the secret is new
+func (c *RoleController) convergeSecret(role *v1alpha1.CassandraRole, rotate bool) (*cql.Credentials, bool, error) {
+	builder := resource.NewCredentialsSecret(
+		role.GetSecretName(),
+		role.GetNamespace(),
+		role.GetRoleName(),
+		map[string]string{"cluster": role.Spec.Cluster, "role": role.GetName()},
+		resource.OwnerOf(role.TypeMeta, role.ObjectMeta),
+	)
+	obj, err := builder.Reconcile(c.k8sDriver)
+	if err != nil {
+		return nil, false, err
+	}
+	secret := obj.(*corev1.Secret)
+
+	credentials, err := cql.NewCredentialsFromSecret(secret)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !rotate || builder.Created() {
+		return credentials, builder.Created(), nil
+	}
+
+	logrus.Infof("Rotating password of role %s", credentials.Username)
+	credentials.Password, err = cql.GeneratePassword()
+	if err != nil {
+		return nil, false, err
+	}
+
+	secret.Data[cql.PasswordKey] = []byte(credentials.Password)
+	err = c.k8sDriver.Update(secret)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return credentials, true, nil
+}
+
+func (c *RoleController) convergePermissions(session cql.Manager, roleName string, grants []cql.Grant) error {
+	current, err := session.GetPermissions(roleName)
+	if err != nil {
+		return err
+	}
+
+	missing, extra := cql.DiffGrants(current, grants)
+	for _, grant := range missing {
+		err = session.Grant(roleName, grant)
+		if err != nil {
+			return err
+		}
+	}
+	for _, grant := range extra {
+		err = session.Revoke(roleName, grant)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getCluster returns the cluster of the role or nil if it does not exist
+func (c *RoleController) getCluster(role *v1alpha1.CassandraRole) (*v1alpha1.CassandraCluster, error) {
+	cluster := &v1alpha1.CassandraCluster{
+		TypeMeta: resource.GetCassandraClusterTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      role.Spec.Cluster,
+			Namespace: role.GetNamespace(),
+		},
+	}
+	err := c.k8sDriver.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster.ResourceVersion == "" {
+		return nil, nil
+	}
+
+	return cluster, nil
+}
+
+// desiredGrants validates and flattens the permissions in the spec
+func desiredGrants(role *v1alpha1.CassandraRole) ([]cql.Grant, error) {
+	grants := []cql.Grant{}
+	for _, keyspace := range role.Spec.Permissions {
+		err := cql.ValidateIdentifier(keyspace.Keyspace)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, permission := range keyspace.Permissions {
+			err = cql.ValidatePermission(permission)
+			if err != nil {
+				return nil, err
+			}
+			grants = append(grants, cql.Grant{Permission: permission, Keyspace: keyspace.Keyspace})
+		}
+	}
+
+	return grants, nil
+}
+
+func setRolePhase(status *v1alpha1.RoleStatus, phase v1alpha1.RolePhase, message string) {
+	if phase != status.Phase || message != status.Message {
+		logrus.Debugf("Role phase %s: %s", phase, message)
+	}
+	status.Phase = phase
+	status.Message = message
+}
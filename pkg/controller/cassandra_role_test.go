@@ -0,0 +1,267 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/operator-framework/operator-sdk/pkg/util/k8sutil"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/controller"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type roleFixture struct {
+	cluster  *v1alpha1.CassandraCluster
+	role     *v1alpha1.CassandraRole
+	secrets  map[string]*corev1.Secret
+	current  *cql.Role
+	grants   []cql.Grant
+	created  *cql.Role
+	altered  *cql.Role
+	password string
+	granted  []cql.Grant
+	revoked  []cql.Grant
+	dropped  string
+	updated  *v1alpha1.CassandraRole
+}
+
+func newRoleFixture() *roleFixture {
+	return &roleFixture{
+		cluster: &v1alpha1.CassandraCluster{
+			TypeMeta: metav1.TypeMeta{Kind: "CassandraCluster"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-cluster",
+				Namespace:       "test-namespace",
+				ResourceVersion: "1",
+			},
+			Spec: v1alpha1.ClusterSpec{
+				Auth: &v1alpha1.AuthPolicy{Enabled: true},
+			},
+			Status: v1alpha1.ClusterStatus{Phase: v1alpha1.ClusterPhaseRunning},
+		},
+		role: &v1alpha1.CassandraRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-user", Namespace: "test-namespace"},
+			Spec: v1alpha1.RoleSpec{
+				Cluster: "test-cluster",
+				Permissions: []v1alpha1.KeyspacePermissions{
+					{Keyspace: "app_data", Permissions: []string{"SELECT", "MODIFY"}},
+				},
+			},
+		},
+		secrets: map[string]*corev1.Secret{
+			"test-cluster-cassandra-superuser": {
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-cassandra-superuser", ResourceVersion: "1"},
+				Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("admin-password")},
+			},
+		},
+		grants: []cql.Grant{},
+	}
+}
+
+func (f *roleFixture) sync(t *testing.T) error {
+	return f.controller(t).Sync(f.role)
+}
+
+func (f *roleFixture) delete(t *testing.T) error {
+	return f.controller(t).Delete(f.role)
+}
+
+func (f *roleFixture) controller(t *testing.T) *controller.RoleController {
+	k8sDriver := &k8s.MockClient{
+		GetCallback: func(into sdk.Object, opts ...sdk.GetOption) error {
+			switch o := into.(type) {
+			case *v1alpha1.CassandraCluster:
+				return k8sutil.RuntimeObjectIntoRuntimeObject(f.cluster, into)
+			case *corev1.Secret:
+				if secret, ok := f.secrets[o.GetName()]; ok {
+					secret.DeepCopyInto(o)
+				}
+			}
+			return nil
+		},
+		CreateCallback: func(object sdk.Object) error {
+			secret := object.(*corev1.Secret)
+			f.secrets[secret.GetName()] = secret
+			return nil
+		},
+		UpdateCallback: func(object sdk.Object) error {
+			switch o := object.(type) {
+			case *corev1.Secret:
+				f.secrets[o.GetName()] = o
+			case *v1alpha1.CassandraRole:
+				f.updated = o
+			}
+			return nil
+		},
+	}
+
+	manager := &cql.MockManager{
+		GetRoleCallback: func(name string) (*cql.Role, error) {
+			return f.current, nil
+		},
+		CreateRoleCallback: func(role cql.Role, password string) error {
+			f.created = &role
+			f.password = password
+			return nil
+		},
+		AlterRoleCallback: func(role cql.Role, password string) error {
+			f.altered = &role
+			f.password = password
+			return nil
+		},
+		GetPermissionsCallback: func(name string) ([]cql.Grant, error) {
+			return f.grants, nil
+		},
+		GrantCallback: func(name string, grant cql.Grant) error {
+			f.granted = append(f.granted, grant)
+			return nil
+		},
+		RevokeCallback: func(name string, grant cql.Grant) error {
+			f.revoked = append(f.revoked, grant)
+			return nil
+		},
+		DropRoleCallback: func(name string) error {
+			f.dropped = name
+			return nil
+		},
+	}
+
+	connector := &cql.MockConnector{
+		ConnectCallback: func(hosts []string, credentials *cql.Credentials) (cql.Manager, error) {
+			assert.Equal(t, []string{"test-cluster-cassandra.test-namespace.svc.cluster.local"}, hosts)
+			assert.Equal(t, &cql.Credentials{Username: "admin", Password: "admin-password"}, credentials)
+			return manager, nil
+		},
+	}
+
+	return controller.NewRoleController(k8sDriver, connector)
+}
+
+func TestRoleController_Create(t *testing.T) {
+	f := newRoleFixture()
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	secret := f.secrets["app-user-credentials"]
+	if assert.NotNil(t, secret) {
+		assert.Equal(t, "app-user", string(secret.Data["username"]))
+		assert.Equal(t, string(secret.Data["password"]), f.password)
+	}
+	assert.Equal(t, &cql.Role{Name: "app-user", Login: true}, f.created)
+	assert.Equal(t, []cql.Grant{
+		{Permission: "MODIFY", Keyspace: "app_data"},
+		{Permission: "SELECT", Keyspace: "app_data"},
+	}, f.granted)
+	assert.Equal(t, v1alpha1.RolePhaseReady, f.updated.Status.Phase)
+}
+
+func TestRoleController_Rotate(t *testing.T) {
+	f := newRoleFixture()
+	f.role.Annotations = map[string]string{v1alpha1.RotatePasswordAnnotation: ""}
+	f.secrets["app-user-credentials"] = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-user-credentials", ResourceVersion: "1"},
+		Data:       map[string][]byte{"username": []byte("app-user"), "password": []byte("old-password")},
+	}
+	f.current = &cql.Role{Name: "app-user", Login: true}
+	f.grants = []cql.Grant{
+		{Permission: "SELECT", Keyspace: "app_data"},
+		{Permission: "MODIFY", Keyspace: "app_data"},
+	}
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	newPassword := string(f.secrets["app-user-credentials"].Data["password"])
+	assert.NotEqual(t, "old-password", newPassword)
+	assert.Equal(t, newPassword, f.password)
+	assert.Equal(t, &cql.Role{Name: "app-user", Login: true}, f.altered)
+	assert.Empty(t, f.granted)
+	assert.Empty(t, f.revoked)
+	assert.NotContains(t, f.updated.Annotations, v1alpha1.RotatePasswordAnnotation)
+}
+
+func TestRoleController_AlterAndRevoke(t *testing.T) {
+	f := newRoleFixture()
+	f.role.Spec.Superuser = true
+	f.secrets["app-user-credentials"] = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-user-credentials", ResourceVersion: "1"},
+		Data:       map[string][]byte{"username": []byte("app-user"), "password": []byte("password")},
+	}
+	f.current = &cql.Role{Name: "app-user", Login: true}
+	f.grants = []cql.Grant{
+		{Permission: "SELECT", Keyspace: "app_data"},
+		{Permission: "MODIFY", Keyspace: "app_data"},
+		{Permission: "SELECT", Keyspace: "other"},
+	}
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Nil(t, f.created)
+	assert.Equal(t, &cql.Role{Name: "app-user", Login: true, Superuser: true}, f.altered)
+	assert.Empty(t, f.password)
+	assert.Equal(t, []cql.Grant{{Permission: "SELECT", Keyspace: "other"}}, f.revoked)
+}
+
+func TestRoleController_AuthNotEnabled(t *testing.T) {
+	f := newRoleFixture()
+	f.cluster.Spec.Auth = nil
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Nil(t, f.created)
+	assert.Equal(t, v1alpha1.RolePhasePending, f.updated.Status.Phase)
+}
+
+func TestRoleController_InvalidPermission(t *testing.T) {
+	f := newRoleFixture()
+	f.role.Spec.Permissions[0].Permissions = []string{"EXECUTE"}
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Nil(t, f.created)
+	assert.Equal(t, v1alpha1.RolePhaseRejected, f.updated.Status.Phase)
+}
+
+func TestRoleController_DefaultSuperuser(t *testing.T) {
+	f := newRoleFixture()
+	f.role.Spec.RoleName = "cassandra"
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Nil(t, f.created)
+	assert.Equal(t, v1alpha1.RolePhaseRejected, f.updated.Status.Phase)
+}
+
+func TestRoleController_ClusterPaused(t *testing.T) {
+	f := newRoleFixture()
+	f.cluster.Spec.Paused = true
+
+	err := f.sync(t)
+
+	assert.NoError(t, err)
+	assert.Nil(t, f.created)
+	assert.Empty(t, f.secrets["app-user-credentials"])
+	assert.Equal(t, v1alpha1.RolePhasePending, f.updated.Status.Phase)
+}
+
+func TestRoleController_DeleteWaitsForPausedCluster(t *testing.T) {
+	f := newRoleFixture()
+	f.cluster.Spec.Paused = true
+
+	// the error requeues the delete until the cluster is resumed
+	assert.Error(t, f.delete(t))
+	assert.Empty(t, f.dropped)
+
+	f.cluster.Spec.Paused = false
+	assert.NoError(t, f.delete(t))
+	assert.Equal(t, "app-user", f.dropped)
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// convergeCertificates issues the CA, the keystore password and the node keystores and returns the revision
+// of the node certificates
+func (c *ClusterController) convergeCertificates() (string, error) {
+	logrus.Debugln("Converging certificates")
+
+	ca := resource.NewCertificateAuthority(c.cluster)
+	_, err := ca.Reconcile(c.driver)
+	if err != nil {
+		return "", err
+	}
+
+	password := resource.NewKeystorePassword(c.cluster)
+	_, err = password.Reconcile(c.driver)
+	if err != nil {
+		return "", err
+	}
+
+	certificates := resource.NewCertificates(c.cluster, ca.Authority(), password.Password())
+	_, err = certificates.Reconcile(c.driver)
+	if err != nil {
+		return "", err
+	}
+
+	return certificates.Revision(), nil
+}
+
+// rollCertificates restarts a single pod that is running with an older revision of the node certificates, it
+// only does so while every node is ready so the ring never loses more than one node to the restart
+func (c *ClusterController) rollCertificates(revision string) error {
+	if len(c.cluster.Status.Members.Ready) != c.cluster.Spec.Size {
+		logrus.Debugf("Not all nodes of cluster %s are ready, waiting to roll certificates", c.cluster.GetName())
+		return nil
+	}
+
+	pods, err := listClusterPods(c.driver, c.cluster.GetName(), c.cluster.GetNamespace(), c.cluster.GetLabels())
+	if err != nil {
+		return err
+	}
+
+	stale := []corev1.Pod{}
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || !podReady(&pod) {
+			logrus.Debugf("Pod %s is not ready, waiting to roll certificates", pod.GetName())
+			return nil
+		}
+		if pod.Annotations[resource.CertificatesRevisionAnnotation] != revision {
+			stale = append(stale, pod)
+		}
+	}
+
+	if len(stale) == 0 {
+		return nil
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].GetName() < stale[j].GetName()
+	})
+
+	pod := stale[0]
+	// items of a list do not carry their type which the sdk needs to find the resource
+	pod.TypeMeta = resource.GetPodTypeMeta()
+	logrus.Infof("Restarting pod %s of cluster %s to load revision %s of the node certificates, %d pods left",
+		pod.GetName(), c.cluster.GetName(), revision, len(stale)-1)
+
+	return c.driver.Delete(&pod)
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// connectOptions returns the options to open CQL sessions to the cluster with, sessions are encrypted and
+// verified against the CA of the cluster when client encryption is enabled
+func connectOptions(driver opsdk.Client, cc *v1alpha1.CassandraCluster) ([]cql.ConnectOption, error) {
+	if !resource.ClientEncryptionEnabled(cc) {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: resource.GetSecretTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resource.CASecretName(cc),
+			Namespace: cc.GetNamespace(),
+		},
+	}
+	err := driver.Get(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret.ResourceVersion == "" {
+		return nil, fmt.Errorf("CA secret %s for cluster %s does not exist", secret.GetName(), cc.GetName())
+	}
+
+	// every node certificate is valid for the internal service the sessions are opened through
+	return []cql.ConnectOption{cql.WithTLS(secret.Data[resource.CACertificateKey], ClusterHosts(cc)[0])}, nil
+}
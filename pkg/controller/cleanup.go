@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultMaxConcurrentCleanups = 1
+
+// nodeCleaner runs nodetool cleanup on a node
+type nodeCleaner interface {
+	Cleanup(ctx context.Context, node *corev1.Pod) error
+}
+
+// cleanup is the result of a cleanup running in the background
+type cleanup struct {
+	done bool
+	err  error
+}
+
+// CleanupManager cleans up the nodes of a cluster after it scaled up. A cleanup takes as long as a compaction of
+// all the data of the node, so it runs in the background and a later reconcile records its result in the status.
+type CleanupManager struct {
+	cleaner nodeCleaner
+	driver  k8s.Client
+
+	mu sync.Mutex
+	// cleanups are keyed by the namespace and name of the pod
+	cleanups map[string]*cleanup
+}
+
+// NewCleanupManager returns a new CleanupManager that cleans up the nodes with the cleaner
+func NewCleanupManager(cleaner nodeCleaner, driver k8s.Client) *CleanupManager {
+	return &CleanupManager{
+		cleaner:  cleaner,
+		driver:   driver,
+		cleanups: map[string]*cleanup{},
+	}
+}
+
+// Converge records the cleanups that finished and starts the pending ones, up to the maximum concurrent
+// cleanups of the cluster. Nodes are only cleaned up while the cluster is running and no node is streaming. The
+// cleanups run until the context is done.
+func (m *CleanupManager) Converge(ctx context.Context, cc *v1alpha1.CassandraCluster) error {
+	status := cc.Status.Cleanup
+	if status == nil || cc.Spec.Paused {
+		return nil
+	}
+
+	changed := m.collect(cc.GetNamespace(), status)
+
+	if len(status.Pending) > 0 && cleanupAllowed(cc) {
+		max := cc.Spec.MaxConcurrentCleanups
+		if max < 1 {
+			max = defaultMaxConcurrentCleanups
+		}
+
+		for len(status.Running) < max && len(status.Pending) > 0 {
+			name := status.Pending[0]
+			status.Pending = status.Pending[1:]
+			changed = true
+
+			pod := &corev1.Pod{
+				TypeMeta: resource.GetPodTypeMeta(),
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: cc.GetNamespace(),
+				},
+			}
+			err := m.driver.Get(pod)
+			if err != nil {
+				return err
+			}
+			if pod.ResourceVersion == "" {
+				// the cluster scaled down since, the node is gone
+				continue
+			}
+
+			m.start(ctx, pod)
+			status.Running = append(status.Running, name)
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return m.driver.Update(cc)
+}
+
+// collect moves the nodes that finished their cleanup out of the running nodes, a failed cleanup is pending
+// again and so is a cleanup the operator lost track of when it restarted
+func (m *CleanupManager) collect(namespace string, status *v1alpha1.CleanupStatus) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed := false
+	var running []string
+	for _, name := range status.Running {
+		key := fmt.Sprintf("%s/%s", namespace, name)
+		c, ok := m.cleanups[key]
+		switch {
+		case !ok:
+			logrus.Infof("Cleanup of pod %s was interrupted, it is started again", key)
+			status.Pending = append([]string{name}, status.Pending...)
+		case !c.done:
+			running = append(running, name)
+			continue
+		case c.err != nil:
+			status.LastError = fmt.Sprintf("cleanup of pod %s failed: %v", name, c.err)
+			status.Pending = append(status.Pending, name)
+		default:
+			status.Completed = append(status.Completed, name)
+		}
+		delete(m.cleanups, key)
+		changed = true
+	}
+	status.Running = running
+
+	return changed
+}
+
+// start runs the cleanup of the node in the background
+func (m *CleanupManager) start(ctx context.Context, pod *corev1.Pod) {
+	key := fmt.Sprintf("%s/%s", pod.GetNamespace(), pod.GetName())
+	c := &cleanup{}
+
+	m.mu.Lock()
+	m.cleanups[key] = c
+	m.mu.Unlock()
+
+	logrus.Infof("Starting cleanup of pod %s", key)
+	go func() {
+		err := m.cleaner.Cleanup(ctx, pod)
+		if err != nil {
+			logrus.Warnf("Cleanup of pod %s failed: %v", key, err)
+		} else {
+			logrus.Infof("Cleanup of pod %s finished", key)
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		c.done, c.err = true, err
+	}()
+}
+
+// cleanupAllowed returns true when all the nodes of the cluster joined the ring and no node is streaming
+func cleanupAllowed(cc *v1alpha1.CassandraCluster) bool {
+	return cc.Status.Phase == v1alpha1.ClusterPhaseRunning && !cc.Status.NodesInTransit() &&
+		len(cc.Status.Members.Streaming) == 0
+}
+
+// scheduleCleanup marks the nodes that were in the ring before the statefulset scaled up as pending a cleanup,
+// they handed token ranges to the added node. The nodes that finished a cleanup since the last scale up need
+// another one.
+func (c *ClusterController) scheduleCleanup(replicas int) error {
+	status := c.cluster.Status.Cleanup
+	if status.Done() {
+		status = &v1alpha1.CleanupStatus{}
+	}
+
+	for i := 0; i < replicas; i++ {
+		name := fmt.Sprintf("%s-cassandra-%d", c.cluster.GetName(), i)
+		if !containsString(status.Pending, name) {
+			status.Pending = append(status.Pending, name)
+		}
+		status.Completed = removeString(status.Completed, name)
+	}
+
+	logrus.Infof("Cluster %s scaled up, nodes %v are cleaned up once it is running", c.cluster.GetName(), status.Pending)
+	c.cluster.Status.Cleanup = status
+	return c.driver.Update(c.cluster)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, value string) []string {
+	var result []string
+	for _, item := range list {
+		if item != value {
+			result = append(result, item)
+		}
+	}
+	return result
+}
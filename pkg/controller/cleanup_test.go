@@ -0,0 +1,145 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/controller"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// blockingCleaner records the cleanups and finishes them when they are released
+type blockingCleaner struct {
+	mu      sync.Mutex
+	started []string
+	release map[string]chan error
+}
+
+func (c *blockingCleaner) Cleanup(ctx context.Context, node *corev1.Pod) error {
+	c.mu.Lock()
+	c.started = append(c.started, node.GetName())
+	release := make(chan error, 1)
+	c.release[node.GetName()] = release
+	c.mu.Unlock()
+
+	return <-release
+}
+
+func (c *blockingCleaner) finish(t *testing.T, pod string, err error) {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c.mu.Lock()
+		release, ok := c.release[pod]
+		delete(c.release, pod)
+		c.mu.Unlock()
+		if ok {
+			release <- err
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("cleanup of %s did not start", pod)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func getCleanupCluster(pending ...string) *v1alpha1.CassandraCluster {
+	cc := getCassandraCluster(3, v1alpha1.ClusterPhaseRunning)
+	cc.Status.Members.Ready = []string{"test-cluster-cassandra-0", "test-cluster-cassandra-1", "test-cluster-cassandra-2"}
+	cc.Status.Cleanup = &v1alpha1.CleanupStatus{Pending: pending}
+	return cc
+}
+
+func newCleanupClient(t *testing.T, cc *v1alpha1.CassandraCluster) *k8s.FakeClient {
+	client := k8s.NewFakeClient(cc)
+	for _, name := range cc.Status.Members.Ready {
+		assert.NoError(t, client.Create(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "testnamespace"}}))
+	}
+	return client
+}
+
+// converge converges the cleanups of the stored cluster until the status satisfies the condition
+func converge(t *testing.T, manager *controller.CleanupManager, client *k8s.FakeClient, done func(*v1alpha1.CleanupStatus) bool) *v1alpha1.CleanupStatus {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		cc := getCassandraCluster(0, "")
+		assert.NoError(t, client.Get(cc))
+		assert.NoError(t, manager.Converge(context.Background(), cc))
+		if done(cc.Status.Cleanup) {
+			return cc.Status.Cleanup
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("cleanup did not converge: %+v", cc.Status.Cleanup)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCleanupManager_OneNodeAtATime(t *testing.T) {
+	cc := getCleanupCluster("test-cluster-cassandra-0", "test-cluster-cassandra-1")
+	client := newCleanupClient(t, cc)
+	cleaner := &blockingCleaner{release: map[string]chan error{}}
+	manager := controller.NewCleanupManager(cleaner, client)
+
+	status := converge(t, manager, client, func(s *v1alpha1.CleanupStatus) bool { return len(s.Running) == 1 })
+	assert.Equal(t, []string{"test-cluster-cassandra-0"}, status.Running)
+	assert.Equal(t, []string{"test-cluster-cassandra-1"}, status.Pending)
+
+	// a failed cleanup is retried after the other pending nodes
+	cleaner.finish(t, "test-cluster-cassandra-0", errors.New("connection refused"))
+	status = converge(t, manager, client, func(s *v1alpha1.CleanupStatus) bool {
+		return len(s.Running) == 1 && s.Running[0] == "test-cluster-cassandra-1"
+	})
+	assert.Equal(t, []string{"test-cluster-cassandra-0"}, status.Pending)
+	assert.Equal(t, "cleanup of pod test-cluster-cassandra-0 failed: connection refused", status.LastError)
+
+	cleaner.finish(t, "test-cluster-cassandra-1", nil)
+	converge(t, manager, client, func(s *v1alpha1.CleanupStatus) bool { return len(s.Completed) == 1 })
+	cleaner.finish(t, "test-cluster-cassandra-0", nil)
+	status = converge(t, manager, client, func(s *v1alpha1.CleanupStatus) bool { return s.Done() })
+	assert.Equal(t, []string{"test-cluster-cassandra-1", "test-cluster-cassandra-0"}, status.Completed)
+}
+
+func TestCleanupManager_Concurrent(t *testing.T) {
+	cc := getCleanupCluster("test-cluster-cassandra-0", "test-cluster-cassandra-1", "test-cluster-cassandra-2")
+	cc.Spec.MaxConcurrentCleanups = 2
+	client := newCleanupClient(t, cc)
+	cleaner := &blockingCleaner{release: map[string]chan error{}}
+	manager := controller.NewCleanupManager(cleaner, client)
+
+	status := converge(t, manager, client, func(s *v1alpha1.CleanupStatus) bool { return len(s.Running) == 2 })
+	assert.Equal(t, []string{"test-cluster-cassandra-2"}, status.Pending)
+}
+
+func TestCleanupManager_WaitsForStreams(t *testing.T) {
+	cc := getCleanupCluster("test-cluster-cassandra-0")
+	cc.Status.Members.Streaming = []string{"test-cluster-cassandra-1"}
+	client := newCleanupClient(t, cc)
+	cleaner := &blockingCleaner{release: map[string]chan error{}}
+	manager := controller.NewCleanupManager(cleaner, client)
+
+	assert.NoError(t, manager.Converge(context.Background(), cc))
+	assert.Equal(t, []string{"test-cluster-cassandra-0"}, cc.Status.Cleanup.Pending)
+	assert.Empty(t, cc.Status.Cleanup.Running)
+	assert.Empty(t, cleaner.started)
+}
+
+func TestCleanupManager_RestartsInterruptedCleanup(t *testing.T) {
+	cc := getCleanupCluster()
+	cc.Status.Cleanup.Running = []string{"test-cluster-cassandra-2"}
+	client := newCleanupClient(t, cc)
+	cleaner := &blockingCleaner{release: map[string]chan error{}}
+
+	// a new manager does not know the cleanup that was running before the operator restarted
+	manager := controller.NewCleanupManager(cleaner, client)
+	status := converge(t, manager, client, func(s *v1alpha1.CleanupStatus) bool { return len(s.Running) == 1 })
+	assert.Equal(t, []string{"test-cluster-cassandra-2"}, status.Running)
+	cleaner.finish(t, "test-cluster-cassandra-2", nil)
+}
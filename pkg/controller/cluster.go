@@ -3,24 +3,31 @@ package controller
 import (
 	"fmt"
 	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
 	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/secrets"
 	"github.com/pantheon-systems/cassandra-operator/version"
 	"github.com/sirupsen/logrus"
 )
 
 // ClusterController is the director for they sync and build
 type ClusterController struct {
-	driver  opsdk.Client
-	cluster *v1alpha1.CassandraCluster
+	driver         opsdk.Client
+	cqlConnector   cql.Connector
+	secretProvider secrets.SecretProvider
+	cluster        *v1alpha1.CassandraCluster
 
 	headlessServiceName string
 }
 
-// New constructs a new ClusterController from an API object
-func New(cc *v1alpha1.CassandraCluster, driver opsdk.Client) *ClusterController {
+// New constructs a new ClusterController from an API object, the secret provider reads the secrets of clusters
+// that keep them in vault and may be nil when vault is not configured
+func New(cc *v1alpha1.CassandraCluster, driver opsdk.Client, cqlConnector cql.Connector, secretProvider secrets.SecretProvider) *ClusterController {
 	return &ClusterController{
-		driver:  driver,
-		cluster: cc,
+		driver:         driver,
+		cqlConnector:   cqlConnector,
+		secretProvider: secretProvider,
+		cluster:        cc,
 	}
 }
 
@@ -29,6 +36,11 @@ func New(cc *v1alpha1.CassandraCluster, driver opsdk.Client) *ClusterController
 func (c *ClusterController) Sync() error {
 	logrus.Debugln("Sync called")
 
+	if c.cluster.Spec.Paused {
+		logrus.Infof("Reconciliation of cluster %s is paused", c.cluster.GetName())
+		return c.suspendRepairs()
+	}
+
 	switch c.cluster.Status.Phase {
 	case "":
 		c.cluster.Annotations["database.panth.io/cassandra-operator-version"] = version.Version
@@ -39,15 +51,23 @@ func (c *ClusterController) Sync() error {
 	case v1alpha1.ClusterPhaseInitial:
 		logrus.Debugf("ClusterPhaseInitial for cluster: %s", c.cluster.GetName())
 		// initial is the default phase when the cluster object is created
-		err := c.validateConfigmaps()
+		configMaps, err := c.validateConfigmaps()
+		if err != nil {
+			return err
+		}
+		secretsValid, err := c.validateSecrets()
 		if err != nil {
 			return err
 		}
-		// Vault -> maybe a plugin interface here for the OSS project
-		err = c.validateSecrets()
+
+		valid, err := c.recordConditions(configMaps, secretsValid)
 		if err != nil {
 			return err
 		}
+		// nodes would not start without them, wait for the secrets and configmaps to be fixed
+		if !valid {
+			return nil
+		}
 	case v1alpha1.ClusterPhaseFailed:
 		return fmt.Errorf("provisioning cluster has failed")
 	default:
@@ -64,11 +84,3 @@ func (c *ClusterController) Sync() error {
 
 	return c.reconcile()
 }
-
-func (c *ClusterController) validateSecrets() error {
-	return nil
-}
-
-func (c *ClusterController) validateConfigmaps() error {
-	return nil
-}
@@ -0,0 +1,281 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/secrets"
+	"github.com/pantheon-systems/cassandra-operator/pkg/controller"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// newReferencesClient returns a client that knows the secrets and configmaps, everything else does not exist
+// and creating or updating it is recorded
+func newReferencesClient(secrets map[string]*corev1.Secret, configMaps map[string]*corev1.ConfigMap, created *[]sdk.Object, updated *[]sdk.Object) *k8s.MockClient {
+	return &k8s.MockClient{
+		GetCallback: func(object sdk.Object, opts ...sdk.GetOption) error {
+			switch o := object.(type) {
+			case *corev1.Secret:
+				if secret, ok := secrets[o.GetName()]; ok {
+					secret.DeepCopyInto(o)
+					o.ResourceVersion = "1"
+				}
+			case *corev1.ConfigMap:
+				if configMap, ok := configMaps[o.GetName()]; ok {
+					configMap.DeepCopyInto(o)
+					o.ResourceVersion = "1"
+				}
+			}
+			return nil
+		},
+		CreateCallback: func(object sdk.Object) error {
+			*created = append(*created, object)
+			return nil
+		},
+		UpdateCallback: func(object sdk.Object) error {
+			*updated = append(*updated, object)
+			return nil
+		},
+	}
+}
+
+func getInitialCluster() *v1alpha1.CassandraCluster {
+	cc := getCassandraCluster(3, v1alpha1.ClusterPhaseInitial)
+	cc.Annotations = map[string]string{}
+	cc.Spec.JvmAgent = "sidecar"
+	cc.Spec.ConfigMapName = "test-cluster-cassandra-config"
+	cc.Spec.Node = &v1alpha1.NodePolicy{
+		Resources: &corev1.ResourceRequirements{},
+	}
+	return cc
+}
+
+func getValidSecrets() map[string]*corev1.Secret {
+	return map[string]*corev1.Secret{
+		"test-cluster-cassandra-certs": {
+			Data: map[string][]byte{
+				"keystore.jks":   []byte("keystore"),
+				"truststore.jks": []byte("truststore"),
+			},
+		},
+	}
+}
+
+func getValidConfigMaps() map[string]*corev1.ConfigMap {
+	return map[string]*corev1.ConfigMap{
+		"test-cluster-cassandra-config": {
+			Data: map[string]string{"cassandra.yaml": ""},
+		},
+		"test-cluster-prometheus-jvm-agent-config": {
+			Data: map[string]string{"telegraf.conf": ""},
+		},
+	}
+}
+
+func TestClusterController_SyncMissingConfigMap(t *testing.T) {
+	configMaps := getValidConfigMaps()
+	delete(configMaps, "test-cluster-prometheus-jvm-agent-config")
+
+	created, updated := []sdk.Object{}, []sdk.Object{}
+	client := newReferencesClient(getValidSecrets(), configMaps, &created, &updated)
+	cc := getInitialCluster()
+
+	err := controller.New(cc, client, &cql.MockConnector{}, nil).Sync()
+	assert.NoError(t, err)
+	assert.Empty(t, created)
+	assert.Len(t, updated, 1)
+
+	condition := cc.Status.GetCondition(v1alpha1.ClusterConditionConfigMapsValid)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, corev1.ConditionFalse, condition.Status)
+		assert.Equal(t, "ConfigMapNotFound", condition.Reason)
+		assert.Equal(t, "ConfigMap testnamespace/test-cluster-prometheus-jvm-agent-config does not exist", condition.Message)
+	}
+	assert.Equal(t, corev1.ConditionTrue, cc.Status.GetCondition(v1alpha1.ClusterConditionSecretsValid).Status)
+}
+
+func TestClusterController_SyncMissingSecretKey(t *testing.T) {
+	secrets := getValidSecrets()
+	delete(secrets["test-cluster-cassandra-certs"].Data, "truststore.jks")
+
+	created, updated := []sdk.Object{}, []sdk.Object{}
+	client := newReferencesClient(secrets, getValidConfigMaps(), &created, &updated)
+	cc := getInitialCluster()
+
+	err := controller.New(cc, client, &cql.MockConnector{}, nil).Sync()
+	assert.NoError(t, err)
+	assert.Empty(t, created)
+
+	condition := cc.Status.GetCondition(v1alpha1.ClusterConditionSecretsValid)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, corev1.ConditionFalse, condition.Status)
+		assert.Equal(t, "SecretKeyMissing", condition.Reason)
+		assert.Equal(t, `Secret testnamespace/test-cluster-cassandra-certs is missing the key "truststore.jks"`, condition.Message)
+	}
+
+	// the condition is only written again once it changes
+	transition := condition.LastTransitionTime
+	err = controller.New(cc, client, &cql.MockConnector{}, nil).Sync()
+	assert.NoError(t, err)
+	assert.Len(t, updated, 1)
+	assert.Equal(t, transition, cc.Status.GetCondition(v1alpha1.ClusterConditionSecretsValid).LastTransitionTime)
+}
+
+func TestClusterController_SyncPaused(t *testing.T) {
+	created, updated := []sdk.Object{}, []sdk.Object{}
+	client := newReferencesClient(getValidSecrets(), getValidConfigMaps(), &created, &updated)
+	cc := getInitialCluster()
+	cc.Spec.Paused = true
+	cc.Spec.Repair = &v1alpha1.RepairPolicy{Schedule: "@daily"}
+
+	err := controller.New(cc, client, &cql.MockConnector{}, nil).Sync()
+	assert.NoError(t, err)
+	assert.Empty(t, updated)
+	assert.Empty(t, cc.Status.Conditions)
+
+	// only the repair cron job is touched, to suspend it
+	if assert.Len(t, created, 1) {
+		cronJob, ok := created[0].(*batchv1beta1.CronJob)
+		if assert.True(t, ok) && assert.NotNil(t, cronJob.Spec.Suspend) {
+			assert.True(t, *cronJob.Spec.Suspend)
+		}
+	}
+}
+
+func TestClusterController_SyncManagedTLSSkipsCertsSecret(t *testing.T) {
+	created, updated := []sdk.Object{}, []sdk.Object{}
+	client := newReferencesClient(map[string]*corev1.Secret{}, getValidConfigMaps(), &created, &updated)
+	cc := getInitialCluster()
+	cc.Spec.TLS = &v1alpha1.TLSPolicy{Managed: true}
+
+	err := controller.New(cc, client, &cql.MockConnector{}, nil).Sync()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created)
+	assert.Equal(t, corev1.ConditionTrue, cc.Status.GetCondition(v1alpha1.ClusterConditionSecretsValid).Status)
+	assert.Equal(t, corev1.ConditionTrue, cc.Status.GetCondition(v1alpha1.ClusterConditionConfigMapsValid).Status)
+}
+
+func getVaultProvider(keystore map[string][]byte) *secrets.MockProvider {
+	return &secrets.MockProvider{
+		KeystoreCallback: func(namespace, name string) (map[string][]byte, error) {
+			if name != "cassandra/test-cluster/keystore" {
+				return nil, nil
+			}
+			return keystore, nil
+		},
+	}
+}
+
+func TestClusterController_SyncVaultKeystore(t *testing.T) {
+	created, updated := []sdk.Object{}, []sdk.Object{}
+	client := newReferencesClient(map[string]*corev1.Secret{}, getValidConfigMaps(), &created, &updated)
+	cc := getInitialCluster()
+	cc.Spec.Secrets = &v1alpha1.SecretsPolicy{
+		Provider:     v1alpha1.SecretsProviderVault,
+		KeystorePath: "cassandra/test-cluster/keystore",
+	}
+	keystore := map[string][]byte{
+		"keystore.jks":   []byte("keystore"),
+		"truststore.jks": []byte("truststore"),
+	}
+
+	err := controller.New(cc, client, &cql.MockConnector{}, getVaultProvider(keystore)).Sync()
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.ConditionTrue, cc.Status.GetCondition(v1alpha1.ClusterConditionSecretsValid).Status)
+
+	var secret *corev1.Secret
+	var statefulSet *appsv1.StatefulSet
+	for _, object := range created {
+		switch o := object.(type) {
+		case *corev1.Secret:
+			if o.GetName() == "test-cluster-cassandra-vault-keystore" {
+				secret = o
+			}
+		case *appsv1.StatefulSet:
+			statefulSet = o
+		}
+	}
+
+	if assert.NotNil(t, secret) {
+		assert.Equal(t, keystore, secret.Data)
+	}
+	if assert.NotNil(t, statefulSet) {
+		volume := statefulSet.Spec.Template.Spec.Volumes[0]
+		assert.Equal(t, "cassandra-keystore", volume.Name)
+		if assert.NotNil(t, volume.Projected) {
+			assert.Equal(t, "test-cluster-cassandra-vault-keystore", volume.Projected.Sources[0].Secret.Name)
+		}
+	}
+}
+
+func TestClusterController_SyncVaultKeystoreMissingKey(t *testing.T) {
+	created, updated := []sdk.Object{}, []sdk.Object{}
+	client := newReferencesClient(map[string]*corev1.Secret{}, getValidConfigMaps(), &created, &updated)
+	cc := getInitialCluster()
+	cc.Spec.Secrets = &v1alpha1.SecretsPolicy{
+		Provider:     v1alpha1.SecretsProviderVault,
+		KeystorePath: "cassandra/test-cluster/keystore",
+	}
+
+	err := controller.New(cc, client, &cql.MockConnector{}, getVaultProvider(map[string][]byte{"keystore.jks": {}})).Sync()
+	assert.NoError(t, err)
+	assert.Empty(t, created)
+
+	condition := cc.Status.GetCondition(v1alpha1.ClusterConditionSecretsValid)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, "VaultSecretKeyMissing", condition.Reason)
+		assert.Equal(t, `VaultSecret cassandra/test-cluster/keystore is missing the key "truststore.jks"`, condition.Message)
+	}
+
+	err = controller.New(cc, client, &cql.MockConnector{}, nil).Sync()
+	assert.NoError(t, err)
+	assert.Equal(t, "VaultNotConfigured", cc.Status.GetCondition(v1alpha1.ClusterConditionSecretsValid).Reason)
+}
+
+func getRunningCluster(size int, keyspace *cql.Keyspace, ensured *map[string]int) (*v1alpha1.CassandraCluster, *cql.MockConnector) {
+	cc := getInitialCluster()
+	cc.Spec.Size = size
+	cc.Spec.KeyspaceName = "test_cluster"
+	cc.Status.Phase = v1alpha1.ClusterPhaseRunning
+
+	manager := &cql.MockManager{
+		LocalNode: &cql.Node{Datacenter: "dc1"},
+		GetKeyspaceCallback: func(name string) (*cql.Keyspace, error) {
+			return keyspace, nil
+		},
+		EnsureKeyspaceCallback: func(name string, replication map[string]int, durableWrites bool) error {
+			*ensured = replication
+			return nil
+		},
+	}
+	return cc, &cql.MockConnector{Manager: manager}
+}
+
+func TestClusterController_SyncCreatesKeyspaceWithDefaultReplication(t *testing.T) {
+	created, updated := []sdk.Object{}, []sdk.Object{}
+	client := newReferencesClient(getValidSecrets(), getValidConfigMaps(), &created, &updated)
+	var ensured map[string]int
+	cc, connector := getRunningCluster(2, nil, &ensured)
+
+	err := controller.New(cc, client, connector, nil).Sync()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"dc1": 2}, ensured)
+}
+
+func TestClusterController_SyncKeepsKeyspaceReplicationOnScaleDown(t *testing.T) {
+	created, updated := []sdk.Object{}, []sdk.Object{}
+	client := newReferencesClient(getValidSecrets(), getValidConfigMaps(), &created, &updated)
+	var ensured map[string]int
+	keyspace := &cql.Keyspace{Name: "test_cluster", Replication: map[string]int{"dc1": 3}, DurableWrites: true}
+	cc, connector := getRunningCluster(2, keyspace, &ensured)
+
+	err := controller.New(cc, client, connector, nil).Sync()
+	assert.NoError(t, err)
+	assert.Nil(t, ensured)
+}
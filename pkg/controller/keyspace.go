@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/secrets"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultReplicationFactor = 3
+
+// convergeKeyspace creates the primary keyspace of the cluster and keeps its replication in line with the spec
+func (c *ClusterController) convergeKeyspace() error {
+	logrus.Debugln("Converging keyspace")
+
+	keyspace := clusterKeyspace(c.cluster)
+
+	// the cluster name is used as the default which can contain characters cassandra does not allow
+	err := cql.ValidateIdentifier(keyspace)
+	if err != nil {
+		logrus.Warnf("Not managing keyspace for cluster %s, set keyspaceName to a valid name: %v", c.cluster.GetName(), err)
+		return nil
+	}
+
+	session, err := connectCluster(c.driver, c.cqlConnector, c.cluster)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	local, err := c.crossCheckRing(session)
+	if err != nil {
+		return err
+	}
+
+	replication := c.cluster.Spec.Replication
+	if len(replication) == 0 {
+		replication, err = defaultKeyspaceReplication(session, keyspace, local.Datacenter, c.cluster.Spec.Size)
+		if err != nil || replication == nil {
+			return err
+		}
+	}
+
+	err = session.EnsureKeyspace(keyspace, replication, true)
+	if err != nil {
+		return fmt.Errorf("could not converge keyspace %s for cluster %s: %v", keyspace, c.cluster.GetName(), err)
+	}
+
+	return nil
+}
+
+// clusterKeyspace returns the primary keyspace of the cluster, it is named after the cluster unless keyspaceName
+// is set
+func clusterKeyspace(cc *v1alpha1.CassandraCluster) string {
+	if cc.Spec.KeyspaceName != "" {
+		return cc.Spec.KeyspaceName
+	}
+	return cc.GetName()
+}
+
+// connectCluster opens a management session through the internal service of the cluster
+func connectCluster(driver opsdk.Client, connector cql.Connector, cc *v1alpha1.CassandraCluster) (cql.Manager, error) {
+	credentials, err := getCQLCredentials(driver, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	return connectAs(driver, connector, cc, credentials)
+}
+
+// connectAs opens a management session through the internal service of the cluster with the credentials
+func connectAs(driver opsdk.Client, connector cql.Connector, cc *v1alpha1.CassandraCluster, credentials *cql.Credentials) (cql.Manager, error) {
+	opts, err := connectOptions(driver, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	return connector.Connect(ClusterHosts(cc), credentials, opts...)
+}
+
+// ClusterHosts returns the internal service of the cluster as the contact point for CQL sessions
+func ClusterHosts(cc *v1alpha1.CassandraCluster) []string {
+	return []string{fmt.Sprintf("%s-cassandra.%s.svc.cluster.local", cc.GetName(), cc.GetNamespace())}
+}
+
+// getCQLCredentials reads the credentials from the auth secret, no credentials are used when auth is not set
+func getCQLCredentials(driver opsdk.Client, cc *v1alpha1.CassandraCluster) (*cql.Credentials, error) {
+	secretName := authSecretName(cc)
+	if secretName == "" {
+		return nil, nil
+	}
+
+	credentials, err := secrets.NewKubernetesProvider(driver).Credentials(cc.GetNamespace(), secretName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the credentials for cluster %s: %v", cc.GetName(), err)
+	}
+
+	return credentials, nil
+}
+
+// crossCheckRing compares the ring in system.local and system.peers with the host IDs in the cluster status
+// and logs any difference, the local node is returned
+func (c *ClusterController) crossCheckRing(session cql.Manager) (*cql.Node, error) {
+	ring, err := cql.Ring(session)
+	if err != nil {
+		return nil, err
+	}
+	local := &ring[0]
+
+	hostIDs := []string{}
+	for _, node := range c.cluster.Status.Nodes {
+		// bootstrapping nodes are only reported with their streams, they have no host id yet
+		if node.HostID != "" {
+			hostIDs = append(hostIDs, node.HostID)
+		}
+	}
+
+	missing, unreported := cql.CompareHostIDs(ring, local.Datacenter, hostIDs)
+	for _, hostID := range missing {
+		logrus.Warnf("Node %s of cluster %s is ready but not in system.peers", hostID, c.cluster.GetName())
+	}
+	for _, hostID := range unreported {
+		logrus.Warnf("Node %s of cluster %s is in system.peers but is not a ready pod", hostID, c.cluster.GetName())
+	}
+
+	return local, nil
+}
+
+// defaultKeyspaceReplication returns the default replication in the datacenter of the cluster for a keyspace that
+// does not exist yet. An existing keyspace is left as it is, nil is returned, so scaling the cluster down never
+// lowers its replication.
+func defaultKeyspaceReplication(session cql.Manager, keyspace, datacenter string, size int) (map[string]int, error) {
+	current, err := session.GetKeyspace(keyspace)
+	if err != nil || current != nil {
+		return nil, err
+	}
+
+	return map[string]int{datacenter: defaultReplication(size)}, nil
+}
+
+func defaultReplication(size int) int {
+	if size < defaultReplicationFactor {
+		return size
+	}
+	return defaultReplicationFactor
+}
+
+// keyspaceManaged returns true if the cluster is in a phase where DDL can be run against it
+func keyspaceManaged(cc *v1alpha1.CassandraCluster) bool {
+	return cc.Status.Phase == v1alpha1.ClusterPhaseRunning
+}
@@ -1,9 +1,13 @@
 package controller
 
 import (
+	"context"
+	"time"
+
 	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	"github.com/pantheon-systems/cassandra-operator/pkg/metrics"
 	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -19,11 +23,11 @@ const (
 type PodFinalizerController struct {
 	k8sDriver        k8s.Client
 	finalizerManager *k8s.Finalizer
-	nodetoolDriver   *nodetool.Executor
+	nodetoolDriver   nodetool.NodeManager
 }
 
 // NewPodFinalizerController builds a new PodFinalizerController
-func NewPodFinalizerController(k8sDriver k8s.Client, nodetoolDriver *nodetool.Executor) *PodFinalizerController {
+func NewPodFinalizerController(k8sDriver k8s.Client, nodetoolDriver nodetool.NodeManager) *PodFinalizerController {
 	return &PodFinalizerController{
 		k8sDriver:        k8sDriver,
 		finalizerManager: k8s.NewFinalizer(k8sDriver, podFinalizer),
@@ -44,8 +48,8 @@ func (c *PodFinalizerController) Converge(node *corev1.Pod) error {
 	return nil
 }
 
-// Process pod for finalizer
-func (c *PodFinalizerController) Process(node *corev1.Pod) error {
+// Process pod for finalizer, the node is drained and stopped until the context is done
+func (c *PodFinalizerController) Process(ctx context.Context, node *corev1.Pod) error {
 	// if its not a delete candidate then we can just bail (fast path)
 	if !c.finalizerManager.IsDeletionCandidate(node) {
 		return nil
@@ -67,24 +71,34 @@ func (c *PodFinalizerController) Process(node *corev1.Pod) error {
 		return err
 	}
 
+	if cluster.Spec.Paused {
+		// the node is being taken care of by hand, the pod goes away without draining it
+		logrus.Infof("Cluster %s is paused, releasing node %s without draining it", cluster.GetName(), node.GetName())
+		return c.finalizerManager.Remove(node)
+	}
+
 	if cluster.Status.Provisioning() {
 		logrus.Debugf("cluster '%s' is provisioning, cannot change state of node '%s'\n", cluster.GetName(), node.GetName())
 		return nil
 	}
 
-	err = c.nodetoolDriver.Drain(node)
+	start := time.Now()
+	err = c.nodetoolDriver.Drain(ctx, node)
 	if err == nil {
-		err = c.nodetoolDriver.Stop(node)
+		err = c.nodetoolDriver.Stop(ctx, node)
 	}
 
 	if err != nil {
 		// Drain or decomission failed, we do not proceed
 		// with delete
+		metrics.ObserveFinalizer(node.GetNamespace(), cluster.GetName(), start, err)
 		return err
 	}
 
 	// we have successfully drained or decommissioned
 	// we can now proceed with the deletion of the pod
 	// by kubernetes by removing the finalizer
-	return c.finalizerManager.Remove(node)
+	err = c.finalizerManager.Remove(node)
+	metrics.ObserveFinalizer(node.GetNamespace(), cluster.GetName(), start, err)
+	return err
 }
@@ -1,6 +1,7 @@
 package controller_test
 
 import (
+	"context"
 	"errors"
 	"github.com/operator-framework/operator-sdk/pkg/sdk"
 	"github.com/operator-framework/operator-sdk/pkg/util/k8sutil"
@@ -31,12 +32,12 @@ func TestFinalizerController_ProcessNotDeletionCandidate(t *testing.T) {
 
 	obj := controller.NewPodFinalizerController(&mockK8sDriver, nodetoolDriver)
 
-	err := obj.Process(&testPod)
+	err := obj.Process(context.Background(), &testPod)
 
 	assert.NoError(t, err)
 
 	testPod.ObjectMeta.DeletionTimestamp = &now
-	err = obj.Process(&testPod)
+	err = obj.Process(context.Background(), &testPod)
 	assert.NoError(t, err)
 }
 
@@ -59,7 +60,7 @@ func TestFinalizerController_ProcessNotPartOfCluster(t *testing.T) {
 
 	obj := controller.NewPodFinalizerController(&mockK8sDriver, nodetoolDriver)
 
-	err := obj.Process(&testPod)
+	err := obj.Process(context.Background(), &testPod)
 	assert.NoError(t, err)
 }
 
@@ -81,7 +82,7 @@ func TestFinalizerController_ProcessGetClusterError(t *testing.T) {
 
 	obj := controller.NewPodFinalizerController(&mockK8sDriver, nodetoolDriver)
 
-	err := obj.Process(&testPod)
+	err := obj.Process(context.Background(), &testPod)
 	assert.Error(t, err)
 }
 
@@ -114,7 +115,7 @@ func TestFinalizerController_ProcessStatefulSetGetError(t *testing.T) {
 
 	obj := controller.NewPodFinalizerController(&mockK8sDriver, nodetoolDriver)
 
-	err := obj.Process(&testPod)
+	err := obj.Process(context.Background(), &testPod)
 	assert.Error(t, err)
 }
 
@@ -162,7 +163,7 @@ func TestFinalizerController_ProcessDrainErrors(t *testing.T) {
 			}
 			return k8sutil.RuntimeObjectIntoRuntimeObject(statefulSet, into)
 		},
-		RunCallback: func(pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+		RunCallback: func(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
 			if command[1] == "drain" {
 				calledDrain = true
 			}
@@ -177,7 +178,7 @@ func TestFinalizerController_ProcessDrainErrors(t *testing.T) {
 
 	obj := controller.NewPodFinalizerController(&mockK8sDriver, nodetoolDriver)
 
-	err := obj.Process(&testPod)
+	err := obj.Process(context.Background(), &testPod)
 	assert.Error(t, err)
 	assert.True(t, calledDrain)
 	assert.False(t, calledUpdate)
@@ -226,7 +227,7 @@ func TestFinalizerController_ProcessDrainNoErrors(t *testing.T) {
 			}
 			return k8sutil.RuntimeObjectIntoRuntimeObject(statefulSet, into)
 		},
-		RunCallback: func(pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+		RunCallback: func(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
 			if command[1] == "drain" {
 				calledDrain = true
 			}
@@ -240,11 +241,59 @@ func TestFinalizerController_ProcessDrainNoErrors(t *testing.T) {
 
 	obj := controller.NewPodFinalizerController(&mockK8sDriver, nodetoolDriver)
 
-	err := obj.Process(&testPod)
+	err := obj.Process(context.Background(), &testPod)
 	assert.NoError(t, err)
 	assert.True(t, calledDrain)
 }
 
+func TestFinalizerController_ProcessPausedCluster(t *testing.T) {
+	now := metav1.NewTime(time.Now())
+	testPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			DeletionTimestamp: &now,
+			Finalizers:        []string{"finalizer.cassandra.database.pantheon.io/v1alpha1"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "cassandra",
+				},
+			},
+		},
+	}
+
+	cluster := &v1alpha1.CassandraCluster{
+		Spec: v1alpha1.ClusterSpec{
+			Size:   3,
+			Paused: true,
+		},
+	}
+
+	var updated *corev1.Pod
+	mockK8sDriver := k8s.MockClient{
+		GetCallback: func(into sdk.Object, opts ...sdk.GetOption) error {
+			return k8sutil.RuntimeObjectIntoRuntimeObject(cluster, into)
+		},
+		RunCallback: func(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+			t.Errorf("unexpected nodetool %s on a paused cluster", command[1])
+			return "", "", nil
+		},
+		UpdateCallback: func(object sdk.Object) error {
+			updated = object.(*corev1.Pod)
+			return nil
+		},
+	}
+	nodetoolDriver := nodetool.NewExecutor(&mockK8sDriver)
+
+	obj := controller.NewPodFinalizerController(&mockK8sDriver, nodetoolDriver)
+
+	err := obj.Process(context.Background(), &testPod)
+	assert.NoError(t, err)
+	if assert.NotNil(t, updated) {
+		assert.Empty(t, updated.Finalizers)
+	}
+}
+
 // TODO: Complete decommission unit tests
 // func TestFinalizerController_ProcessDecommissionNoErrors(t *testing.T) {
 // 	testPod := corev1.Pod{
@@ -288,7 +337,7 @@ func TestFinalizerController_ProcessDrainNoErrors(t *testing.T) {
 // 			}
 // 			return k8sutil.RuntimeObjectIntoRuntimeObject(statefulSet, into)
 // 		},
-// 		RunCallback: func(pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
+// 		RunCallback: func(ctx context.Context, pod *corev1.Pod, containerIdx int, command []string) (string, string, error) {
 // 			if command[1] == "decommission" {
 // 				calledDecom = true
 // 			}
@@ -308,7 +357,7 @@ func TestFinalizerController_ProcessDrainNoErrors(t *testing.T) {
 
 // 	obj := controller.NewPodFinalizerController(&mockK8sDriver, nodetoolDriver)
 
-// 	err := obj.Process(&testPod)
+// 	err := obj.Process(context.Background(), &testPod)
 // 	assert.NoError(t, err)
 // 	assert.True(t, calledDecom)
 // }
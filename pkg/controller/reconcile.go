@@ -2,22 +2,49 @@ package controller
 
 import (
 	"github.com/operator-framework/operator-sdk/pkg/util/k8sutil"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
 	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
 	"github.com/sirupsen/logrus"
 )
 
 // reconcile brings the cassandra cluster in kube to the specified state
 func (c *ClusterController) reconcile() error {
+	err := c.convergeProvidedSecrets()
+	if err != nil {
+		return err
+	}
+
 	saName, err := c.convergeServiceAccount()
 	if err != nil {
 		return err
 	}
 
+	if authEnabled(c.cluster) {
+		err = c.convergeSuperuserSecret()
+		if err != nil {
+			return err
+		}
+	}
+
 	err = c.convergeServices()
 	if err != nil {
 		return err
 	}
-	err = c.convergeStatefulSet(saName)
+
+	certsRevision := ""
+	if resource.TLSManaged(c.cluster) {
+		certsRevision, err = c.convergeCertificates()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.convergeStatefulSet(saName, certsRevision)
+	if err != nil {
+		return err
+	}
+
+	err = c.convergeBroadcastAddresses()
 	if err != nil {
 		return err
 	}
@@ -27,10 +54,43 @@ func (c *ClusterController) reconcile() error {
 		if err != nil {
 			return err
 		}
+		c.observeRepairs()
+	}
+
+	if policy := resource.Monitoring(c.cluster); policy != nil && policy.ServiceMonitor {
+		err = c.convergeServiceMonitor()
+		if err != nil {
+			return err
+		}
 	}
 
-	if c.cluster.Spec.EnablePodDisruptionBudget {
-		err = c.convergeDisruptionBudget()
+	// a disabled PodDisruptionBudget is converged as well so it is removed
+	err = c.convergeDisruptionBudget()
+	if err != nil {
+		return err
+	}
+
+	if certsRevision != "" && c.cluster.Status.Phase == v1alpha1.ClusterPhaseRunning {
+		err = c.rollCertificates(certsRevision)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.pruneChildren()
+	if err != nil {
+		return err
+	}
+
+	if keyspaceManaged(c.cluster) {
+		if authEnabled(c.cluster) {
+			err = c.convergeSuperuser()
+			if err != nil {
+				return err
+			}
+		}
+
+		err = c.convergeKeyspace()
 		if err != nil {
 			return err
 		}
@@ -45,22 +105,50 @@ func (c *ClusterController) convergeDisruptionBudget() error {
 	return err
 }
 
+func (c *ClusterController) convergeBroadcastAddresses() error {
+	logrus.Debugln("Converging broadcast addresses")
+	_, err := resource.NewBroadcastAddresses(c.cluster).Reconcile(c.driver)
+	return err
+}
+
+func (c *ClusterController) pruneChildren() error {
+	logrus.Debugln("Pruning children")
+	_, err := resource.NewPruner(c.cluster).Prune(c.driver)
+	return err
+}
+
+func (c *ClusterController) convergeServiceMonitor() error {
+	logrus.Debugln("Converging ServiceMonitor")
+	_, err := resource.NewServiceMonitor(c.cluster).Reconcile(c.driver)
+	return err
+}
+
 func (c *ClusterController) convergeRepairCronJob() error {
 	logrus.Debugln("Converging repair cron job")
 	_, err := resource.NewRepairCronJob(c.cluster).Reconcile(c.driver)
 	return err
 }
 
-func (c *ClusterController) convergeStatefulSet(serviceAccountName string) error {
+func (c *ClusterController) convergeStatefulSet(serviceAccountName, certsRevision string) error {
 	logrus.Debugln("Converging statefulset")
 
-	_, err := resource.NewStatefulSet(
+	statefulSet := resource.NewStatefulSet(
 		c.cluster,
 		resource.WithServiceName(c.headlessServiceName),
 		resource.WithServiceAccountName(serviceAccountName),
-	).Reconcile(c.driver)
+		resource.WithCertificatesRevision(certsRevision),
+		resource.WithScaleUpHeld(len(c.cluster.Status.Members.Streaming) > 0),
+	)
+	_, err := statefulSet.Reconcile(c.driver)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if replicas, scaledUp := statefulSet.ScaledUp(); scaledUp {
+		return c.scheduleCleanup(int(replicas))
+	}
+
+	return nil
 }
 
 func (c *ClusterController) convergeServiceAccount() (string, error) {
@@ -76,10 +164,13 @@ func (c *ClusterController) convergeServiceAccount() (string, error) {
 
 // convergeService creates or updates the services required by the operator
 func (c *ClusterController) convergeServices() error {
-	logrus.Debugln("Converging public service")
-	_, err := resource.NewService(c.cluster, resource.WithServiceType(resource.ServiceTypePublicLB)).Reconcile(c.driver)
-	if err != nil {
-		return err
+	var err error
+	if resource.ClientExposure(c.cluster).Type != v1alpha1.ExposureNone {
+		logrus.Debugln("Converging public service")
+		_, err = resource.NewService(c.cluster, resource.WithServiceType(resource.ServiceTypePublicLB)).Reconcile(c.driver)
+		if err != nil {
+			return err
+		}
 	}
 	logrus.Debugln("Converging internal service")
 	_, err = resource.NewService(c.cluster, resource.WithServiceType(resource.ServiceTypeInternal)).Reconcile(c.driver)
@@ -97,7 +188,7 @@ func (c *ClusterController) convergeServices() error {
 		return err
 	}
 
-	if c.cluster.Spec.EnablePublicPodServices {
+	if resource.InternodeExposure(c.cluster).Type != v1alpha1.ExposureNone {
 		logrus.Debugln("Converging public pod services")
 		for i := 0; i < c.cluster.Spec.Size; i++ {
 			_, err = resource.NewService(
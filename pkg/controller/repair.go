@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/metrics"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// suspendRepairs suspends the repair cron job of a paused cluster, it is the only object the operator changes
+// while the cluster is paused and it is resumed by the next reconcile after the cluster is unpaused
+func (c *ClusterController) suspendRepairs() error {
+	if c.cluster.Spec.Repair == nil {
+		return nil
+	}
+
+	return c.convergeRepairCronJob()
+}
+
+// observeRepairs records when the last repair job of the cluster succeeded, failing to list the jobs only
+// leaves the metric stale so it does not fail the reconcile. The repairs of single keyspaces do not count.
+func (c *ClusterController) observeRepairs() {
+	jobs := &batchv1.JobList{
+		TypeMeta: resource.GetJobTypeMeta(),
+	}
+	keyspaceRepairs, err := labels.NewRequirement(resource.RepairLabel, selection.NotEquals, []string{resource.RepairLabelKeyspace})
+	if err != nil {
+		logrus.Warnf("Could not select repair jobs of cluster %s: %v", c.cluster.GetName(), err)
+		return
+	}
+	selector := labels.SelectorFromSet(map[string]string{"cluster": c.cluster.GetName()}).Add(*keyspaceRepairs)
+	listOpts := &metav1.ListOptions{
+		LabelSelector: selector.String(),
+	}
+	err = c.driver.List(c.cluster.GetNamespace(), jobs, sdk.WithListOptions(listOpts))
+	if err != nil {
+		logrus.Warnf("Could not list repair jobs of cluster %s: %v", c.cluster.GetName(), err)
+		return
+	}
+
+	var last time.Time
+	for _, job := range jobs.Items {
+		if job.Status.Succeeded > 0 && job.Status.CompletionTime != nil && job.Status.CompletionTime.After(last) {
+			last = job.Status.CompletionTime.Time
+		}
+	}
+
+	if !last.IsZero() {
+		metrics.SetRepairLastSuccess(c.cluster, last)
+	}
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/sirupsen/logrus"
+)
+
+// convergeProvidedSecrets copies the keystore and credentials the cluster keeps in vault into secrets owned by
+// the cluster, the nodes mount the keystore and the operator logs in with the credentials from there
+func (c *ClusterController) convergeProvidedSecrets() error {
+	if !resource.VaultKeystore(c.cluster) && !resource.VaultCredentials(c.cluster) {
+		return nil
+	}
+
+	logrus.Debugln("Converging provided secrets")
+	if c.secretProvider == nil {
+		return fmt.Errorf("cluster %s reads its secrets from vault but the operator has no vault address", c.cluster.GetName())
+	}
+
+	if resource.VaultKeystore(c.cluster) {
+		path := c.cluster.Spec.Secrets.KeystorePath
+		files, err := c.secretProvider.Keystore(c.cluster.GetNamespace(), path)
+		if err != nil {
+			return err
+		}
+
+		if files == nil {
+			return fmt.Errorf("vault secret %s with the keystore of cluster %s does not exist", path, c.cluster.GetName())
+		}
+
+		_, err = resource.NewProvidedSecret(c.cluster, resource.VaultKeystoreSecretName(c.cluster), files).Reconcile(c.driver)
+		if err != nil {
+			return err
+		}
+	}
+
+	if resource.VaultCredentials(c.cluster) {
+		credentials, err := c.secretProvider.Credentials(c.cluster.GetNamespace(), c.cluster.Spec.Secrets.CredentialsPath)
+		if err != nil {
+			return err
+		}
+
+		data := map[string][]byte{
+			cql.UsernameKey: []byte(credentials.Username),
+			cql.PasswordKey: []byte(credentials.Password),
+		}
+		_, err = resource.NewProvidedSecret(c.cluster, resource.VaultCredentialsSecretName(c.cluster), data).Reconcile(c.driver)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
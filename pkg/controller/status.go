@@ -1,22 +1,35 @@
 package controller
 
 import (
+	"context"
 	"fmt"
+	"time"
+
 	"github.com/operator-framework/operator-sdk/pkg/sdk"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
 	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
 
 	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
+const (
+	reasonPaused  = "Paused"
+	reasonResumed = "Resumed"
+
+	// nodeMetricsRefreshInterval bounds how stale the uptime and heap usage of the nodes in the status get
+	nodeMetricsRefreshInterval = 5 * time.Minute
+)
+
 // nodeStatusReporter is an interface that constricts the nodeStatusReporter implentation
 // needed behavior. So that we can better decouple this classes required contract vs the implementation
 type nodeStatusReporter interface {
-	GetStatus(node *corev1.Pod) (map[string]*nodetool.Status, error)
-	GetHostID(node *corev1.Pod) (string, error)
+	GetStatus(ctx context.Context, node *corev1.Pod) (map[string]*nodetool.Status, error)
+	GetInfo(ctx context.Context, node *corev1.Pod) (*nodetool.Info, error)
+	GetNetstats(ctx context.Context, node *corev1.Pod) (*nodetool.Netstats, error)
 }
 
 // nodeStatusReporter is an interface that constricts the nodeStatusReporter implentation
@@ -40,29 +53,88 @@ func NewStatusManager(statusReporter nodeStatusReporter, listerUpdater resourceL
 	}
 }
 
-// Update calculates the current status and updates the k8s resource status accordingly
-func (c *ClusterStatusManager) Update(cc *v1alpha1.CassandraCluster) error {
-	currentStatus, err := c.getClusterStatus(cc)
+// Update calculates the current status and updates the k8s resource status accordingly, the nodes are queried
+// until the context is done
+func (c *ClusterStatusManager) Update(ctx context.Context, cc *v1alpha1.CassandraCluster) error {
+	currentStatus, err := c.getClusterStatus(ctx, cc)
 	if err != nil {
 		return err
 	}
+	setPausedCondition(cc, currentStatus)
 
+	now := time.Now()
+	if !statusChanged(&cc.Status, currentStatus) && !nodeMetricsStale(&cc.Status, now) {
+		return nil
+	}
+
+	currentStatus.NodeMetricsObservedAt = &metav1.Time{Time: now}
 	currentStatus.DeepCopyInto(&cc.Status)
 	return c.listerUpdater.Update(cc)
 }
 
-func (c *ClusterStatusManager) getClusterStatus(cc *v1alpha1.CassandraCluster) (*v1alpha1.ClusterStatus, error) {
+// statusChanged compares the statuses without the metrics of the nodes, which change on every observation
+func statusChanged(previous, current *v1alpha1.ClusterStatus) bool {
+	return !equality.Semantic.DeepEqual(withoutNodeMetrics(previous), withoutNodeMetrics(current))
+}
+
+// nodeMetricsStale returns true when the metrics of the nodes in the status are older than the refresh interval
+func nodeMetricsStale(status *v1alpha1.ClusterStatus, now time.Time) bool {
+	if len(status.Nodes) == 0 {
+		return false
+	}
+	return status.NodeMetricsObservedAt == nil || now.Sub(status.NodeMetricsObservedAt.Time) >= nodeMetricsRefreshInterval
+}
+
+func withoutNodeMetrics(status *v1alpha1.ClusterStatus) *v1alpha1.ClusterStatus {
+	status = status.DeepCopy()
+	status.NodeMetricsObservedAt = nil
+	for i := range status.Nodes {
+		status.Nodes[i].UptimeSeconds = 0
+		status.Nodes[i].HeapUsedMB = 0
+		status.Nodes[i].HeapTotalMB = 0
+		status.Nodes[i].HeapUsedPercent = 0
+	}
+	return status
+}
+
+// setPausedCondition reports whether the reconciliation of the cluster is paused, clusters that were never paused
+// do not get the condition
+func setPausedCondition(cc *v1alpha1.CassandraCluster, status *v1alpha1.ClusterStatus) {
+	if !cc.Spec.Paused && status.GetCondition(v1alpha1.ClusterConditionPaused) == nil {
+		return
+	}
+
+	condition := v1alpha1.ClusterCondition{
+		Type:               v1alpha1.ClusterConditionPaused,
+		Status:             corev1.ConditionFalse,
+		Reason:             reasonResumed,
+		LastTransitionTime: metav1.Now(),
+	}
+	if cc.Spec.Paused {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = reasonPaused
+		condition.Message = "reconciliation is paused by spec.paused"
+	}
+	status.SetCondition(condition)
+}
+
+func (c *ClusterStatusManager) getClusterStatus(ctx context.Context, cc *v1alpha1.CassandraCluster) (*v1alpha1.ClusterStatus, error) {
 	pods, err := c.getClusterPods(cc.GetName(), cc.GetNamespace(), cc.GetLabels())
 	if err != nil {
 		return nil, err
 	}
 
+	// the status is built on a copy, the conditions are set in place and must not change cc.Status before the
+	// statuses are compared
+	currentStatus := *cc.Status.DeepCopy()
+
 	// we are unknown till we are known
 	status := &v1alpha1.ClusterStatus{
-		Phase: v1alpha1.ClusterPhaseUnknown,
+		Phase:      v1alpha1.ClusterPhaseUnknown,
+		Conditions: currentStatus.Conditions,
+		Cleanup:    currentStatus.Cleanup,
 	}
 
-	currentStatus := cc.Status
 	actualPodCount := len(pods.Items)
 
 	// RULE: None/Initial and No Pods -> Initial
@@ -72,11 +144,14 @@ func (c *ClusterStatusManager) getClusterStatus(cc *v1alpha1.CassandraCluster) (
 	}
 
 	// loop through pods and add to status buckets in status object
-	kubeNodeStatuses, err := c.groupPodsByState(pods.Items)
+	// the streams are only checked while they hold back adding the next node or cleaning up the nodes
+	checkStreams := actualPodCount < cc.Spec.Size || !cc.Status.Cleanup.Done()
+	kubeNodeStatuses, nodeInfos, err := c.groupPodsByState(ctx, pods.Items, cc.Status.Nodes, checkStreams)
 	if err != nil {
 		return nil, err
 	}
 	status.Members = *kubeNodeStatuses
+	status.Nodes = nodeInfos
 
 	// we have not kicked off the creation
 	// for the cluster
@@ -136,8 +211,11 @@ func (c *ClusterStatusManager) getClusterStatus(cc *v1alpha1.CassandraCluster) (
 	return status, nil
 }
 
-func (c *ClusterStatusManager) groupPodsByState(pods []corev1.Pod) (*v1alpha1.NodesStatus, error) {
+// groupPodsByState bins the pods by the state of their node, the streams of the joining and leaving nodes are
+// compared to the previous node infos to estimate when they complete
+func (c *ClusterStatusManager) groupPodsByState(ctx context.Context, pods []corev1.Pod, previous []v1alpha1.NodeInfo, checkStreams bool) (*v1alpha1.NodesStatus, []v1alpha1.NodeInfo, error) {
 	var nodeStatuses map[string]*nodetool.Status
+	var nodeInfos []v1alpha1.NodeInfo
 	var err error
 
 	nodeStates := &v1alpha1.NodesStatus{}
@@ -175,7 +253,7 @@ func (c *ClusterStatusManager) groupPodsByState(pods []corev1.Pod) (*v1alpha1.No
 			// then we error if it is not running, this is to
 			// prevent them from adding phases and us rolling forward
 			// thinking its running
-			return nil, fmt.Errorf("Unsupported PodPhase: %s", pod.Status.Phase)
+			return nil, nil, fmt.Errorf("Unsupported PodPhase: %s", pod.Status.Phase)
 		}
 
 		// pod is corev1.Running, now check if it is ready
@@ -191,42 +269,153 @@ func (c *ClusterStatusManager) groupPodsByState(pods []corev1.Pod) (*v1alpha1.No
 			// or is starting up, ie the healthcheck which uses nodetool does not
 			// succeed
 			nodeStates.Creating = append(nodeStates.Creating, podName)
+
+			// a node is not ready while it bootstraps, the data it receives is reported while the cluster scales up
+			if checkStreams {
+				streams := c.getBootstrapProgress(ctx, &pod, findStreamProgress(previous, podName))
+				if streams != nil {
+					nodeInfos = append(nodeInfos, v1alpha1.NodeInfo{Name: podName, Streams: streams})
+				}
+			}
 			continue
 		}
 
 		// if it is not set, we fetch the nodetool status for all nodes
 		// then memoize the result for the rest of the pods
 		if len(nodeStatuses) == 0 {
-			nodeStatuses, err = c.nodeStatusReporter.GetStatus(&pod)
+			nodeStatuses, err = c.nodeStatusReporter.GetStatus(ctx, &pod)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
 
-		// getting cassandra node id
-		hostID, err := c.nodeStatusReporter.GetHostID(&pod)
+		// getting cassandra node id, heap and uptime
+		info, err := c.nodeStatusReporter.GetInfo(ctx, &pod)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+		nodeInfo := buildNodeInfo(podName, info)
 
-		switch nodeStatuses[hostID].State {
+		nodeStatus, ok := nodeStatuses[info.ID]
+		if !ok {
+			nodeInfos = append(nodeInfos, nodeInfo)
+			nodeStates.Unready = append(nodeStates.Unready, podName)
+			continue
+		}
+
+		// joining and leaving nodes always stream, the streams of ready nodes are only checked when asked to
+		streams := checkStreams
+		switch nodeStatus.State {
 		case nodetool.NodeStateJoining:
 			nodeStates.Joining = append(nodeStates.Joining, podName)
+			streams = true
 		case nodetool.NodeStateNormal:
 			nodeStates.Ready = append(nodeStates.Ready, podName)
 		case nodetool.NodeStateLeaving:
 			nodeStates.Leaving = append(nodeStates.Leaving, podName)
+			streams = true
 		default:
 			nodeStates.Unready = append(nodeStates.Unready, podName)
+			streams = false
 		}
-		continue
+
+		if !streams {
+			nodeInfos = append(nodeInfos, nodeInfo)
+			continue
+		}
+
+		netstats, err := c.nodeStatusReporter.GetNetstats(ctx, &pod)
+		if err != nil {
+			return nil, nil, err
+		}
+		if nodeStatus.State == nodetool.NodeStateNormal &&
+			(netstats.Mode != nodetool.NodeModeNormal || netstats.Streaming) {
+			nodeStates.Streaming = append(nodeStates.Streaming, podName)
+		}
+		nodeInfo.Streams = buildStreamProgress(netstats.Sessions, findStreamProgress(previous, podName), metav1.Now())
+		nodeInfos = append(nodeInfos, nodeInfo)
+	}
+
+	return nodeStates, nodeInfos, nil
+}
+
+func buildNodeInfo(podName string, info *nodetool.Info) v1alpha1.NodeInfo {
+	return v1alpha1.NodeInfo{
+		Name:            podName,
+		HostID:          info.ID,
+		UptimeSeconds:   info.UptimeSeconds,
+		HeapUsedMB:      int64(info.HeapUsedMB),
+		HeapTotalMB:     int64(info.HeapTotalMB),
+		HeapUsedPercent: int32(info.HeapUsedPercent()),
+	}
+}
+
+// getBootstrapProgress returns the stream progress of a node that is joining the ring, nil if it is not joining
+// or not started far enough to tell
+func (c *ClusterStatusManager) getBootstrapProgress(ctx context.Context, pod *corev1.Pod, previous *v1alpha1.StreamProgress) *v1alpha1.StreamProgress {
+	netstats, err := c.nodeStatusReporter.GetNetstats(ctx, pod)
+	if err != nil || netstats == nil || netstats.Mode != nodetool.NodeModeJoining {
+		return nil
+	}
+	return buildStreamProgress(netstats.Sessions, previous, metav1.Now())
+}
+
+// buildStreamProgress sums the streaming sessions of a node, the completion is estimated from the rate the node
+// streamed at since the previous progress
+func buildStreamProgress(sessions []nodetool.StreamSession, previous *v1alpha1.StreamProgress, now metav1.Time) *v1alpha1.StreamProgress {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	progress := &v1alpha1.StreamProgress{ObservedAt: now}
+	for _, session := range sessions {
+		progress.Sessions = append(progress.Sessions, v1alpha1.StreamSessionStatus{
+			Operation:  session.Operation,
+			Peer:       session.Peer,
+			Direction:  string(session.Direction),
+			FilesDone:  session.DoneFiles,
+			FilesTotal: session.TotalFiles,
+			BytesDone:  session.DoneBytes,
+			BytesTotal: session.TotalBytes,
+		})
+		progress.BytesDone += session.DoneBytes
+		progress.BytesTotal += session.TotalBytes
+	}
+
+	if previous == nil {
+		return progress
+	}
+
+	// a new streaming plan may start from fewer bytes than the previous one streamed
+	elapsed := now.Sub(previous.ObservedAt.Time)
+	streamed := progress.BytesDone - previous.BytesDone
+	if elapsed <= 0 || streamed <= 0 {
+		return progress
 	}
 
-	return nodeStates, nil
+	remaining := progress.BytesTotal - progress.BytesDone
+	completion := metav1.NewTime(now.Add(time.Duration(float64(elapsed) * float64(remaining) / float64(streamed))))
+	progress.EstimatedCompletion = &completion
+
+	return progress
+}
+
+// findStreamProgress returns the stream progress the node had in the previous status, if any
+func findStreamProgress(nodeInfos []v1alpha1.NodeInfo, podName string) *v1alpha1.StreamProgress {
+	for _, nodeInfo := range nodeInfos {
+		if nodeInfo.Name == podName {
+			return nodeInfo.Streams
+		}
+	}
+	return nil
 }
 
 // GetClusterPods retrieves the pods for a specific cluster in a specific namespace
 func (c *ClusterStatusManager) getClusterPods(clusterName, namespace string, clusterLabels map[string]string) (*corev1.PodList, error) {
+	return listClusterPods(c.listerUpdater, clusterName, namespace, clusterLabels)
+}
+
+func listClusterPods(lister resourceListerUpdater, clusterName, namespace string, clusterLabels map[string]string) (*corev1.PodList, error) {
 	pods := &corev1.PodList{
 		TypeMeta: resource.GetPodTypeMeta(),
 	}
@@ -245,7 +434,7 @@ func (c *ClusterStatusManager) getClusterPods(clusterName, namespace string, clu
 		LabelSelector: labels.SelectorFromSet(labelSelector).String(),
 	}
 
-	err := c.listerUpdater.List(namespace, pods, sdk.WithListOptions(listOpts))
+	err := lister.List(namespace, pods, sdk.WithListOptions(listOpts))
 	if err != nil {
 		return nil, fmt.Errorf("Could not list pods for cluster %s: %s", clusterName, err)
 	}
@@ -0,0 +1,295 @@
+package controller_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/simulator"
+	"github.com/pantheon-systems/cassandra-operator/pkg/controller"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// scenario drives a cluster through the fake kubernetes api and a simulated ring, the pods stand in for the
+// statefulset and their readiness for the readiness probe
+type scenario struct {
+	t        *testing.T
+	client   *k8s.FakeClient
+	ring     *simulator.Ring
+	executor *nodetool.Executor
+	manager  *controller.ClusterStatusManager
+	pods     int
+}
+
+func newScenario(t *testing.T, size int) *scenario {
+	ring := simulator.NewRing(simulator.WithJoinDuration(time.Minute), simulator.WithStreamDuration(2*time.Minute))
+	client := k8s.NewFakeClient(getCassandraCluster(size, ""))
+	client.RunCallback = ring.Run
+	executor := nodetool.NewExecutor(client)
+
+	return &scenario{
+		t:        t,
+		client:   client,
+		ring:     ring,
+		executor: executor,
+		manager:  controller.NewStatusManager(executor, client),
+	}
+}
+
+func (s *scenario) podName(i int) string {
+	return fmt.Sprintf("test-cluster-cassandra-%d", i)
+}
+
+func (s *scenario) cluster() *v1alpha1.CassandraCluster {
+	cc := getCassandraCluster(0, "")
+	assert.NoError(s.t, s.client.Get(cc))
+	return cc
+}
+
+// resize changes the size of the cluster in its spec
+func (s *scenario) resize(size int) {
+	cc := s.cluster()
+	cc.Spec.Size = size
+	assert.NoError(s.t, s.client.Update(cc))
+}
+
+// schedule creates the next pod, it is pending until its node is started
+func (s *scenario) schedule() {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.podName(s.pods),
+			Namespace: "testnamespace",
+			Labels: map[string]string{
+				"cluster": "test-cluster",
+				"type":    "cassandra-node",
+				"state":   "serving",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "cassandra"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	assert.NoError(s.t, s.client.Create(pod))
+	s.pods++
+}
+
+// start starts the node of the last pod
+func (s *scenario) start() {
+	s.ring.AddNode(s.podName(s.pods-1), fmt.Sprintf("10.0.0.%d", s.pods))
+	s.probe()
+}
+
+// remove deletes the last pod
+func (s *scenario) remove() {
+	s.pods--
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: s.podName(s.pods), Namespace: "testnamespace"}}
+	assert.NoError(s.t, s.client.Delete(pod))
+}
+
+// decommission decommissions the node of the last pod
+func (s *scenario) decommission() {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: s.podName(s.pods - 1), Namespace: "testnamespace"}}
+	assert.NoError(s.t, s.client.Get(pod))
+	assert.NoError(s.t, s.executor.Decommission(context.Background(), pod))
+	s.probe()
+}
+
+// advance moves the time of the ring forward and probes the pods
+func (s *scenario) advance(d time.Duration) {
+	s.ring.Advance(d)
+	s.probe()
+}
+
+// probe sets the readiness of the running pods from their nodes
+func (s *scenario) probe() {
+	for i := 0; i < s.pods; i++ {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: s.podName(i), Namespace: "testnamespace"}}
+		assert.NoError(s.t, s.client.Get(pod))
+		if _, started := s.ring.State(pod.Name); !started && pod.Status.Phase == corev1.PodPending {
+			continue
+		}
+
+		ready := corev1.ConditionFalse
+		if s.ring.Ready(pod.Name) {
+			ready = corev1.ConditionTrue
+		}
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.PodIP = fmt.Sprintf("10.0.0.%d", i+1)
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}
+		assert.NoError(s.t, s.client.Update(pod))
+	}
+}
+
+// update updates the status of the cluster and returns it
+func (s *scenario) update() v1alpha1.ClusterStatus {
+	cc := s.cluster()
+	assert.NoError(s.t, s.manager.Update(context.Background(), cc))
+	return s.cluster().Status
+}
+
+func (s *scenario) expectPhase(phase v1alpha1.ClusterPhase) v1alpha1.ClusterStatus {
+	status := s.update()
+	assert.Equal(s.t, phase, status.Phase, "members: %+v", status.Members)
+	return status
+}
+
+func TestClusterStatusScenario_CreateScaleUpScaleDown(t *testing.T) {
+	s := newScenario(t, 3)
+
+	// create with 3 nodes, one at a time
+	s.expectPhase(v1alpha1.ClusterPhaseInitial)
+	s.schedule()
+	s.expectPhase(v1alpha1.ClusterPhaseCreating)
+	s.start()
+	s.expectPhase(v1alpha1.ClusterPhaseInitializing)
+	for i := 1; i < 3; i++ {
+		s.schedule()
+		s.start()
+		status := s.expectPhase(v1alpha1.ClusterPhaseInitializing)
+		assert.Equal(t, []string{s.podName(i)}, status.Members.Creating)
+		s.advance(time.Minute)
+	}
+	status := s.expectPhase(v1alpha1.ClusterPhaseRunning)
+	assert.Len(t, status.Members.Ready, 3)
+	assert.Len(t, status.Nodes, 3)
+
+	// scale to 5, the next pod is scheduled as soon as the previous node joined
+	s.resize(5)
+	s.schedule()
+	s.expectPhase(v1alpha1.ClusterPhaseScaling)
+	for i := 3; i < 5; i++ {
+		s.start()
+		s.expectPhase(v1alpha1.ClusterPhaseScaling)
+		s.advance(time.Minute)
+		if i < 4 {
+			s.schedule()
+			s.expectPhase(v1alpha1.ClusterPhaseScaling)
+		}
+	}
+	status = s.expectPhase(v1alpha1.ClusterPhaseRunning)
+	assert.Len(t, status.Members.Ready, 5)
+
+	// scale to 2, the next node is decommissioned as soon as the pod of the previous one is removed
+	s.resize(2)
+	for s.pods > 2 {
+		s.decommission()
+		status = s.expectPhase(v1alpha1.ClusterPhaseScaling)
+		assert.Equal(t, []string{s.podName(s.pods - 1)}, status.Members.Leaving)
+
+		// streaming takes two minutes
+		s.advance(time.Minute)
+		s.expectPhase(v1alpha1.ClusterPhaseScaling)
+		s.advance(time.Minute)
+		s.remove()
+	}
+	status = s.expectPhase(v1alpha1.ClusterPhaseRunning)
+	assert.Len(t, status.Members.Ready, 2)
+	assert.Empty(t, status.Members.Leaving)
+}
+
+func TestClusterStatusScenario_LoseNode(t *testing.T) {
+	s := newScenario(t, 3)
+	s.expectPhase(v1alpha1.ClusterPhaseInitial)
+	for i := 0; i < 3; i++ {
+		s.schedule()
+		s.start()
+		s.update()
+		s.advance(time.Minute)
+	}
+	s.expectPhase(v1alpha1.ClusterPhaseRunning)
+
+	// the readiness probe of the pod fails while its node is down, like a pod that is still starting
+	s.ring.MarkDown(s.podName(1))
+	s.probe()
+	status := s.expectPhase(v1alpha1.ClusterPhaseScaling)
+	assert.Equal(t, []string{s.podName(1)}, status.Members.Creating)
+	assert.Len(t, status.Members.Ready, 2)
+
+	s.ring.MarkUp(s.podName(1))
+	s.probe()
+	status = s.expectPhase(v1alpha1.ClusterPhaseRunning)
+	assert.Len(t, status.Members.Ready, 3)
+}
+
+func TestClusterStatusScenario_Paused(t *testing.T) {
+	s := newScenario(t, 1)
+	s.expectPhase(v1alpha1.ClusterPhaseInitial)
+	s.schedule()
+	s.start()
+	s.update()
+	s.expectPhase(v1alpha1.ClusterPhaseRunning)
+	assert.Nil(t, s.cluster().Status.GetCondition(v1alpha1.ClusterConditionPaused))
+
+	// the status is still reported while the cluster is paused
+	cc := s.cluster()
+	cc.Spec.Paused = true
+	assert.NoError(t, s.client.Update(cc))
+	s.ring.MarkDown(s.podName(0))
+	s.probe()
+	status := s.update()
+	assert.Equal(t, []string{s.podName(0)}, status.Members.Creating)
+	if condition := status.GetCondition(v1alpha1.ClusterConditionPaused); assert.NotNil(t, condition) {
+		assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	}
+
+	cc = s.cluster()
+	cc.Spec.Paused = false
+	assert.NoError(t, s.client.Update(cc))
+	s.ring.MarkUp(s.podName(0))
+	s.probe()
+	status = s.expectPhase(v1alpha1.ClusterPhaseRunning)
+	if condition := status.GetCondition(v1alpha1.ClusterConditionPaused); assert.NotNil(t, condition) {
+		assert.Equal(t, corev1.ConditionFalse, condition.Status)
+		assert.Equal(t, "Resumed", condition.Reason)
+	}
+}
+
+func TestClusterStatusScenario_Streaming(t *testing.T) {
+	s := newScenario(t, 2)
+	s.expectPhase(v1alpha1.ClusterPhaseInitial)
+	for i := 0; i < 2; i++ {
+		s.schedule()
+		s.start()
+		s.update()
+		s.advance(time.Minute)
+	}
+	s.expectPhase(v1alpha1.ClusterPhaseRunning)
+
+	// the nodes send data to the joining node, which holds back adding the last one
+	s.resize(4)
+	s.schedule()
+	s.start()
+	status := s.update()
+	assert.Equal(t, []string{s.podName(0), s.podName(1)}, status.Members.Streaming)
+
+	// the joining node reports what it received from both nodes
+	s.advance(30 * time.Second)
+	status = s.update()
+	joining := findNodeInfo(status.Nodes, s.podName(2))
+	if assert.NotNil(t, joining) && assert.NotNil(t, joining.Streams) {
+		assert.Len(t, joining.Streams.Sessions, 2)
+		assert.Equal(t, joining.Streams.BytesTotal/2, joining.Streams.BytesDone)
+		assert.NotNil(t, joining.Streams.EstimatedCompletion)
+	}
+
+	s.advance(30 * time.Second)
+	status = s.update()
+	assert.Empty(t, status.Members.Streaming)
+	assert.Len(t, status.Members.Ready, 3)
+}
+
+func findNodeInfo(nodeInfos []v1alpha1.NodeInfo, name string) *v1alpha1.NodeInfo {
+	for i := range nodeInfos {
+		if nodeInfos[i].Name == name {
+			return &nodeInfos[i]
+		}
+	}
+	return nil
+}
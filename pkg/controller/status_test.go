@@ -1,6 +1,7 @@
 package controller_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/operator-framework/operator-sdk/pkg/sdk"
 	"github.com/operator-framework/operator-sdk/pkg/util/k8sutil"
@@ -12,21 +13,31 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
+	"time"
 )
 
 // Mock Objects
 type MockClusterClient struct {
 	GetStatusCallback func(node *corev1.Pod) (map[string]*nodetool.Status, error)
-	GetHostIDCallback func(node *corev1.Pod) (string, error)
+	GetInfoCallback   func(node *corev1.Pod) (*nodetool.Info, error)
+	// GetNetstatsCallback defaults to a node that is not streaming
+	GetNetstatsCallback func(node *corev1.Pod) (*nodetool.Netstats, error)
 }
 
 // GetNodeStatus retrieves the specified nodes status
-func (c *MockClusterClient) GetStatus(node *corev1.Pod) (map[string]*nodetool.Status, error) {
+func (c *MockClusterClient) GetStatus(ctx context.Context, node *corev1.Pod) (map[string]*nodetool.Status, error) {
 	return c.GetStatusCallback(node)
 }
 
-func (c *MockClusterClient) GetHostID(node *corev1.Pod) (string, error) {
-	return c.GetHostIDCallback(node)
+func (c *MockClusterClient) GetInfo(ctx context.Context, node *corev1.Pod) (*nodetool.Info, error) {
+	return c.GetInfoCallback(node)
+}
+
+func (c *MockClusterClient) GetNetstats(ctx context.Context, node *corev1.Pod) (*nodetool.Netstats, error) {
+	if c.GetNetstatsCallback == nil {
+		return &nodetool.Netstats{Mode: nodetool.NodeModeNormal}, nil
+	}
+	return c.GetNetstatsCallback(node)
 }
 
 // Unit Tests
@@ -57,10 +68,12 @@ func TestUpdate_NoPods(t *testing.T) {
 	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
 
 	cc := getCassandraCluster(1, v1alpha1.ClusterPhaseInitial)
-	err := controller.Update(cc)
-	status := capturedObject.Status
+	err := controller.Update(context.Background(), cc)
+	status := cc.Status
 
 	assert.NoError(t, err)
+	// the status did not change, it is not written
+	assert.Nil(t, capturedObject)
 	assert.Equal(t, v1alpha1.ClusterPhaseInitial, status.Phase)
 	assert.Len(t, status.Members.Creating, 0)
 	assert.Len(t, status.Members.Joining, 0)
@@ -107,7 +120,7 @@ func TestGetClusterStatus_CreatingPodPending(t *testing.T) {
 	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
 
 	cc := getCassandraCluster(1, v1alpha1.ClusterPhaseCreating)
-	err := controller.Update(cc)
+	err := controller.Update(context.Background(), cc)
 	status := capturedObject.Status
 
 	assert.NoError(t, err)
@@ -161,7 +174,7 @@ func TestGetClusterStatus_CreatingPodRunningNotReady(t *testing.T) {
 	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
 
 	cc := getCassandraCluster(1, v1alpha1.ClusterPhaseCreating)
-	err := controller.Update(cc)
+	err := controller.Update(context.Background(), cc)
 	status := capturedObject.Status
 
 	assert.NoError(t, err)
@@ -205,8 +218,8 @@ func TestGetClusterStatus_CreatingPodRunningReadyJoining(t *testing.T) {
 				},
 			}, nil
 		},
-		GetHostIDCallback: func(node *corev1.Pod) (string, error) {
-			return "4d1a5c32-9642-405e-bd7e-27c8400bf779", nil
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
+			return &nodetool.Info{ID: "4d1a5c32-9642-405e-bd7e-27c8400bf779"}, nil
 		},
 	}
 
@@ -228,7 +241,7 @@ func TestGetClusterStatus_CreatingPodRunningReadyJoining(t *testing.T) {
 	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
 
 	cc := getCassandraCluster(1, v1alpha1.ClusterPhaseInitializing)
-	err := controller.Update(cc)
+	err := controller.Update(context.Background(), cc)
 	status := capturedObject.Status
 
 	assert.NoError(t, err)
@@ -250,8 +263,8 @@ func TestGetClusterStatus_CreatingOtherUnsupported(t *testing.T) {
 				},
 			}, nil
 		},
-		GetHostIDCallback: func(node *corev1.Pod) (string, error) {
-			return "4d1a5c32-9642-405e-bd7e-27c8400bf779", nil
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
+			return &nodetool.Info{ID: "4d1a5c32-9642-405e-bd7e-27c8400bf779"}, nil
 		},
 	}
 
@@ -276,7 +289,7 @@ func TestGetClusterStatus_CreatingOtherUnsupported(t *testing.T) {
 	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
 
 	cc := getCassandraCluster(1, v1alpha1.ClusterPhaseCreating)
-	err := controller.Update(cc)
+	err := controller.Update(context.Background(), cc)
 
 	assert.Error(t, err, "Unsupported PodPhase: Succeeded")
 }
@@ -317,8 +330,8 @@ func TestGetClusterStatus_AllRunningUp(t *testing.T) {
 				},
 			}, nil
 		},
-		GetHostIDCallback: func(node *corev1.Pod) (string, error) {
-			return "4d1a5c32-9642-405e-bd7e-27c8400bf779", nil
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
+			return &nodetool.Info{ID: "4d1a5c32-9642-405e-bd7e-27c8400bf779"}, nil
 		},
 	}
 
@@ -340,7 +353,10 @@ func TestGetClusterStatus_AllRunningUp(t *testing.T) {
 	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
 
 	cc := getCassandraCluster(1, v1alpha1.ClusterPhaseRunning)
-	err := controller.Update(cc)
+	cc.Status.Conditions = []v1alpha1.ClusterCondition{
+		{Type: v1alpha1.ClusterConditionSecretsValid, Status: corev1.ConditionTrue},
+	}
+	err := controller.Update(context.Background(), cc)
 	status := capturedObject.Status
 
 	assert.NoError(t, err)
@@ -351,6 +367,7 @@ func TestGetClusterStatus_AllRunningUp(t *testing.T) {
 	assert.Len(t, status.Members.Unready, 0)
 	assert.Len(t, status.Members.Ready, 1)
 	assert.Equal(t, mockPod1.GetName(), status.Members.Ready[0])
+	assert.Len(t, status.Conditions, 1)
 }
 
 func TestGetClusterStatus_ScalingRunningJoin(t *testing.T) {
@@ -420,15 +437,15 @@ func TestGetClusterStatus_ScalingRunningJoin(t *testing.T) {
 				},
 			}, nil
 		},
-		GetHostIDCallback: func(node *corev1.Pod) (string, error) {
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
 			if node.GetName() == "test-cluster-cassandra-0" {
-				return "4d1a5c32-9642-405e-bd7e-27c8400bf779", nil
+				return &nodetool.Info{ID: "4d1a5c32-9642-405e-bd7e-27c8400bf779"}, nil
 			}
 			if node.GetName() == "test-cluster-cassandra-1" {
-				return "f652fd91-3c7d-43bb-84a2-b6d55e578b49", nil
+				return &nodetool.Info{ID: "f652fd91-3c7d-43bb-84a2-b6d55e578b49"}, nil
 			}
 
-			return "", fmt.Errorf("SHOULD NEVER GET HERE")
+			return nil, fmt.Errorf("SHOULD NEVER GET HERE")
 		},
 	}
 	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
@@ -442,7 +459,7 @@ func TestGetClusterStatus_ScalingRunningJoin(t *testing.T) {
 	// unready: 0
 	// NewPhase: ClusterPhaseScaling
 	cc := getCassandraCluster(2, v1alpha1.ClusterPhaseRunning)
-	err := controller.Update(cc)
+	err := controller.Update(context.Background(), cc)
 	status := capturedObject.Status
 
 	assert.NoError(t, err)
@@ -463,7 +480,7 @@ func TestGetClusterStatus_ScalingRunningJoin(t *testing.T) {
 	// unready: 0
 	// NewPhase: ClusterPhaseScaling
 	cc = getCassandraCluster(2, v1alpha1.ClusterPhaseScaling)
-	err = controller.Update(cc)
+	err = controller.Update(context.Background(), cc)
 	status = capturedObject.Status
 
 	assert.NoError(t, err)
@@ -544,15 +561,15 @@ func TestGetClusterStatus_ScalingRunningLeave(t *testing.T) {
 				},
 			}, nil
 		},
-		GetHostIDCallback: func(node *corev1.Pod) (string, error) {
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
 			if node.GetName() == "test-cluster-cassandra-0" {
-				return "4d1a5c32-9642-405e-bd7e-27c8400bf779", nil
+				return &nodetool.Info{ID: "4d1a5c32-9642-405e-bd7e-27c8400bf779"}, nil
 			}
 			if node.GetName() == "test-cluster-cassandra-1" {
-				return "f652fd91-3c7d-43bb-84a2-b6d55e578b49", nil
+				return &nodetool.Info{ID: "f652fd91-3c7d-43bb-84a2-b6d55e578b49"}, nil
 			}
 
-			return "", fmt.Errorf("SHOULD NEVER GET HERE")
+			return nil, fmt.Errorf("SHOULD NEVER GET HERE")
 		},
 	}
 	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
@@ -566,7 +583,7 @@ func TestGetClusterStatus_ScalingRunningLeave(t *testing.T) {
 	// unready: 0
 	// NewPhase: ClusterPhaseScaling
 	cc := getCassandraCluster(2, v1alpha1.ClusterPhaseScaling)
-	err := controller.Update(cc)
+	err := controller.Update(context.Background(), cc)
 	status := capturedObject.Status
 
 	assert.NoError(t, err)
@@ -588,7 +605,7 @@ func TestGetClusterStatus_ScalingRunningLeave(t *testing.T) {
 	// unready: 0
 	// NewPhase: ClusterPhaseScaling
 	cc = getCassandraCluster(2, v1alpha1.ClusterPhaseRunning)
-	err = controller.Update(cc)
+	err = controller.Update(context.Background(), cc)
 	status = capturedObject.Status
 
 	assert.NoError(t, err)
@@ -657,8 +674,8 @@ func TestGetClusterStatus_ScalingRunningCreating(t *testing.T) {
 				},
 			}, nil
 		},
-		GetHostIDCallback: func(node *corev1.Pod) (string, error) {
-			return "4d1a5c32-9642-405e-bd7e-27c8400bf779", nil
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
+			return &nodetool.Info{ID: "4d1a5c32-9642-405e-bd7e-27c8400bf779"}, nil
 		},
 	}
 	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
@@ -671,7 +688,7 @@ func TestGetClusterStatus_ScalingRunningCreating(t *testing.T) {
 	// leaving: 0
 	// unready: 0
 	cc := getCassandraCluster(2, v1alpha1.ClusterPhaseScaling)
-	err := controller.Update(cc)
+	err := controller.Update(context.Background(), cc)
 	status := capturedObject.Status
 
 	assert.NoError(t, err)
@@ -692,7 +709,7 @@ func TestGetClusterStatus_ScalingRunningCreating(t *testing.T) {
 	// leaving: 0
 	// unready: 0
 	cc = getCassandraCluster(2, v1alpha1.ClusterPhaseRunning)
-	err = controller.Update(cc)
+	err = controller.Update(context.Background(), cc)
 	status = capturedObject.Status
 
 	assert.NoError(t, err)
@@ -737,15 +754,15 @@ func TestGetClusterStatus_CreatingFailure(t *testing.T) {
 		GetStatusCallback: func(node *corev1.Pod) (map[string]*nodetool.Status, error) {
 			return nil, fmt.Errorf("SHOULD NOT GET HERE")
 		},
-		GetHostIDCallback: func(node *corev1.Pod) (string, error) {
-			return "nil", fmt.Errorf("SHOULD NOT GET HERE")
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
+			return nil, fmt.Errorf("SHOULD NOT GET HERE")
 		},
 	}
 
 	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
 
 	cc := getCassandraCluster(2, v1alpha1.ClusterPhaseCreating)
-	err := controller.Update(cc)
+	err := controller.Update(context.Background(), cc)
 	status := capturedObject.Status
 
 	assert.NoError(t, err)
@@ -757,7 +774,7 @@ func TestGetClusterStatus_CreatingFailure(t *testing.T) {
 	assert.Len(t, status.Members.Ready, 0)
 
 	cc = getCassandraCluster(2, v1alpha1.ClusterPhaseInitializing)
-	err = controller.Update(cc)
+	err = controller.Update(context.Background(), cc)
 	status = capturedObject.Status
 
 	assert.NoError(t, err)
@@ -800,15 +817,15 @@ func TestGetClusterStatus_CreatingUnknown(t *testing.T) {
 		GetStatusCallback: func(node *corev1.Pod) (map[string]*nodetool.Status, error) {
 			return nil, fmt.Errorf("SHOULD NOT GET HERE")
 		},
-		GetHostIDCallback: func(node *corev1.Pod) (string, error) {
-			return "nil", fmt.Errorf("SHOULD NOT GET HERE")
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
+			return nil, fmt.Errorf("SHOULD NOT GET HERE")
 		},
 	}
 
 	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
 
 	cc := getCassandraCluster(2, v1alpha1.ClusterPhaseCreating)
-	err := controller.Update(cc)
+	err := controller.Update(context.Background(), cc)
 	status := capturedObject.Status
 
 	assert.NoError(t, err)
@@ -820,7 +837,7 @@ func TestGetClusterStatus_CreatingUnknown(t *testing.T) {
 	assert.Len(t, status.Members.Ready, 0)
 
 	cc = getCassandraCluster(2, v1alpha1.ClusterPhaseInitializing)
-	err = controller.Update(cc)
+	err = controller.Update(context.Background(), cc)
 	status = capturedObject.Status
 
 	assert.NoError(t, err)
@@ -846,3 +863,331 @@ func getCassandraCluster(size int, phase v1alpha1.ClusterPhase) *v1alpha1.Cassan
 		},
 	}
 }
+
+func TestGetClusterStatus_ReportsNodeInfo(t *testing.T) {
+	mockPod1 := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-cassandra-0",
+			Namespace: "testnamespace",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{
+					Type:   corev1.PodReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+	mockClusterClient := &MockClusterClient{
+		GetStatusCallback: func(node *corev1.Pod) (map[string]*nodetool.Status, error) {
+			return map[string]*nodetool.Status{
+				"4d1a5c32-9642-405e-bd7e-27c8400bf779": {
+					HostID: "4d1a5c32-9642-405e-bd7e-27c8400bf779",
+					Owns:   100.0,
+					State:  nodetool.NodeStateNormal,
+					Status: nodetool.NodeStatusUp,
+				},
+			}, nil
+		},
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
+			return &nodetool.Info{
+				ID:            "4d1a5c32-9642-405e-bd7e-27c8400bf779",
+				UptimeSeconds: 1871647,
+				HeapUsedMB:    1584.43,
+				HeapTotalMB:   6104.00,
+			}, nil
+		},
+	}
+
+	var capturedObject *v1alpha1.CassandraCluster
+	mockKubeClient := &k8s.MockClient{
+		ListCallback: func(namespace string, into sdk.Object, opts ...sdk.ListOption) error {
+			actual := &corev1.PodList{
+				Items: []corev1.Pod{
+					mockPod1,
+				},
+			}
+			return k8sutil.RuntimeObjectIntoRuntimeObject(actual, into)
+		},
+		UpdateCallback: func(object sdk.Object) error {
+			capturedObject = object.(*v1alpha1.CassandraCluster)
+			return nil
+		},
+	}
+	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
+
+	cc := getCassandraCluster(1, v1alpha1.ClusterPhaseRunning)
+	err := controller.Update(context.Background(), cc)
+	status := capturedObject.Status
+
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1.ClusterPhaseRunning, status.Phase)
+	assert.Equal(t, []v1alpha1.NodeInfo{
+		{
+			Name:            "test-cluster-cassandra-0",
+			HostID:          "4d1a5c32-9642-405e-bd7e-27c8400bf779",
+			UptimeSeconds:   1871647,
+			HeapUsedMB:      1584,
+			HeapTotalMB:     6104,
+			HeapUsedPercent: 25,
+		},
+	}, status.Nodes)
+}
+
+func TestUpdate_SkipsNodeMetrics(t *testing.T) {
+	mockPod1 := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-cassandra-0",
+			Namespace: "testnamespace",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{
+					Type:   corev1.PodReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+	uptime := int64(60)
+	mockClusterClient := &MockClusterClient{
+		GetStatusCallback: func(node *corev1.Pod) (map[string]*nodetool.Status, error) {
+			return map[string]*nodetool.Status{
+				"4d1a5c32-9642-405e-bd7e-27c8400bf779": {
+					HostID: "4d1a5c32-9642-405e-bd7e-27c8400bf779",
+					State:  nodetool.NodeStateNormal,
+					Status: nodetool.NodeStatusUp,
+				},
+			}, nil
+		},
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
+			uptime += 60
+			return &nodetool.Info{
+				ID:            "4d1a5c32-9642-405e-bd7e-27c8400bf779",
+				UptimeSeconds: uptime,
+				HeapUsedMB:    float64(uptime),
+				HeapTotalMB:   6104.00,
+			}, nil
+		},
+	}
+
+	updates := 0
+	mockKubeClient := &k8s.MockClient{
+		ListCallback: func(namespace string, into sdk.Object, opts ...sdk.ListOption) error {
+			actual := &corev1.PodList{
+				Items: []corev1.Pod{
+					mockPod1,
+				},
+			}
+			return k8sutil.RuntimeObjectIntoRuntimeObject(actual, into)
+		},
+		UpdateCallback: func(object sdk.Object) error {
+			updates++
+			return nil
+		},
+	}
+	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
+
+	cc := getCassandraCluster(1, v1alpha1.ClusterPhaseRunning)
+	assert.NoError(t, controller.Update(context.Background(), cc))
+	assert.Equal(t, 1, updates)
+
+	// only the uptime and heap usage changed
+	assert.NoError(t, controller.Update(context.Background(), cc))
+	assert.Equal(t, 1, updates)
+	assert.Equal(t, int64(120), cc.Status.Nodes[0].UptimeSeconds)
+
+	// the metrics are refreshed once they are stale
+	cc.Status.NodeMetricsObservedAt = &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}
+	assert.NoError(t, controller.Update(context.Background(), cc))
+	assert.Equal(t, 2, updates)
+	assert.Equal(t, int64(240), cc.Status.Nodes[0].UptimeSeconds)
+}
+
+func TestUpdate_PausedOnly(t *testing.T) {
+	mockPod1 := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-cassandra-0",
+			Namespace: "testnamespace",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{
+					Type:   corev1.PodReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+	mockClusterClient := &MockClusterClient{
+		GetStatusCallback: func(node *corev1.Pod) (map[string]*nodetool.Status, error) {
+			return map[string]*nodetool.Status{
+				"4d1a5c32-9642-405e-bd7e-27c8400bf779": {
+					HostID: "4d1a5c32-9642-405e-bd7e-27c8400bf779",
+					State:  nodetool.NodeStateNormal,
+					Status: nodetool.NodeStatusUp,
+				},
+			}, nil
+		},
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
+			return &nodetool.Info{ID: "4d1a5c32-9642-405e-bd7e-27c8400bf779"}, nil
+		},
+	}
+
+	updates := 0
+	mockKubeClient := &k8s.MockClient{
+		ListCallback: func(namespace string, into sdk.Object, opts ...sdk.ListOption) error {
+			actual := &corev1.PodList{
+				Items: []corev1.Pod{
+					mockPod1,
+				},
+			}
+			return k8sutil.RuntimeObjectIntoRuntimeObject(actual, into)
+		},
+		UpdateCallback: func(object sdk.Object) error {
+			updates++
+			return nil
+		},
+	}
+	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
+
+	cc := getCassandraCluster(1, v1alpha1.ClusterPhaseRunning)
+	assert.NoError(t, controller.Update(context.Background(), cc))
+	assert.Equal(t, 1, updates)
+
+	// only spec.paused changes between the updates
+	cc.Spec.Paused = true
+	assert.NoError(t, controller.Update(context.Background(), cc))
+	assert.Equal(t, 2, updates)
+	assert.Equal(t, corev1.ConditionTrue, cc.Status.GetCondition(v1alpha1.ClusterConditionPaused).Status)
+
+	cc.Spec.Paused = false
+	assert.NoError(t, controller.Update(context.Background(), cc))
+	assert.Equal(t, 3, updates)
+	assert.Equal(t, corev1.ConditionFalse, cc.Status.GetCondition(v1alpha1.ClusterConditionPaused).Status)
+
+	assert.NoError(t, controller.Update(context.Background(), cc))
+	assert.Equal(t, 3, updates)
+}
+
+func TestGetClusterStatus_ReportsStreamProgress(t *testing.T) {
+	mockPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-cassandra-1",
+			Namespace: "testnamespace",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{
+					Type:   corev1.PodReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+	mockClusterClient := &MockClusterClient{
+		GetStatusCallback: func(node *corev1.Pod) (map[string]*nodetool.Status, error) {
+			return map[string]*nodetool.Status{
+				"4d1a5c32-9642-405e-bd7e-27c8400bf779": {
+					HostID: "4d1a5c32-9642-405e-bd7e-27c8400bf779",
+					State:  nodetool.NodeStateJoining,
+					Status: nodetool.NodeStatusUp,
+				},
+			}, nil
+		},
+		GetInfoCallback: func(node *corev1.Pod) (*nodetool.Info, error) {
+			return &nodetool.Info{ID: "4d1a5c32-9642-405e-bd7e-27c8400bf779"}, nil
+		},
+		GetNetstatsCallback: func(node *corev1.Pod) (*nodetool.Netstats, error) {
+			return &nodetool.Netstats{
+				Mode:      nodetool.NodeModeJoining,
+				Streaming: true,
+				Sessions: []nodetool.StreamSession{
+					{
+						Operation:  "Bootstrap",
+						Peer:       "10.0.0.1",
+						Direction:  nodetool.StreamDirectionReceiving,
+						TotalFiles: 10,
+						TotalBytes: 3000,
+						DoneFiles:  5,
+						DoneBytes:  1500,
+					},
+					{
+						Operation:  "Bootstrap",
+						Peer:       "10.0.0.2",
+						Direction:  nodetool.StreamDirectionReceiving,
+						TotalFiles: 10,
+						TotalBytes: 1000,
+						DoneFiles:  10,
+						DoneBytes:  1000,
+					},
+				},
+			}, nil
+		},
+	}
+
+	var capturedObject *v1alpha1.CassandraCluster
+	mockKubeClient := &k8s.MockClient{
+		ListCallback: func(namespace string, into sdk.Object, opts ...sdk.ListOption) error {
+			actual := &corev1.PodList{
+				Items: []corev1.Pod{
+					mockPod,
+				},
+			}
+			return k8sutil.RuntimeObjectIntoRuntimeObject(actual, into)
+		},
+		UpdateCallback: func(object sdk.Object) error {
+			capturedObject = object.(*v1alpha1.CassandraCluster)
+			return nil
+		},
+	}
+	controller := controller.NewStatusManager(mockClusterClient, mockKubeClient)
+
+	// the node streamed 2000 bytes in the 10 seconds since it was last observed, the other 1500 take 7.5 seconds
+	cc := getCassandraCluster(2, v1alpha1.ClusterPhaseScaling)
+	cc.Status.Nodes = []v1alpha1.NodeInfo{
+		{
+			Name: "test-cluster-cassandra-1",
+			Streams: &v1alpha1.StreamProgress{
+				BytesDone:  500,
+				BytesTotal: 4000,
+				ObservedAt: metav1.NewTime(time.Now().Add(-10 * time.Second)),
+			},
+		},
+	}
+	err := controller.Update(context.Background(), cc)
+	assert.NoError(t, err)
+
+	streams := capturedObject.Status.Nodes[0].Streams
+	assert.Equal(t, []v1alpha1.StreamSessionStatus{
+		{
+			Operation:  "Bootstrap",
+			Peer:       "10.0.0.1",
+			Direction:  "Receiving",
+			FilesDone:  5,
+			FilesTotal: 10,
+			BytesDone:  1500,
+			BytesTotal: 3000,
+		},
+		{
+			Operation:  "Bootstrap",
+			Peer:       "10.0.0.2",
+			Direction:  "Receiving",
+			FilesDone:  10,
+			FilesTotal: 10,
+			BytesDone:  1000,
+			BytesTotal: 1000,
+		},
+	}, streams.Sessions)
+	assert.Equal(t, int64(2500), streams.BytesDone)
+	assert.Equal(t, int64(4000), streams.BytesTotal)
+	if assert.NotNil(t, streams.EstimatedCompletion) {
+		remaining := streams.EstimatedCompletion.Sub(streams.ObservedAt.Time)
+		assert.InDelta(t, 7.5, remaining.Seconds(), 0.1)
+	}
+}
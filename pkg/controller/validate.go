@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/secrets"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	referenceKindSecret      = "Secret"
+	referenceKindVaultSecret = "VaultSecret"
+	referenceKindConfigMap   = "ConfigMap"
+
+	reasonReferencesFound    = "ReferencesFound"
+	reasonNotFoundSuffix     = "NotFound"
+	reasonKeyMissingSuffix   = "KeyMissing"
+	reasonVaultNotConfigured = "VaultNotConfigured"
+)
+
+// validateSecrets checks the secrets the nodes mount exist and hold the keystore and truststore, a keystore
+// read from vault is checked in vault
+func (c *ClusterController) validateSecrets() (v1alpha1.ClusterCondition, error) {
+	condition, err := c.validateReferences(v1alpha1.ClusterConditionSecretsValid, referenceKindSecret,
+		resource.SecretReferences(c.cluster), c.getSecretKeys)
+	if err != nil || condition.Status != corev1.ConditionTrue || !resource.VaultKeystore(c.cluster) {
+		return condition, err
+	}
+
+	if c.secretProvider == nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = reasonVaultNotConfigured
+		condition.Message = "the keystore is read from vault but the operator has no vault address"
+		return condition, nil
+	}
+
+	return c.validateReferences(v1alpha1.ClusterConditionSecretsValid, referenceKindVaultSecret,
+		resource.VaultReferences(c.cluster), c.getVaultKeys)
+}
+
+// validateConfigmaps checks the cassandra and jvm agent configmaps exist and hold the config files
+func (c *ClusterController) validateConfigmaps() (v1alpha1.ClusterCondition, error) {
+	return c.validateReferences(v1alpha1.ClusterConditionConfigMapsValid, referenceKindConfigMap,
+		resource.ConfigMapReferences(c.cluster), c.getConfigMapKeys)
+}
+
+// recordConditions sets the conditions on the cluster and updates it when any of them changed, it returns true
+// if all the conditions are true
+func (c *ClusterController) recordConditions(conditions ...v1alpha1.ClusterCondition) (bool, error) {
+	changed := false
+	valid := true
+	for _, condition := range conditions {
+		if c.cluster.Status.SetCondition(condition) {
+			changed = true
+		}
+		if condition.Status != corev1.ConditionTrue {
+			logrus.Warnf("Cluster %s is not valid: %s", c.cluster.GetName(), condition.Message)
+			valid = false
+		}
+	}
+
+	if changed {
+		err := c.driver.Update(c.cluster)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return valid, nil
+}
+
+// validateReferences returns a condition that is false with the first missing object or key of the references
+func (c *ClusterController) validateReferences(conditionType v1alpha1.ClusterConditionType, kind string,
+	references []resource.Reference, getKeys func(name string) (map[string]bool, error)) (v1alpha1.ClusterCondition, error) {
+	condition := v1alpha1.ClusterCondition{
+		Type:               conditionType,
+		Status:             corev1.ConditionTrue,
+		Reason:             reasonReferencesFound,
+		LastTransitionTime: metav1.Now(),
+	}
+
+	for _, reference := range references {
+		keys, err := getKeys(reference.Name)
+		if err != nil {
+			return condition, err
+		}
+
+		if keys == nil {
+			condition.Status = corev1.ConditionFalse
+			condition.Reason = kind + reasonNotFoundSuffix
+			condition.Message = fmt.Sprintf("%s %s does not exist", kind, c.referenceName(kind, reference.Name))
+			return condition, nil
+		}
+
+		for _, key := range reference.Keys {
+			if !keys[key] {
+				condition.Status = corev1.ConditionFalse
+				condition.Reason = kind + reasonKeyMissingSuffix
+				condition.Message = fmt.Sprintf("%s %s is missing the key %q", kind, c.referenceName(kind, reference.Name), key)
+				return condition, nil
+			}
+		}
+	}
+
+	return condition, nil
+}
+
+// referenceName qualifies kubernetes objects with the namespace of the cluster, vault paths are not namespaced
+func (c *ClusterController) referenceName(kind, name string) string {
+	if kind == referenceKindVaultSecret {
+		return name
+	}
+	return c.cluster.GetNamespace() + "/" + name
+}
+
+// getSecretKeys returns the keys of the secret or nil if it does not exist
+func (c *ClusterController) getSecretKeys(name string) (map[string]bool, error) {
+	return keysOf(secrets.NewKubernetesProvider(c.driver), c.cluster.GetNamespace(), name)
+}
+
+// getVaultKeys returns the keys of the vault secret or nil if it does not exist
+func (c *ClusterController) getVaultKeys(path string) (map[string]bool, error) {
+	return keysOf(c.secretProvider, c.cluster.GetNamespace(), path)
+}
+
+func keysOf(provider secrets.SecretProvider, namespace, name string) (map[string]bool, error) {
+	files, err := provider.Keystore(namespace, name)
+	if err != nil || files == nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for key := range files {
+		keys[key] = true
+	}
+	return keys, nil
+}
+
+// getConfigMapKeys returns the keys of the configmap or nil if it does not exist
+func (c *ClusterController) getConfigMapKeys(name string) (map[string]bool, error) {
+	configMap := &corev1.ConfigMap{
+		TypeMeta: resource.GetConfigMapTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.cluster.GetNamespace(),
+		},
+	}
+	err := c.driver.Get(configMap)
+	if err != nil {
+		return nil, fmt.Errorf("could not get configmap %s: %v", name, err)
+	}
+
+	if configMap.ResourceVersion == "" {
+		return nil, nil
+	}
+
+	keys := map[string]bool{}
+	for key := range configMap.Data {
+		keys[key] = true
+	}
+	for key := range configMap.BinaryData {
+		keys[key] = true
+	}
+	return keys, nil
+}
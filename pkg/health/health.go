@@ -0,0 +1,149 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Status reports the health and readiness of the operator. While leading, the watches of the operator are
+// expected to deliver the renewals of the leader lock configmap, the time of the last one shows the watches are
+// connected. A standby replica has no watches running and is healthy and ready while it waits for the lock.
+type Status struct {
+	watchTimeout time.Duration
+
+	mu            sync.Mutex
+	leading       bool
+	leadingSince  time.Time
+	lastHeartbeat time.Time
+}
+
+// NewStatus creates a new Status, the watches are unhealthy when no heartbeat was observed for the watch timeout
+func NewStatus(watchTimeout time.Duration) *Status {
+	return &Status{
+		watchTimeout: watchTimeout,
+	}
+}
+
+// SetLeading records that the operator acquired or lost the leader lock
+func (s *Status) SetLeading(leading bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if leading && !s.leading {
+		s.leadingSince = time.Now()
+	}
+	s.leading = leading
+}
+
+// Heartbeat records an event of the leader lock delivered through the watches
+func (s *Status) Heartbeat() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastHeartbeat = time.Now()
+}
+
+// Healthy returns an error when the operator is leading and its watches stopped delivering events
+func (s *Status) Healthy() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.leading {
+		return nil
+	}
+
+	// the watches are given the timeout to connect after the leadership was acquired
+	last := s.lastHeartbeat
+	if last.Before(s.leadingSince) {
+		last = s.leadingSince
+	}
+	if since := time.Since(last); since > s.watchTimeout {
+		return fmt.Errorf("watches did not deliver the leader lock for %s", since.Round(time.Second))
+	}
+
+	return nil
+}
+
+// Ready returns an error when the operator is leading and its watches have not delivered the leader lock recently
+func (s *Status) Ready() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.leading {
+		return nil
+	}
+
+	if s.lastHeartbeat.Before(s.leadingSince) {
+		return fmt.Errorf("watches have not delivered events since leading")
+	}
+	if since := time.Since(s.lastHeartbeat); since > s.watchTimeout {
+		return fmt.Errorf("watches did not deliver the leader lock for %s", since.Round(time.Second))
+	}
+
+	return nil
+}
+
+// role is reported in the body of the endpoints
+func (s *Status) role() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.leading {
+		return "leader"
+	}
+	return "standby"
+}
+
+// HealthzHandler serves /healthz, it fails when the operator is leading without working watches
+func (s *Status) HealthzHandler() http.Handler {
+	return s.handler(s.Healthy)
+}
+
+// ReadyzHandler serves /readyz, it fails until the watches of the leader deliver events
+func (s *Status) ReadyzHandler() http.Handler {
+	return s.handler(s.Ready)
+}
+
+func (s *Status) handler(check func() error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := check()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%s: %v", s.role(), err), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, "ok: %s\n", s.role())
+	})
+}
+
+// WatchHandler wraps the handler of the operator, events of the leader lock configmap in the namespace with the
+// name are recorded as heartbeats and not passed on
+func (s *Status) WatchHandler(next sdk.Handler, namespace, name string) sdk.Handler {
+	return &watchHandler{
+		next:      next,
+		status:    s,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+type watchHandler struct {
+	next      sdk.Handler
+	status    *Status
+	namespace string
+	name      string
+}
+
+// Handle records heartbeats and passes the other events on
+func (h *watchHandler) Handle(ctx context.Context, event sdk.Event) error {
+	if lock, ok := event.Object.(*corev1.ConfigMap); ok && lock.GetNamespace() == h.namespace && lock.GetName() == h.name {
+		h.status.Heartbeat()
+		return nil
+	}
+	return h.next.Handle(ctx, event)
+}
@@ -0,0 +1,83 @@
+package health_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/health"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type countingHandler struct {
+	events int
+}
+
+func (h *countingHandler) Handle(ctx context.Context, event sdk.Event) error {
+	h.events++
+	return nil
+}
+
+func lockEvent(namespace, name string) sdk.Event {
+	return sdk.Event{
+		Object: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		},
+	}
+}
+
+func get(handler http.Handler) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	return recorder
+}
+
+func TestStatus_Standby(t *testing.T) {
+	status := health.NewStatus(time.Minute)
+
+	healthz := get(status.HealthzHandler())
+	assert.Equal(t, http.StatusOK, healthz.Code)
+	assert.Equal(t, "ok: standby\n", healthz.Body.String())
+	assert.Equal(t, http.StatusOK, get(status.ReadyzHandler()).Code)
+}
+
+func TestStatus_LeaderReadyAfterHeartbeat(t *testing.T) {
+	status := health.NewStatus(time.Minute)
+	next := &countingHandler{}
+	handler := status.WatchHandler(next, "operator", "cassandra-operator-lock")
+
+	status.SetLeading(true)
+	assert.Equal(t, http.StatusOK, get(status.HealthzHandler()).Code)
+	readyz := get(status.ReadyzHandler())
+	assert.Equal(t, http.StatusServiceUnavailable, readyz.Code)
+	assert.Equal(t, "leader: watches have not delivered events since leading\n", readyz.Body.String())
+
+	// other configmaps are passed on, the lock is a heartbeat
+	assert.NoError(t, handler.Handle(context.Background(), lockEvent("operator", "other")))
+	assert.Equal(t, http.StatusServiceUnavailable, get(status.ReadyzHandler()).Code)
+	assert.NoError(t, handler.Handle(context.Background(), lockEvent("operator", "cassandra-operator-lock")))
+	assert.Equal(t, 1, next.events)
+
+	readyz = get(status.ReadyzHandler())
+	assert.Equal(t, http.StatusOK, readyz.Code)
+	assert.Equal(t, "ok: leader\n", readyz.Body.String())
+}
+
+func TestStatus_LeaderUnhealthyWithoutHeartbeats(t *testing.T) {
+	status := health.NewStatus(20 * time.Millisecond)
+	status.SetLeading(true)
+	status.Heartbeat()
+	assert.Equal(t, http.StatusOK, get(status.HealthzHandler()).Code)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, http.StatusServiceUnavailable, get(status.HealthzHandler()).Code)
+	assert.Equal(t, http.StatusServiceUnavailable, get(status.ReadyzHandler()).Code)
+
+	status.SetLeading(false)
+	assert.Equal(t, http.StatusOK, get(status.HealthzHandler()).Code)
+}
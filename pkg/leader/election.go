@@ -0,0 +1,176 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	// DefaultLeaseDuration is how long a standby waits after the last renewal it observed before taking over
+	DefaultLeaseDuration = 15 * time.Second
+	// DefaultRenewDeadline is how long the leader retries renewing before it gives up leadership
+	DefaultRenewDeadline = 10 * time.Second
+	// DefaultRetryPeriod is how often the lock is acquired or renewed
+	DefaultRetryPeriod = 2 * time.Second
+)
+
+// ErrLeadershipLost is returned by Run when the lock could not be renewed within the renew deadline
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// Elector elects a single leader among the replicas of the operator with the configmap lock of client-go. The
+// lease is timed with the local clock from the moment a change of the record is observed so clock skew between
+// the replicas does not matter.
+type Elector struct {
+	lock resourcelock.Interface
+
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+
+	mu      sync.Mutex
+	leading bool
+}
+
+// ElectorOption is a function that sets the configuration on the Elector
+type ElectorOption func(*Elector)
+
+// WithLeaseDuration sets how long a standby waits for a renewal before it takes over
+func WithLeaseDuration(d time.Duration) ElectorOption {
+	return func(e *Elector) {
+		e.leaseDuration = d
+	}
+}
+
+// WithRenewDeadline sets how long the leader retries renewing before it gives up leadership
+func WithRenewDeadline(d time.Duration) ElectorOption {
+	return func(e *Elector) {
+		e.renewDeadline = d
+	}
+}
+
+// WithRetryPeriod sets how often the lock is acquired or renewed
+func WithRetryPeriod(d time.Duration) ElectorOption {
+	return func(e *Elector) {
+		e.retryPeriod = d
+	}
+}
+
+// NewElector creates a new Elector that competes for the configmap lock with the name in the namespace as the identity
+func NewElector(client corev1client.ConfigMapsGetter, namespace, name, identity string, opts ...ElectorOption) *Elector {
+	e := &Elector{
+		lock: &resourcelock.ConfigMapLock{
+			ConfigMapMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Client: client,
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity:      identity,
+				EventRecorder: logRecorder{},
+			},
+		},
+		leaseDuration: DefaultLeaseDuration,
+		renewDeadline: DefaultRenewDeadline,
+		retryPeriod:   DefaultRetryPeriod,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Run blocks until the lock is acquired, runs onStartedLeading and keeps renewing the lock. The context passed
+// to onStartedLeading is cancelled when the leadership is lost. Run returns ErrLeadershipLost once the lock could
+// not be renewed within the renew deadline, or the error of the context when it is done first.
+func (e *Elector) Run(ctx context.Context, onStartedLeading func(context.Context)) error {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          e.lock,
+		LeaseDuration: e.leaseDuration,
+		RenewDeadline: e.renewDeadline,
+		RetryPeriod:   e.retryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(<-chan struct{}) {
+				logrus.Infof("Acquired leader lock %s as %s", e.lock.Describe(), e.lock.Identity())
+				e.setLeading(true)
+				onStartedLeading(leaderCtx)
+			},
+			OnStoppedLeading: func() {
+				e.setLeading(false)
+				cancel()
+				close(stopped)
+			},
+			OnNewLeader: func(identity string) {
+				if identity != e.lock.Identity() {
+					logrus.Infof("Leader lock %s is held by %s, waiting", e.lock.Describe(), identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Attempting to acquire leader lock %s as %s", e.lock.Describe(), e.lock.Identity())
+	// the elector of client-go can not be stopped, it is left behind when the context is done as the process
+	// exits and the lock expires
+	go elector.Run()
+
+	select {
+	case <-stopped:
+		logrus.Errorf("Lost leader lock %s, it was not renewed for %s", e.lock.Describe(), e.renewDeadline)
+		return ErrLeadershipLost
+	case <-ctx.Done():
+		e.setLeading(false)
+		return ctx.Err()
+	}
+}
+
+// IsLeader returns true while the elector holds the lock
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.leading
+}
+
+func (e *Elector) setLeading(leading bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.leading = leading
+}
+
+// logRecorder logs the events of the leader election instead of recording them on the lock configmap
+type logRecorder struct{}
+
+func (logRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	logrus.Infof("%s: %s", reason, message)
+}
+
+func (r logRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r logRecorder) PastEventf(object runtime.Object, timestamp metav1.Time, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+func (r logRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
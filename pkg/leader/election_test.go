@@ -0,0 +1,152 @@
+package leader_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/leader"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakecorev1 "k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// lockStore keeps the lock configmap in an object tracker and fails the updates once updateErr is set
+type lockStore struct {
+	tracker k8stesting.ObjectTracker
+
+	mu        sync.Mutex
+	updateErr error
+}
+
+func newLockStore(objects ...runtime.Object) *lockStore {
+	s := &lockStore{tracker: k8stesting.NewObjectTracker(scheme.Scheme, scheme.Codecs.UniversalDecoder())}
+	for _, object := range objects {
+		s.tracker.Add(object)
+	}
+	return s
+}
+
+func (s *lockStore) client() *fakecorev1.FakeCoreV1 {
+	fake := &k8stesting.Fake{}
+	fake.AddReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.updateErr != nil, nil, s.updateErr
+	})
+	fake.AddReactor("*", "*", k8stesting.ObjectReaction(s.tracker))
+	return &fakecorev1.FakeCoreV1{Fake: fake}
+}
+
+func (s *lockStore) record(t *testing.T) resourcelock.LeaderElectionRecord {
+	record := resourcelock.LeaderElectionRecord{}
+	object, err := s.tracker.Get(corev1.SchemeGroupVersion.WithResource("configmaps"), "operator", "cassandra-operator-lock")
+	if assert.NoError(t, err) {
+		lock := object.(*corev1.ConfigMap)
+		assert.NoError(t, json.Unmarshal([]byte(lock.Annotations[resourcelock.LeaderElectionRecordAnnotationKey]), &record))
+	}
+	return record
+}
+
+func (s *lockStore) setUpdateErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updateErr = err
+}
+
+func newTestElector(store *lockStore, identity string) *leader.Elector {
+	return leader.NewElector(store.client(), "operator", "cassandra-operator-lock", identity,
+		leader.WithLeaseDuration(200*time.Millisecond),
+		leader.WithRenewDeadline(100*time.Millisecond),
+		leader.WithRetryPeriod(10*time.Millisecond),
+	)
+}
+
+func TestElector_AcquiresMissingLock(t *testing.T) {
+	store := newLockStore()
+	elector := newTestElector(store, "operator-a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- elector.Run(ctx, func(context.Context) { close(started) })
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("lock was not acquired")
+	}
+	assert.True(t, elector.IsLeader())
+	assert.Equal(t, "operator-a", store.record(t).HolderIdentity)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	assert.False(t, elector.IsLeader())
+}
+
+func TestElector_WaitsForLeaseOfOtherHolder(t *testing.T) {
+	raw, _ := json.Marshal(resourcelock.LeaderElectionRecord{HolderIdentity: "operator-b", LeaseDurationSeconds: 15, RenewTime: metav1.Now()})
+	store := newLockStore(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cassandra-operator-lock",
+			Namespace:   "operator",
+			Annotations: map[string]string{resourcelock.LeaderElectionRecordAnnotationKey: string(raw)},
+		},
+	})
+	elector := newTestElector(store, "operator-a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	started := make(chan time.Time, 1)
+	start := time.Now()
+	go elector.Run(ctx, func(context.Context) { started <- time.Now() })
+
+	select {
+	case acquired := <-started:
+		// operator-b never renews so its lease runs out after the lease duration
+		assert.True(t, acquired.Sub(start) >= 200*time.Millisecond)
+	case <-time.After(2 * time.Second):
+		t.Fatal("expired lock was not taken over")
+	}
+
+	record := store.record(t)
+	assert.Equal(t, "operator-a", record.HolderIdentity)
+	assert.Equal(t, 1, record.LeaderTransitions)
+}
+
+func TestElector_StopsLeadingWhenRenewFails(t *testing.T) {
+	store := newLockStore()
+	elector := newTestElector(store, "operator-a")
+
+	var leaderCtx context.Context
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- elector.Run(context.Background(), func(ctx context.Context) {
+			leaderCtx = ctx
+			close(started)
+		})
+	}()
+
+	<-started
+	store.setUpdateErr(errors.New("api server unavailable"))
+
+	select {
+	case err := <-done:
+		assert.Equal(t, leader.ErrLeadershipLost, err)
+	case <-time.After(time.Second):
+		t.Fatal("leadership was not given up")
+	}
+	assert.False(t, elector.IsLeader())
+	assert.Error(t, leaderCtx.Err())
+}
@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "cassandra_operator"
+
+// phases are all the phases a cluster can be in, the phase gauge is 1 for the current phase and 0 for the others
+var phases = []v1alpha1.ClusterPhase{
+	v1alpha1.ClusterPhaseInitial,
+	v1alpha1.ClusterPhaseCreating,
+	v1alpha1.ClusterPhaseInitializing,
+	v1alpha1.ClusterPhaseRunning,
+	v1alpha1.ClusterPhaseScaling,
+	v1alpha1.ClusterPhaseFailed,
+	v1alpha1.ClusterPhaseTerminating,
+	v1alpha1.ClusterPhaseUnknown,
+}
+
+var (
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_total",
+		Help:      "Number of reconciles of a cluster.",
+	}, []string{"namespace", "cluster"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_errors_total",
+		Help:      "Number of reconciles of a cluster that failed.",
+	}, []string{"namespace", "cluster"})
+
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of the reconciles of a cluster.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"namespace", "cluster"})
+
+	nodetoolDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "nodetool_duration_seconds",
+		Help:      "Duration of the nodetool commands executed in the cassandra pods.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 30, 60, 300, 900},
+	}, []string{"command"})
+
+	nodetoolFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "nodetool_failures_total",
+		Help:      "Number of nodetool commands that failed.",
+	}, []string{"command"})
+
+	clusterPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_phase",
+		Help:      "Phase of a cluster, 1 for the current phase and 0 for the others.",
+	}, []string{"namespace", "cluster", "phase"})
+
+	clusterMembers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_members",
+		Help:      "Number of nodes of a cluster by state.",
+	}, []string{"namespace", "cluster", "state"})
+
+	finalizerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "finalizer_duration_seconds",
+		Help:      "Duration of draining and stopping a node before its pod is deleted.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800},
+	}, []string{"namespace", "cluster", "result"})
+
+	repairLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "repair_last_success_timestamp_seconds",
+		Help:      "Unix time the last repair job of a cluster completed successfully.",
+	}, []string{"namespace", "cluster"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		reconcileTotal,
+		reconcileErrors,
+		reconcileDuration,
+		nodetoolDuration,
+		nodetoolFailures,
+		clusterPhase,
+		clusterMembers,
+		finalizerDuration,
+		repairLastSuccess,
+	)
+}
+
+// Handler returns the http handler that serves the metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveReconcile records a reconcile of the cluster that started at the time
+func ObserveReconcile(cc *v1alpha1.CassandraCluster, start time.Time, err error) {
+	reconcileTotal.WithLabelValues(cc.GetNamespace(), cc.GetName()).Inc()
+	reconcileDuration.WithLabelValues(cc.GetNamespace(), cc.GetName()).Observe(time.Since(start).Seconds())
+	if err != nil {
+		reconcileErrors.WithLabelValues(cc.GetNamespace(), cc.GetName()).Inc()
+	}
+}
+
+// ObserveNodetool records a nodetool command that started at the time
+func ObserveNodetool(command string, start time.Time, err error) {
+	nodetoolDuration.WithLabelValues(command).Observe(time.Since(start).Seconds())
+	if err != nil {
+		nodetoolFailures.WithLabelValues(command).Inc()
+	}
+}
+
+// SetClusterStatus records the phase of the cluster and the number of nodes in each state
+func SetClusterStatus(cc *v1alpha1.CassandraCluster) {
+	for _, phase := range phases {
+		value := 0.0
+		if cc.Status.Phase == phase {
+			value = 1
+		}
+		clusterPhase.WithLabelValues(cc.GetNamespace(), cc.GetName(), string(phase)).Set(value)
+	}
+
+	for state, count := range memberCounts(&cc.Status.Members) {
+		clusterMembers.WithLabelValues(cc.GetNamespace(), cc.GetName(), state).Set(float64(count))
+	}
+}
+
+// ObserveFinalizer records the processing of the finalizer of a node of the cluster that started at the time
+func ObserveFinalizer(namespace, cluster string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	finalizerDuration.WithLabelValues(namespace, cluster, result).Observe(time.Since(start).Seconds())
+}
+
+// SetRepairLastSuccess records the time the last repair of the cluster completed successfully
+func SetRepairLastSuccess(cc *v1alpha1.CassandraCluster, completed time.Time) {
+	repairLastSuccess.WithLabelValues(cc.GetNamespace(), cc.GetName()).Set(float64(completed.Unix()))
+}
+
+// DeleteCluster removes the metrics of a deleted cluster
+func DeleteCluster(namespace, cluster string) {
+	reconcileTotal.DeleteLabelValues(namespace, cluster)
+	reconcileErrors.DeleteLabelValues(namespace, cluster)
+	reconcileDuration.DeleteLabelValues(namespace, cluster)
+	repairLastSuccess.DeleteLabelValues(namespace, cluster)
+	for _, phase := range phases {
+		clusterPhase.DeleteLabelValues(namespace, cluster, string(phase))
+	}
+	for state := range memberCounts(&v1alpha1.NodesStatus{}) {
+		clusterMembers.DeleteLabelValues(namespace, cluster, state)
+	}
+	for _, result := range []string{"success", "error"} {
+		finalizerDuration.DeleteLabelValues(namespace, cluster, result)
+	}
+}
+
+func memberCounts(members *v1alpha1.NodesStatus) map[string]int {
+	return map[string]int{
+		"creating": len(members.Creating),
+		"ready":    len(members.Ready),
+		"joining":  len(members.Joining),
+		"leaving":  len(members.Leaving),
+		"unready":  len(members.Unready),
+		"deleted":  len(members.Deleted),
+	}
+}
@@ -0,0 +1,80 @@
+package metrics_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func scrape(t *testing.T) string {
+	server := httptest.NewServer(metrics.Handler())
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return string(body)
+}
+
+func getCluster() *v1alpha1.CassandraCluster {
+	return &v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "testnamespace",
+		},
+		Status: v1alpha1.ClusterStatus{
+			Phase: v1alpha1.ClusterPhaseScaling,
+			Members: v1alpha1.NodesStatus{
+				Ready:   []string{"test-cluster-cassandra-0", "test-cluster-cassandra-1"},
+				Joining: []string{"test-cluster-cassandra-2"},
+			},
+		},
+	}
+}
+
+func TestMetrics_Cluster(t *testing.T) {
+	cc := getCluster()
+	metrics.ObserveReconcile(cc, time.Now(), nil)
+	metrics.ObserveReconcile(cc, time.Now(), errors.New("failed"))
+	metrics.SetClusterStatus(cc)
+	metrics.SetRepairLastSuccess(cc, time.Unix(1500000000, 0))
+
+	body := scrape(t)
+	assert.Contains(t, body, `cassandra_operator_reconcile_total{cluster="test-cluster",namespace="testnamespace"} 2`)
+	assert.Contains(t, body, `cassandra_operator_reconcile_errors_total{cluster="test-cluster",namespace="testnamespace"} 1`)
+	assert.Contains(t, body, `cassandra_operator_reconcile_duration_seconds_count{cluster="test-cluster",namespace="testnamespace"} 2`)
+	assert.Contains(t, body, `cassandra_operator_cluster_phase{cluster="test-cluster",namespace="testnamespace",phase="Scaling"} 1`)
+	assert.Contains(t, body, `cassandra_operator_cluster_phase{cluster="test-cluster",namespace="testnamespace",phase="Running"} 0`)
+	assert.Contains(t, body, `cassandra_operator_cluster_members{cluster="test-cluster",namespace="testnamespace",state="ready"} 2`)
+	assert.Contains(t, body, `cassandra_operator_cluster_members{cluster="test-cluster",namespace="testnamespace",state="joining"} 1`)
+	assert.Contains(t, body, `cassandra_operator_repair_last_success_timestamp_seconds{cluster="test-cluster",namespace="testnamespace"} 1.5e+09`)
+
+	metrics.DeleteCluster(cc.GetNamespace(), cc.GetName())
+	assert.NotContains(t, scrape(t), `cluster="test-cluster"`)
+}
+
+func TestMetrics_Nodetool(t *testing.T) {
+	metrics.ObserveNodetool("drain", time.Now(), nil)
+	metrics.ObserveNodetool("drain", time.Now(), errors.New("failed"))
+
+	body := scrape(t)
+	assert.Contains(t, body, `cassandra_operator_nodetool_duration_seconds_count{command="drain"} 2`)
+	assert.Contains(t, body, `cassandra_operator_nodetool_failures_total{command="drain"} 1`)
+}
+
+func TestMetrics_Finalizer(t *testing.T) {
+	metrics.ObserveFinalizer("testnamespace", "finalizer-cluster", time.Now(), nil)
+
+	body := scrape(t)
+	assert.Contains(t, body, `cassandra_operator_finalizer_duration_seconds_count{cluster="finalizer-cluster",namespace="testnamespace",result="success"} 1`)
+}
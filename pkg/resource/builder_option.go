@@ -5,6 +5,8 @@ type builderOp struct {
 	PodNumber          int
 	ServiceName        string
 	ServiceAccountName string
+	CertsRevision      string
+	HoldScaleUp        bool
 }
 
 // BuilderOption is a function that sets the configuration on the builderOp
@@ -51,3 +53,17 @@ func WithServiceAccountName(serviceAccountName string) BuilderOption {
 		op.ServiceAccountName = serviceAccountName
 	}
 }
+
+// WithCertificatesRevision sets the revision of the node certificates the pods are started with
+func WithCertificatesRevision(revision string) BuilderOption {
+	return func(op *builderOp) {
+		op.CertsRevision = revision
+	}
+}
+
+// WithScaleUpHeld keeps the statefulset from adding nodes, it is held while nodes are still streaming
+func WithScaleUpHeld(hold bool) BuilderOption {
+	return func(op *builderOp) {
+		op.HoldScaleUp = hold
+	}
+}
@@ -2,8 +2,8 @@ package resource
 
 import (
 	"fmt"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	"strconv"
 	"strings"
 )
@@ -17,8 +17,8 @@ func (b *StatefulSet) buildDesiredCassandraPodSpec() {
 	b.buildPodVolumes()
 	b.buildCassandraContainer()
 
-	if b.cluster.Spec.JvmAgent == "sidecar" {
-		b.buildTelegrafContainer()
+	if policy := Monitoring(b.cluster); policy != nil {
+		b.buildExporterContainer(policy)
 	}
 
 	if b.cluster.Spec.Affinity != nil {
@@ -27,24 +27,36 @@ func (b *StatefulSet) buildDesiredCassandraPodSpec() {
 }
 
 func (b *StatefulSet) buildPodVolumes() {
-	secretName := fmt.Sprintf("%s-cassandra-certs", b.cluster.GetName())
-	if b.cluster.Spec.SecretName != "" {
-		secretName = b.cluster.Spec.SecretName
-	}
+	secretName := CertificatesSecretName(b.cluster)
+
+	jvmAgentConfigName := JvmAgentConfigName(b.cluster)
 
-	jvmAgentConfigName := fmt.Sprintf("%s-prometheus-jvm-agent-config", b.cluster.GetName())
-	if b.cluster.Spec.JvmAgentConfigName != "" {
-		jvmAgentConfigName = b.cluster.Spec.JvmAgentConfigName
+	keystoreVolume := corev1.VolumeSource{
+		Secret: &corev1.SecretVolumeSource{
+			SecretName: secretName,
+		},
+	}
+	// the keystore read from vault is materialised through a projected volume of the secret it is copied into
+	if VaultKeystore(b.cluster) {
+		keystoreVolume = corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						Secret: &corev1.SecretProjection{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: secretName,
+							},
+						},
+					},
+				},
+			},
+		}
 	}
 
 	b.desired.Spec.Template.Spec.Volumes = []corev1.Volume{
 		{
-			Name: "cassandra-keystore",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: secretName,
-				},
-			},
+			Name:         "cassandra-keystore",
+			VolumeSource: keystoreVolume,
 		},
 		{
 			Name: "jvm-agent-config",
@@ -57,48 +69,34 @@ func (b *StatefulSet) buildPodVolumes() {
 			},
 		},
 	}
-}
 
-func (b *StatefulSet) buildTelegrafContainer() {
-	// telegraf metrics sidecar
-	// https://hub.docker.com/_/telegraf/
-	container := corev1.Container{
-		Name:            "telegraf",
-		Image:           "telegraf:1.2",
-		ImagePullPolicy: corev1.PullIfNotPresent,
-		Args: []string{
-			"--config",
-			"/telegraf-config/telegraf.conf",
-		},
-		Ports: []corev1.ContainerPort{
-			{
-				ContainerPort: 9126,
-				Name:          "prometheus",
-			},
-		},
-		VolumeMounts: []corev1.VolumeMount{
-			{
-				Name:      "jvm-agent-config",
-				MountPath: "/telegraf-config",
-			},
-			// mount cassandra's persistent-disk into the telegraf pod so that telegraf can collect usage metrics
-			{
-				Name:      fmt.Sprintf("%s-cassandra-data", b.cluster.GetName()),
-				MountPath: b.getFileMountPath(),
-			},
-		},
-		Resources: corev1.ResourceRequirements{
-			Limits: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("1"),
-				corev1.ResourceMemory: resource.MustParse("128Mi"),
+	if policy := Monitoring(b.cluster); policy != nil && policy.Exporter == v1alpha1.ExporterJMX && policy.Image != "" {
+		b.desired.Spec.Template.Spec.Volumes = append(b.desired.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: jmxExporterVolume,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
 			},
-			Requests: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("0.1"),
-				corev1.ResourceMemory: resource.MustParse("64Mi"),
+		})
+	}
+
+	// unlike an env var the file is updated once the load balancer of the node got its ip
+	if broadcastAddressPublished(b.cluster) {
+		b.desired.Spec.Template.Spec.Volumes = append(b.desired.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: broadcastAddressVolume,
+			VolumeSource: corev1.VolumeSource{
+				DownwardAPI: &corev1.DownwardAPIVolumeSource{
+					Items: []corev1.DownwardAPIVolumeFile{
+						{
+							Path: broadcastAddressFile,
+							FieldRef: &corev1.ObjectFieldSelector{
+								FieldPath: fmt.Sprintf("metadata.annotations['%s']", BroadcastAddressAnnotation),
+							},
+						},
+					},
+				},
 			},
-		},
+		})
 	}
-	b.desired.Spec.Template.Spec.Containers = append(b.desired.Spec.Template.Spec.Containers, container)
 }
 
 func (b *StatefulSet) buildCassandraContainer() {
@@ -143,7 +141,7 @@ func (b *StatefulSet) buildContainerVolumeMounts() []corev1.VolumeMount {
 	mounts := []corev1.VolumeMount{
 		{
 			Name:      "cassandra-keystore",
-			MountPath: "/keystore",
+			MountPath: keystoreMountPath,
 		},
 		{
 			Name:      fmt.Sprintf("%s-cassandra-data", b.cluster.GetName()),
@@ -160,11 +158,22 @@ func (b *StatefulSet) buildContainerVolumeMounts() []corev1.VolumeMount {
 		})
 	}
 
+	if policy := Monitoring(b.cluster); policy != nil && policy.Exporter == v1alpha1.ExporterJMX {
+		mounts = append(mounts, b.buildJMXExporterVolumeMounts(policy)...)
+	}
+
+	if broadcastAddressPublished(b.cluster) {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      broadcastAddressVolume,
+			MountPath: broadcastAddressMountPath,
+		})
+	}
+
 	return mounts
 }
 
 func (b *StatefulSet) buildContainerPorts() []corev1.ContainerPort {
-	return []corev1.ContainerPort{
+	ports := []corev1.ContainerPort{
 		{
 			ContainerPort: 7000,
 			Name:          "intra-node",
@@ -190,6 +199,13 @@ func (b *StatefulSet) buildContainerPorts() []corev1.ContainerPort {
 			Name:          "metrics",
 		},
 	}
+
+	// the jmx-exporter serves the metrics from inside the cassandra JVM
+	if policy := Monitoring(b.cluster); policy != nil && policy.Exporter == v1alpha1.ExporterJMX {
+		ports = append(ports, exporterPorts(policy)...)
+	}
+
+	return ports
 }
 
 func (b *StatefulSet) buildReadinessProbe() *corev1.Probe {
@@ -273,5 +289,80 @@ func (b *StatefulSet) buildEnvVars() []corev1.EnvVar {
 			})
 	}
 
+	if b.cluster.Spec.Auth != nil && b.cluster.Spec.Auth.Enabled {
+		vars = append(vars,
+			corev1.EnvVar{
+				Name:  "CASSANDRA_AUTHENTICATOR",
+				Value: passwordAuthenticator,
+			},
+			corev1.EnvVar{
+				Name:  "CASSANDRA_AUTHORIZER",
+				Value: cassandraAuthorizer,
+			})
+	}
+
+	if TLSManaged(b.cluster) {
+		vars = append(vars, b.buildTLSEnvVars()...)
+	}
+
+	if policy := Monitoring(b.cluster); policy != nil && policy.Exporter == v1alpha1.ExporterJMX {
+		vars = append(vars, b.buildJMXExporterEnvVars(policy)...)
+	}
+
+	if broadcastAddressPublished(b.cluster) {
+		vars = append(vars, corev1.EnvVar{
+			Name:  "CASSANDRA_BROADCAST_ADDRESS_FILE",
+			Value: fmt.Sprintf("%s/%s", broadcastAddressMountPath, broadcastAddressFile),
+		})
+	}
+
 	return vars
 }
+
+// buildTLSEnvVars points cassandra at the keystore issued for the pod, the keystore and truststore share a password
+func (b *StatefulSet) buildTLSEnvVars() []corev1.EnvVar {
+	password := &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: keystorePasswordSecretName(b.cluster),
+			},
+			Key: keystorePasswordKey,
+		},
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name: "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		},
+		{
+			Name:  "CASSANDRA_INTERNODE_ENCRYPTION",
+			Value: internodeEncryption,
+		},
+		{
+			Name:  "CASSANDRA_CLIENT_ENCRYPTION",
+			Value: strconv.FormatBool(ClientEncryptionEnabled(b.cluster)),
+		},
+		{
+			// the env var is expanded by kubernetes, every pod has its own keystore in the secret
+			Name:  "CASSANDRA_KEYSTORE_PATH",
+			Value: fmt.Sprintf("%s/%s", keystoreMountPath, fmt.Sprintf(keystoreKeyTemplate, "$(POD_NAME)")),
+		},
+		{
+			Name:      "CASSANDRA_KEYSTORE_PASSWORD",
+			ValueFrom: password,
+		},
+		{
+			Name:  "CASSANDRA_TRUSTSTORE_PATH",
+			Value: fmt.Sprintf("%s/%s", keystoreMountPath, truststoreKey),
+		},
+		{
+			Name:      "CASSANDRA_TRUSTSTORE_PASSWORD",
+			ValueFrom: password,
+		},
+	}
+}
@@ -0,0 +1,421 @@
+package resource
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/certs"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CertificatesRevisionAnnotation is bumped on the certs secret every time the node certificates are reissued,
+	// pods annotated with an older revision are restarted to pick up the new keystores
+	CertificatesRevisionAnnotation = "database.panth.io/certificates-revision"
+
+	// CACertificateKey is the key of the PEM encoded CA certificate in the CA and certs secrets
+	CACertificateKey = "ca.crt"
+	caKeyKey         = "ca.key"
+
+	truststoreKey              = "truststore.jks"
+	keystoreKeyTemplate        = "%s.keystore.jks"
+	nodeCertificateKeyTemplate = "%s.crt"
+	nodeKeyKeyTemplate         = "%s.key"
+	keystorePasswordKey        = "password"
+
+	truststoreAlias = "ca"
+	keystoreAlias   = "cassandra"
+
+	caValidity                     = 10 * 365 * 24 * time.Hour
+	defaultCertificateValidityDays = 365
+	defaultRenewBeforeDays         = 30
+)
+
+// TLSManaged returns true if the operator issues the certificates of the cluster
+func TLSManaged(cc *v1alpha1.CassandraCluster) bool {
+	return cc.Spec.TLS != nil && cc.Spec.TLS.Managed
+}
+
+// ClientEncryptionEnabled returns true if the native transport of the cluster requires TLS
+func ClientEncryptionEnabled(cc *v1alpha1.CassandraCluster) bool {
+	return TLSManaged(cc) && cc.Spec.TLS.ClientEncryption
+}
+
+// CertificatesSecretName returns the name of the secret mounted as the keystore of the nodes
+func CertificatesSecretName(cc *v1alpha1.CassandraCluster) string {
+	if VaultKeystore(cc) {
+		return VaultKeystoreSecretName(cc)
+	}
+	if cc.Spec.SecretName != "" {
+		return cc.Spec.SecretName
+	}
+	return fmt.Sprintf(certsSecretNameTemplate, cc.GetName())
+}
+
+// CASecretName returns the name of the secret with the CA that signs the node certificates
+func CASecretName(cc *v1alpha1.CassandraCluster) string {
+	return fmt.Sprintf(caSecretNameTemplate, cc.GetName())
+}
+
+func keystorePasswordSecretName(cc *v1alpha1.CassandraCluster) string {
+	return fmt.Sprintf(keystorePasswordSecretNameTemplate, cc.GetName())
+}
+
+// certificateValidity returns how long node certificates are valid for and how long before expiry they are
+// reissued, renewing is capped at half the validity so certificates are not reissued on every reconcile
+func certificateValidity(policy *v1alpha1.TLSPolicy) (time.Duration, time.Duration) {
+	validityDays := defaultCertificateValidityDays
+	renewBeforeDays := defaultRenewBeforeDays
+	if policy != nil && policy.ValidityDays > 0 {
+		validityDays = policy.ValidityDays
+	}
+	if policy != nil && policy.RenewBeforeDays > 0 {
+		renewBeforeDays = policy.RenewBeforeDays
+	}
+
+	validity := time.Duration(validityDays) * 24 * time.Hour
+	renewBefore := time.Duration(renewBeforeDays) * 24 * time.Hour
+	if renewBefore > validity/2 {
+		renewBefore = validity / 2
+	}
+
+	return validity, renewBefore
+}
+
+// CertificateAuthority builds the secret with the CA that signs the node certificates of a cluster
+type CertificateAuthority struct {
+	cluster   *v1alpha1.CassandraCluster
+	authority *certs.Authority
+}
+
+// NewCertificateAuthority constructor for CertificateAuthority
+func NewCertificateAuthority(cc *v1alpha1.CassandraCluster) *CertificateAuthority {
+	return &CertificateAuthority{
+		cluster: cc,
+	}
+}
+
+// Reconcile generates the CA into its secret if it does not exist, an existing CA is loaded and never replaced
+func (b *CertificateAuthority) Reconcile(driver opsdk.Client) (sdk.Object, error) {
+	existing := &corev1.Secret{
+		TypeMeta:   GetSecretTypeMeta(),
+		ObjectMeta: buildCertificatesObjectMeta(b.cluster, CASecretName(b.cluster)),
+	}
+	err := driver.Get(existing)
+	if err != nil {
+		return nil, errors.New("could not get existing")
+	}
+
+	if existing.ResourceVersion != "" {
+		b.authority, err = certs.ParseAuthority(existing.Data[CACertificateKey], existing.Data[caKeyKey])
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA in secret %s: %v", existing.GetName(), err)
+		}
+		return existing, nil
+	}
+
+	logrus.Infof("Generating the certificate authority of cluster %s", b.cluster.GetName())
+	b.authority, err = certs.NewAuthority(fmt.Sprintf("%s-cassandra-ca", b.cluster.GetName()), caValidity)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := &corev1.Secret{
+		TypeMeta:   GetSecretTypeMeta(),
+		ObjectMeta: buildCertificatesObjectMeta(b.cluster, CASecretName(b.cluster)),
+		Type:       corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			CACertificateKey: b.authority.CertificatePEM(),
+			caKeyKey:         b.authority.KeyPEM(),
+		},
+	}
+
+	err = driver.Create(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	return desired, nil
+}
+
+// Authority returns the CA loaded or generated by the last reconcile
+func (b *CertificateAuthority) Authority() *certs.Authority {
+	return b.authority
+}
+
+// KeystorePassword builds the secret with the generated password of the node keystores and the truststore
+type KeystorePassword struct {
+	cluster  *v1alpha1.CassandraCluster
+	password string
+}
+
+// NewKeystorePassword constructor for KeystorePassword
+func NewKeystorePassword(cc *v1alpha1.CassandraCluster) *KeystorePassword {
+	return &KeystorePassword{
+		cluster: cc,
+	}
+}
+
+// Reconcile generates the password into its secret if it does not exist
+func (b *KeystorePassword) Reconcile(driver opsdk.Client) (sdk.Object, error) {
+	existing := &corev1.Secret{
+		TypeMeta:   GetSecretTypeMeta(),
+		ObjectMeta: buildCertificatesObjectMeta(b.cluster, keystorePasswordSecretName(b.cluster)),
+	}
+	err := driver.Get(existing)
+	if err != nil {
+		return nil, errors.New("could not get existing")
+	}
+
+	if existing.ResourceVersion != "" {
+		b.password = string(existing.Data[keystorePasswordKey])
+		if b.password == "" {
+			return nil, fmt.Errorf("secret %s has no %s", existing.GetName(), keystorePasswordKey)
+		}
+		return existing, nil
+	}
+
+	b.password, err = cql.GeneratePassword()
+	if err != nil {
+		return nil, err
+	}
+
+	desired := &corev1.Secret{
+		TypeMeta:   GetSecretTypeMeta(),
+		ObjectMeta: buildCertificatesObjectMeta(b.cluster, keystorePasswordSecretName(b.cluster)),
+		Type:       corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			keystorePasswordKey: []byte(b.password),
+		},
+	}
+
+	err = driver.Create(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	return desired, nil
+}
+
+// Password returns the password loaded or generated by the last reconcile
+func (b *KeystorePassword) Password() string {
+	return b.password
+}
+
+// Certificates builds the certs secret with a keystore per node and the truststore of the cluster
+type Certificates struct {
+	cluster   *v1alpha1.CassandraCluster
+	authority *certs.Authority
+	password  string
+	revision  string
+	now       time.Time
+}
+
+// NewCertificates constructor for Certificates
+func NewCertificates(cc *v1alpha1.CassandraCluster, authority *certs.Authority, password string) *Certificates {
+	return &Certificates{
+		cluster:   cc,
+		authority: authority,
+		password:  password,
+		now:       time.Now(),
+	}
+}
+
+// Reconcile issues the keystores of new nodes, all node certificates are reissued under a new revision when
+// they are about to expire or when the CA or the keystore password changed
+func (b *Certificates) Reconcile(driver opsdk.Client) (sdk.Object, error) {
+	if b.authority == nil || b.password == "" {
+		return nil, errors.New("certificates require a CA and a keystore password")
+	}
+
+	existing := &corev1.Secret{
+		TypeMeta:   GetSecretTypeMeta(),
+		ObjectMeta: buildCertificatesObjectMeta(b.cluster, CertificatesSecretName(b.cluster)),
+	}
+	err := driver.Get(existing)
+	if err != nil {
+		return nil, errors.New("could not get existing")
+	}
+
+	// never take over keystores that were built by hand
+	if existing.ResourceVersion != "" && !ownedBy(existing.ObjectMeta, b.cluster.GetUID()) {
+		return nil, fmt.Errorf("secret %s is not managed by the operator, remove it or disable tls.managed", existing.GetName())
+	}
+
+	desired := &corev1.Secret{
+		TypeMeta:   GetSecretTypeMeta(),
+		ObjectMeta: buildCertificatesObjectMeta(b.cluster, CertificatesSecretName(b.cluster)),
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{},
+	}
+	desired.ResourceVersion = existing.ResourceVersion
+	desired.Annotations = mergeMap(map[string]string{}, existing.Annotations)
+	for key, value := range existing.Data {
+		desired.Data[key] = value
+	}
+
+	validity, renewBefore := certificateValidity(b.cluster.Spec.TLS)
+	b.revision = desired.Annotations[CertificatesRevisionAnnotation]
+	renew := b.needsRenewal(desired.Data, renewBefore)
+
+	changed := renew
+	if renew {
+		desired.Data[CACertificateKey] = b.authority.CertificatePEM()
+		desired.Data[truststoreKey], err = certs.EncodeTruststore(b.password, truststoreAlias, b.authority.Certificate, b.now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < b.cluster.Spec.Size; i++ {
+		podName := fmt.Sprintf("%s-cassandra-%d", b.cluster.GetName(), i)
+		if !renew && desired.Data[fmt.Sprintf(keystoreKeyTemplate, podName)] != nil {
+			continue
+		}
+
+		err = b.issue(desired.Data, i, validity)
+		if err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+
+	if !changed {
+		return existing, nil
+	}
+
+	if renew {
+		b.revision = nextRevision(b.revision)
+		desired.Annotations[CertificatesRevisionAnnotation] = b.revision
+		logrus.Infof("Issued revision %s of the node certificates of cluster %s", b.revision, b.cluster.GetName())
+	}
+
+	if existing.ResourceVersion == "" {
+		err = driver.Create(desired)
+	} else {
+		err = driver.Update(desired)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return desired, nil
+}
+
+// Revision returns the revision of the node certificates after the last reconcile
+func (b *Certificates) Revision() string {
+	return b.revision
+}
+
+// needsRenewal returns true if the secret was never issued, the CA or password changed or a node certificate
+// expires within the renewal window
+func (b *Certificates) needsRenewal(data map[string][]byte, renewBefore time.Duration) bool {
+	if b.revision == "" {
+		return true
+	}
+
+	if !bytes.Equal(data[CACertificateKey], b.authority.CertificatePEM()) {
+		logrus.Infof("The CA of cluster %s changed, reissuing the node certificates", b.cluster.GetName())
+		return true
+	}
+
+	if !certs.KeystorePasswordMatches(b.password, data[truststoreKey]) {
+		logrus.Infof("The keystore password of cluster %s changed, reissuing the node certificates", b.cluster.GetName())
+		return true
+	}
+
+	for i := 0; i < b.cluster.Spec.Size; i++ {
+		podName := fmt.Sprintf("%s-cassandra-%d", b.cluster.GetName(), i)
+		certPEM, ok := data[fmt.Sprintf(nodeCertificateKeyTemplate, podName)]
+		if !ok {
+			continue
+		}
+
+		cert, err := certs.ParseCertificatePEM(certPEM)
+		if err != nil {
+			logrus.Warnf("Invalid certificate for pod %s, reissuing the node certificates: %v", podName, err)
+			return true
+		}
+
+		if !b.now.Add(renewBefore).Before(cert.NotAfter) {
+			logrus.Infof("The certificate of pod %s expires at %s, reissuing the node certificates", podName, cert.NotAfter)
+			return true
+		}
+	}
+
+	return false
+}
+
+// issue signs a certificate for the pod and stores it with its keystore in the secret data
+func (b *Certificates) issue(data map[string][]byte, podNumber int, validity time.Duration) error {
+	podName := fmt.Sprintf("%s-cassandra-%d", b.cluster.GetName(), podNumber)
+	node, err := b.authority.Issue(podName, b.dnsNames(podNumber), validity)
+	if err != nil {
+		return err
+	}
+
+	keystore, err := certs.EncodeKeystore(b.password, keystoreAlias, node.Key, []*x509.Certificate{node.Certificate, b.authority.Certificate}, b.now)
+	if err != nil {
+		return err
+	}
+
+	data[fmt.Sprintf(keystoreKeyTemplate, podName)] = keystore
+	data[fmt.Sprintf(nodeCertificateKeyTemplate, podName)] = node.CertificatePEM()
+	data[fmt.Sprintf(nodeKeyKeyTemplate, podName)] = node.KeyPEM()
+
+	return nil
+}
+
+// dnsNames returns the names a node is reached by, the pod name under the headless service, the internal and public
+// services shared by all nodes and the public service of the pod
+func (b *Certificates) dnsNames(podNumber int) []string {
+	name := b.cluster.GetName()
+	namespace := b.cluster.GetNamespace()
+	podName := fmt.Sprintf("%s-cassandra-%d", name, podNumber)
+	headless := fmt.Sprintf("%s-cassandra-headless", name)
+
+	names := []string{podName}
+	for _, host := range []string{
+		fmt.Sprintf("%s.%s", podName, headless),
+		headless,
+		fmt.Sprintf("%s-cassandra", name),
+		fmt.Sprintf("%s-cassandra-public", name),
+		fmt.Sprintf("%s-cassandra-public-%d", name, podNumber),
+	} {
+		names = append(names,
+			host,
+			fmt.Sprintf("%s.%s", host, namespace),
+			fmt.Sprintf("%s.%s.svc", host, namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", host, namespace),
+		)
+	}
+
+	return names
+}
+
+func buildCertificatesObjectMeta(cc *v1alpha1.CassandraCluster, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       cc.GetNamespace(),
+		Labels:          map[string]string{"cluster": cc.GetName()},
+		OwnerReferences: []metav1.OwnerReference{asOwner(cc)},
+	}
+}
+
+func nextRevision(revision string) string {
+	current, err := strconv.Atoi(revision)
+	if err != nil {
+		current = 0
+	}
+	return strconv.Itoa(current + 1)
+}
@@ -0,0 +1,192 @@
+package resource_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/certs"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// secretStore is a mock client that keeps the secrets it is asked to create or update
+type secretStore struct {
+	secrets map[string]*corev1.Secret
+	creates int
+	updates int
+}
+
+func newSecretStore() *secretStore {
+	return &secretStore{secrets: map[string]*corev1.Secret{}}
+}
+
+func (s *secretStore) client() *k8s.MockClient {
+	return &k8s.MockClient{
+		GetCallback: func(into sdk.Object, opts ...sdk.GetOption) error {
+			secret := into.(*corev1.Secret)
+			if stored, ok := s.secrets[secret.GetName()]; ok {
+				stored.DeepCopyInto(secret)
+			}
+			return nil
+		},
+		CreateCallback: func(object sdk.Object) error {
+			secret := object.(*corev1.Secret).DeepCopy()
+			secret.ResourceVersion = "1"
+			s.secrets[secret.GetName()] = secret
+			s.creates++
+			return nil
+		},
+		UpdateCallback: func(object sdk.Object) error {
+			s.secrets[object.(*corev1.Secret).GetName()] = object.(*corev1.Secret).DeepCopy()
+			s.updates++
+			return nil
+		},
+	}
+}
+
+func getTLSCluster(size int) *v1alpha1.CassandraCluster {
+	return &v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-1",
+			Namespace: "test-namespace",
+			UID:       "test-cluster-uid",
+		},
+		Spec: v1alpha1.ClusterSpec{
+			Size: size,
+			TLS:  &v1alpha1.TLSPolicy{Managed: true},
+		},
+	}
+}
+
+func reconcileCertificates(t *testing.T, store *secretStore, cluster *v1alpha1.CassandraCluster) *resource.Certificates {
+	driver := store.client()
+
+	ca := resource.NewCertificateAuthority(cluster)
+	_, err := ca.Reconcile(driver)
+	assert.NoError(t, err)
+
+	password := resource.NewKeystorePassword(cluster)
+	_, err = password.Reconcile(driver)
+	assert.NoError(t, err)
+
+	certificates := resource.NewCertificates(cluster, ca.Authority(), password.Password())
+	_, err = certificates.Reconcile(driver)
+	assert.NoError(t, err)
+
+	return certificates
+}
+
+func TestCertificates_Reconcile(t *testing.T) {
+	store := newSecretStore()
+	cluster := getTLSCluster(2)
+
+	certificates := reconcileCertificates(t, store, cluster)
+	assert.Equal(t, "1", certificates.Revision())
+	assert.Equal(t, 3, store.creates)
+
+	caSecret := store.secrets["test-cluster-1-cassandra-ca"]
+	passwordSecret := store.secrets["test-cluster-1-cassandra-keystore-password"]
+	certsSecret := store.secrets["test-cluster-1-cassandra-certs"]
+	if !assert.NotNil(t, caSecret) || !assert.NotNil(t, passwordSecret) || !assert.NotNil(t, certsSecret) {
+		return
+	}
+
+	assert.Equal(t, "1", certsSecret.Annotations["database.panth.io/certificates-revision"])
+	assert.Equal(t, caSecret.Data["ca.crt"], certsSecret.Data["ca.crt"])
+	assert.NotContains(t, certsSecret.Data, "ca.key", "the CA key must not be mounted into the pods")
+	assert.True(t, certs.KeystorePasswordMatches(string(passwordSecret.Data["password"]), certsSecret.Data["truststore.jks"]))
+
+	for _, pod := range []string{"test-cluster-1-cassandra-0", "test-cluster-1-cassandra-1"} {
+		assert.True(t, certs.KeystorePasswordMatches(string(passwordSecret.Data["password"]), certsSecret.Data[pod+".keystore.jks"]))
+
+		cert, err := certs.ParseCertificatePEM(certsSecret.Data[pod+".crt"])
+		if assert.NoError(t, err) {
+			assert.Equal(t, pod, cert.Subject.CommonName)
+			assert.Contains(t, cert.DNSNames, pod+".test-cluster-1-cassandra-headless.test-namespace.svc.cluster.local")
+			assert.Contains(t, cert.DNSNames, "test-cluster-1-cassandra.test-namespace.svc.cluster.local")
+			assert.Contains(t, cert.DNSNames, "test-cluster-1-cassandra-public.test-namespace.svc.cluster.local")
+		}
+	}
+
+	publicPod, err := certs.ParseCertificatePEM(certsSecret.Data["test-cluster-1-cassandra-1.crt"])
+	if assert.NoError(t, err) {
+		assert.Contains(t, publicPod.DNSNames, "test-cluster-1-cassandra-public-1.test-namespace.svc.cluster.local")
+	}
+
+	// nothing changed, nothing is written
+	certificates = reconcileCertificates(t, store, cluster)
+	assert.Equal(t, "1", certificates.Revision())
+	assert.Equal(t, 3, store.creates)
+	assert.Equal(t, 0, store.updates)
+}
+
+func TestCertificates_ReconcileScaleUp(t *testing.T) {
+	store := newSecretStore()
+	reconcileCertificates(t, store, getTLSCluster(1))
+	keystore := store.secrets["test-cluster-1-cassandra-certs"].Data["test-cluster-1-cassandra-0.keystore.jks"]
+
+	certificates := reconcileCertificates(t, store, getTLSCluster(2))
+	assert.Equal(t, "1", certificates.Revision(), "a new node does not restart the existing ones")
+	assert.Equal(t, 1, store.updates)
+
+	data := store.secrets["test-cluster-1-cassandra-certs"].Data
+	assert.Equal(t, keystore, data["test-cluster-1-cassandra-0.keystore.jks"])
+	assert.NotEmpty(t, data["test-cluster-1-cassandra-1.keystore.jks"])
+}
+
+func TestCertificates_ReconcileRenew(t *testing.T) {
+	store := newSecretStore()
+	cluster := getTLSCluster(2)
+	reconcileCertificates(t, store, cluster)
+
+	// replace a node certificate with one that is about to expire
+	ca, err := certs.ParseAuthority(store.secrets["test-cluster-1-cassandra-ca"].Data["ca.crt"], store.secrets["test-cluster-1-cassandra-ca"].Data["ca.key"])
+	if !assert.NoError(t, err) {
+		return
+	}
+	expiring, err := ca.Issue("test-cluster-1-cassandra-1", nil, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+	secret := store.secrets["test-cluster-1-cassandra-certs"]
+	secret.Data["test-cluster-1-cassandra-1.crt"] = expiring.CertificatePEM()
+	keystore := secret.Data["test-cluster-1-cassandra-0.keystore.jks"]
+
+	certificates := reconcileCertificates(t, store, cluster)
+	assert.Equal(t, "2", certificates.Revision())
+
+	data := store.secrets["test-cluster-1-cassandra-certs"].Data
+	assert.False(t, bytes.Equal(keystore, data["test-cluster-1-cassandra-0.keystore.jks"]), "all nodes are reissued")
+	renewed, err := certs.ParseCertificatePEM(data["test-cluster-1-cassandra-1.crt"])
+	if assert.NoError(t, err) {
+		assert.True(t, renewed.NotAfter.After(time.Now().Add(300*24*time.Hour)))
+	}
+}
+
+func TestCertificates_ReconcileUnmanagedSecret(t *testing.T) {
+	store := newSecretStore()
+	store.secrets["test-cluster-1-cassandra-certs"] = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test-cluster-1-cassandra-certs",
+			Namespace:       "test-namespace",
+			ResourceVersion: "1",
+		},
+		Data: map[string][]byte{"keystore.jks": []byte("built by hand")},
+	}
+	cluster := getTLSCluster(1)
+	driver := store.client()
+
+	ca := resource.NewCertificateAuthority(cluster)
+	_, err := ca.Reconcile(driver)
+	assert.NoError(t, err)
+
+	_, err = resource.NewCertificates(cluster, ca.Authority(), "password").Reconcile(driver)
+	assert.Error(t, err)
+	assert.Equal(t, []byte("built by hand"), store.secrets["test-cluster-1-cassandra-certs"].Data["keystore.jks"])
+}
@@ -4,6 +4,9 @@ const (
 	cronJobAPIVersion             = "batch/v1beta1"
 	cronJobKind                   = "CronJob"
 	cronJobNameTemplate           = "%s-cassandra-repair"
+	jobAPIVersion                 = "batch/v1"
+	jobKind                       = "Job"
+	repairJobNameTemplate         = "%s-cassandra-repair-%s-%08x"
 	serviceAPIVersion             = "v1"
 	serviceKind                   = "Service"
 	statefulSetAPIVersion         = "apps/v1"
@@ -16,10 +19,31 @@ const (
 	podDisruptionBudgetKind       = "PodDisruptionBudget"
 	cassandraClusterAPIVersion    = "database.pantheon.io/v1alpha1"
 	cassandraClusterKind          = "CassandraCluster"
+	secretAPIVersion              = "v1"
+	secretKind                    = "Secret"
+	configMapAPIVersion           = "v1"
+	configMapKind                 = "ConfigMap"
+	serviceMonitorAPIVersion      = "monitoring.coreos.com/v1"
+	serviceMonitorKind            = "ServiceMonitor"
+
+	caSecretNameTemplate               = "%s-cassandra-ca"
+	certsSecretNameTemplate            = "%s-cassandra-certs"
+	keystorePasswordSecretNameTemplate = "%s-cassandra-keystore-password"
+	jvmAgentConfigNameTemplate         = "%s-prometheus-jvm-agent-config"
+	vaultKeystoreSecretNameTemplate    = "%s-cassandra-vault-keystore"
+	vaultCredentialsSecretNameTemplate = "%s-cassandra-vault-credentials"
+	serviceMonitorNameTemplate         = "%s-cassandra"
 
 	kubeNamespaceEnvVar    = "KUBE_NAMESPACE"
 	cassandraClusterEnvVar = "CASSANDRA_CLUSTER"
 	appNameEnvVar          = "APP_NAME"
+	keyspaceEnvVar         = "KEYSPACE"
 
 	ssdStorageClassName = "ssd"
+
+	passwordAuthenticator = "PasswordAuthenticator"
+	cassandraAuthorizer   = "CassandraAuthorizer"
+
+	keystoreMountPath   = "/keystore"
+	internodeEncryption = "all"
 )
@@ -0,0 +1,85 @@
+package resource
+
+import (
+	"errors"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CredentialsSecret builds a secret holding the username and a generated password of a cassandra role
+type CredentialsSecret struct {
+	name      string
+	namespace string
+	username  string
+	labels    map[string]string
+	owner     metav1.OwnerReference
+	created   bool
+}
+
+// NewCredentialsSecret constructor for CredentialsSecret
+func NewCredentialsSecret(name, namespace, username string, labels map[string]string, owner metav1.OwnerReference) *CredentialsSecret {
+	return &CredentialsSecret{
+		name:      name,
+		namespace: namespace,
+		username:  username,
+		labels:    labels,
+		owner:     owner,
+	}
+}
+
+// Reconcile creates the secret with a generated password if it does not exist, an existing secret is
+// returned as is so the password is never replaced by a reconcile
+func (b *CredentialsSecret) Reconcile(driver opsdk.Client) (sdk.Object, error) {
+	existing := &corev1.Secret{
+		TypeMeta: GetSecretTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.name,
+			Namespace: b.namespace,
+		},
+	}
+	err := driver.Get(existing)
+	if err != nil {
+		return nil, errors.New("could not get existing")
+	}
+
+	if existing.ResourceVersion != "" {
+		return existing, nil
+	}
+
+	password, err := cql.GeneratePassword()
+	if err != nil {
+		return nil, err
+	}
+
+	desired := &corev1.Secret{
+		TypeMeta: GetSecretTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            b.name,
+			Namespace:       b.namespace,
+			Labels:          b.labels,
+			OwnerReferences: []metav1.OwnerReference{b.owner},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			cql.UsernameKey: []byte(b.username),
+			cql.PasswordKey: []byte(password),
+		},
+	}
+
+	err = driver.Create(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	b.created = true
+	return desired, nil
+}
+
+// Created returns true if the last reconcile created the secret
+func (b *CredentialsSecret) Created() bool {
+	return b.created
+}
@@ -40,7 +40,7 @@ func (b *RepairCronJob) Reconcile(driver opsdk.Client) (sdk.Object, error) {
 
 	existing := &batchv1beta1.CronJob{
 		TypeMeta:   GetCronJobTypeMeta(),
-		ObjectMeta: b.desired.ObjectMeta,
+		ObjectMeta: metav1.ObjectMeta{Name: b.desired.Name, Namespace: b.desired.Namespace},
 	}
 	err = driver.Get(existing)
 	if err != nil {
@@ -52,6 +52,10 @@ func (b *RepairCronJob) Reconcile(driver opsdk.Client) (sdk.Object, error) {
 		// we put our code here to reconcile the two and return
 		// the reconciled object
 		b.desired.ResourceVersion = existing.ResourceVersion
+		preserveForeignMetadata(b.desired, existing)
+		if !drifted(b.desired, existing) {
+			return b.desired, nil
+		}
 		err = driver.Update(b.desired)
 		return b.desired, err
 	}
@@ -75,6 +79,10 @@ func (b *RepairCronJob) configureDesired() {
 			SuccessfulJobsHistoryLimit: &successfulJobsHistoryLimit,
 			FailedJobsHistoryLimit:     &failedJobsHistoryLimit,
 			JobTemplate: batchv1beta1.JobTemplateSpec{
+				// the jobs are labeled with the cluster so the operator can find the last successful repair
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: b.buildLabels(),
+				},
 				Spec: v1.JobSpec{
 					BackoffLimit: &backoffLimit,
 					Template:     b.buildCronJobPodTemplateSpec(),
@@ -82,6 +90,9 @@ func (b *RepairCronJob) configureDesired() {
 			},
 		},
 	}
+	// the repairs of a paused cluster are suspended
+	suspend := b.cluster.Spec.Paused
+	b.desired.Spec.Suspend = &suspend
 	b.setOwner(asOwner(b.cluster))
 }
 
@@ -109,7 +120,7 @@ func (b *RepairCronJob) buildCronJobPodTemplateSpec() corev1.PodTemplateSpec {
 				{
 					Name:  b.buildCronJobName(),
 					Image: imageName,
-					Env:   b.buildCronJobEnvVars(),
+					Env:   buildRepairEnvVars(b.cluster),
 				},
 			},
 			RestartPolicy: corev1.RestartPolicyNever,
@@ -117,11 +128,12 @@ func (b *RepairCronJob) buildCronJobPodTemplateSpec() corev1.PodTemplateSpec {
 	}
 }
 
-func (b *RepairCronJob) buildCronJobEnvVars() []corev1.EnvVar {
+// buildRepairEnvVars builds the environment the repair image uses to find the cluster
+func buildRepairEnvVars(cc *v1alpha1.CassandraCluster) []corev1.EnvVar {
 	envs := []corev1.EnvVar{
 		{
 			Name:  cassandraClusterEnvVar,
-			Value: b.cluster.GetName(),
+			Value: cc.GetName(),
 		},
 		{
 			Name: kubeNamespaceEnvVar,
@@ -133,7 +145,7 @@ func (b *RepairCronJob) buildCronJobEnvVars() []corev1.EnvVar {
 		},
 	}
 
-	if appName, ok := b.cluster.ObjectMeta.Labels["app"]; ok {
+	if appName, ok := cc.ObjectMeta.Labels["app"]; ok {
 		envs = append(envs, corev1.EnvVar{
 			Name:  appNameEnvVar,
 			Value: appName,
@@ -17,9 +17,10 @@ import (
 )
 
 var (
-	three   int32 = 3
-	zero    int32 = 0
-	trueVar       = true
+	three    int32 = 3
+	zero     int32 = 0
+	trueVar        = true
+	falseVar       = false
 )
 
 func TestRepairCronJob_Reconcile(t *testing.T) {
@@ -75,9 +76,16 @@ func TestRepairCronJob_Reconcile(t *testing.T) {
 				Spec: batchv1beta1.CronJobSpec{
 					Schedule:                   "",
 					ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+					Suspend:                    &falseVar,
 					SuccessfulJobsHistoryLimit: &three,
 					FailedJobsHistoryLimit:     &three,
 					JobTemplate: batchv1beta1.JobTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{
+								"cluster": "test-cluster-1",
+								"app":     "test-app",
+							},
+						},
 						Spec: batchv1.JobSpec{
 							BackoffLimit: &zero,
 							Template: corev1.PodTemplateSpec{
@@ -178,9 +186,16 @@ func TestRepairCronJob_Reconcile(t *testing.T) {
 				Spec: batchv1beta1.CronJobSpec{
 					Schedule:                   "",
 					ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+					Suspend:                    &falseVar,
 					SuccessfulJobsHistoryLimit: &three,
 					FailedJobsHistoryLimit:     &three,
 					JobTemplate: batchv1beta1.JobTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{
+								"cluster": "test-cluster-1",
+								"app":     "test-app",
+							},
+						},
 						Spec: batchv1.JobSpec{
 							BackoffLimit: &zero,
 							Template: corev1.PodTemplateSpec{
@@ -274,3 +289,35 @@ func TestRepairCronJob_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestRepairCronJob_ReconcilePaused(t *testing.T) {
+	cluster := &v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-1",
+			Namespace: "test-namespace",
+		},
+		Spec: v1alpha1.ClusterSpec{
+			Repair: &v1alpha1.RepairPolicy{Schedule: "@daily"},
+			Paused: true,
+		},
+	}
+	client := k8s.NewFakeClient()
+
+	got, err := resource.NewRepairCronJob(cluster).Reconcile(client)
+	if err != nil {
+		t.Fatalf("RepairCronJob.Reconcile() error = %v", err)
+	}
+	cronJob := got.(*batchv1beta1.CronJob)
+	if cronJob.Spec.Suspend == nil || !*cronJob.Spec.Suspend {
+		t.Errorf("RepairCronJob.Reconcile() suspend = %v, want true", cronJob.Spec.Suspend)
+	}
+
+	cluster.Spec.Paused = false
+	got, err = resource.NewRepairCronJob(cluster).Reconcile(client)
+	if err != nil {
+		t.Fatalf("RepairCronJob.Reconcile() error = %v", err)
+	}
+	if suspend := got.(*batchv1beta1.CronJob).Spec.Suspend; suspend != nil && *suspend {
+		t.Errorf("RepairCronJob.Reconcile() suspend = %v, want false", *suspend)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/operator-framework/operator-sdk/pkg/sdk"
 	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
 	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/sirupsen/logrus"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -25,22 +27,44 @@ func NewPodDisruptionBudget(cc *v1alpha1.CassandraCluster) *PodDisruptionBudget
 	}
 }
 
-// Reconcile the PodDisruptionBudget's actual state with desired
+// Reconcile the PodDisruptionBudget's actual state with desired, the PodDisruptionBudget is deleted and nothing is
+// returned when it is not enabled
 func (b *PodDisruptionBudget) Reconcile(driver opsdk.Client) (sdk.Object, error) {
-	b.buildDesired()
-
 	existing := &policyv1beta1.PodDisruptionBudget{
 		TypeMeta:   GetPodDisruptionBudgetTypeMeta(),
-		ObjectMeta: b.desired.ObjectMeta,
+		ObjectMeta: b.objectMeta(),
 	}
 	err := driver.Get(existing)
 	if err != nil {
 		return nil, errors.New("could not get existing")
 	}
 
+	// the policy is only validated when the budget is enabled, a disabled budget is removed whatever it holds
+	if !b.cluster.Spec.EnablePodDisruptionBudget {
+		if existing.GetResourceVersion() == "" {
+			return nil, nil
+		}
+		logrus.Infof("Deleting the PodDisruptionBudget of cluster %s", b.cluster.GetName())
+		return nil, driver.Delete(existing)
+	}
+
+	err = b.buildDesired()
+	if err != nil {
+		return nil, err
+	}
+
 	if existing.GetResourceVersion() != "" {
-		b.desired.SetResourceVersion(existing.GetResourceVersion())
-		err = driver.Update(b.desired)
+		preserveForeignMetadata(b.desired, existing)
+		if !drifted(b.desired, existing) {
+			b.desired.SetResourceVersion(existing.GetResourceVersion())
+			return b.desired, nil
+		}
+		if equality.Semantic.DeepEqual(b.desired.Spec, existing.Spec) {
+			b.desired.SetResourceVersion(existing.GetResourceVersion())
+			err = driver.Update(b.desired)
+		} else {
+			err = b.replace(driver, existing)
+		}
 	} else {
 		err = driver.Create(b.desired)
 	}
@@ -51,23 +75,81 @@ func (b *PodDisruptionBudget) Reconcile(driver opsdk.Client) (sdk.Object, error)
 	return b.desired, nil
 }
 
-func (b *PodDisruptionBudget) buildDesired() {
-	two := intstr.Parse("2")
+// replace deletes the existing PodDisruptionBudget and creates the desired one, the api server rejects updates of
+// the spec of a PodDisruptionBudget before kubernetes 1.15
+func (b *PodDisruptionBudget) replace(driver opsdk.Client, existing *policyv1beta1.PodDisruptionBudget) error {
+	logrus.Infof("Replacing the PodDisruptionBudget of cluster %s, its budget or selector changed", b.cluster.GetName())
+	err := driver.Delete(existing)
+	if err != nil {
+		return err
+	}
+	return driver.Create(b.desired)
+}
 
+func (b *PodDisruptionBudget) buildDesired() error {
 	b.desired = &policyv1beta1.PodDisruptionBudget{
-		TypeMeta: GetPodDisruptionBudgetTypeMeta(),
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-cassandra", b.cluster.GetName()),
-			Namespace: b.cluster.GetNamespace(),
-		},
+		TypeMeta:   GetPodDisruptionBudgetTypeMeta(),
+		ObjectMeta: b.objectMeta(),
 		Spec: policyv1beta1.PodDisruptionBudgetSpec{
-			MinAvailable: &two,
-			Selector:     &metav1.LabelSelector{},
+			Selector: &metav1.LabelSelector{},
 		},
 	}
 
+	err := b.buildBudget()
+	if err != nil {
+		return err
+	}
 	b.buildSelector()
 	b.setOwner(asOwner(b.cluster))
+
+	return nil
+}
+
+func (b *PodDisruptionBudget) objectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      fmt.Sprintf("%s-cassandra", b.cluster.GetName()),
+		Namespace: b.cluster.GetNamespace(),
+	}
+}
+
+// buildBudget sets the budget of the policy, by default the nodes may be evicted as long as every token range
+// keeps a quorum of its replicas
+func (b *PodDisruptionBudget) buildBudget() error {
+	policy := b.cluster.Spec.DisruptionBudget
+	if policy != nil && policy.MinAvailable != nil && policy.MaxUnavailable != nil {
+		return errors.New("disruptionBudget can only set one of minAvailable and maxUnavailable")
+	}
+
+	switch {
+	case policy != nil && policy.MinAvailable != nil:
+		minAvailable := *policy.MinAvailable
+		b.desired.Spec.MinAvailable = &minAvailable
+	case policy != nil && policy.MaxUnavailable != nil:
+		maxUnavailable := *policy.MaxUnavailable
+		b.desired.Spec.MaxUnavailable = &maxUnavailable
+	default:
+		// the pods are not spread by rack, so any of them may hold replicas of the same range
+		unavailable := (replicationFactor(b.cluster) - 1) / 2
+		if unavailable < 1 {
+			unavailable = 1
+		}
+		maxUnavailable := intstr.FromInt(unavailable)
+		b.desired.Spec.MaxUnavailable = &maxUnavailable
+	}
+
+	return nil
+}
+
+// replicationFactor returns the replication of the datacenter of the cluster, the keyspace of the cluster is
+// replicated to up to 3 nodes when it is not set
+func replicationFactor(cc *v1alpha1.CassandraCluster) int {
+	if rf, ok := cc.Spec.Replication[cc.Spec.Datacenter]; ok {
+		return rf
+	}
+	if cc.Spec.Size < 3 {
+		return cc.Spec.Size
+	}
+	return 3
 }
 
 func (b *PodDisruptionBudget) buildSelector() {
@@ -7,6 +7,7 @@ import (
 	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
 	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"reflect"
@@ -16,7 +17,9 @@ import (
 )
 
 var (
-	twoObj = intstr.Parse("2")
+	oneObj     = intstr.FromInt(1)
+	twoObj     = intstr.Parse("2")
+	percentObj = intstr.FromString("50%")
 )
 
 func TestPodDisruptionBudget_Reconcile(t *testing.T) {
@@ -26,6 +29,7 @@ func TestPodDisruptionBudget_Reconcile(t *testing.T) {
 		mockGetError    error
 		mockUpdateError error
 		mockCreateError error
+		mockDeleteError error
 	}
 	tests := []struct {
 		name    string
@@ -66,7 +70,7 @@ func TestPodDisruptionBudget_Reconcile(t *testing.T) {
 					},
 				},
 				Spec: policyv1beta1.PodDisruptionBudgetSpec{
-					MinAvailable: &twoObj,
+					MaxUnavailable: &oneObj,
 					Selector: &metav1.LabelSelector{
 						MatchLabels: map[string]string{
 							"cluster": "test-cluster-1",
@@ -78,7 +82,7 @@ func TestPodDisruptionBudget_Reconcile(t *testing.T) {
 			},
 		},
 		{
-			name: "update",
+			name: "replace",
 			fields: fields{
 				cluster: &v1alpha1.CassandraCluster{
 					ObjectMeta: metav1.ObjectMeta{
@@ -126,9 +130,8 @@ func TestPodDisruptionBudget_Reconcile(t *testing.T) {
 					Kind:       "PodDisruptionBudget",
 				},
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            "test-cluster-1-cassandra",
-					Namespace:       "test-namespace",
-					ResourceVersion: "test-resource-version",
+					Name:      "test-cluster-1-cassandra",
+					Namespace: "test-namespace",
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							Name:       "test-cluster-1",
@@ -137,7 +140,7 @@ func TestPodDisruptionBudget_Reconcile(t *testing.T) {
 					},
 				},
 				Spec: policyv1beta1.PodDisruptionBudgetSpec{
-					MinAvailable: &twoObj,
+					MaxUnavailable: &oneObj,
 					Selector: &metav1.LabelSelector{
 						MatchLabels: map[string]string{
 							"cluster": "test-cluster-1",
@@ -148,6 +151,70 @@ func TestPodDisruptionBudget_Reconcile(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "min-available-percent",
+			fields: fields{
+				cluster: &v1alpha1.CassandraCluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-cluster-1",
+						Namespace: "test-namespace",
+					},
+					Spec: v1alpha1.ClusterSpec{
+						EnablePodDisruptionBudget: true,
+						DisruptionBudget: &v1alpha1.DisruptionBudgetPolicy{
+							MinAvailable: &percentObj,
+						},
+					},
+				},
+				actual: nil,
+			},
+			want: &policyv1beta1.PodDisruptionBudget{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "policy/v1beta1",
+					Kind:       "PodDisruptionBudget",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster-1-cassandra",
+					Namespace: "test-namespace",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Name:       "test-cluster-1",
+							Controller: &trueVar,
+						},
+					},
+				},
+				Spec: policyv1beta1.PodDisruptionBudgetSpec{
+					MinAvailable: &percentObj,
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"cluster": "test-cluster-1",
+							"state":   "serving",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "min-available-and-max-unavailable",
+			fields: fields{
+				cluster: &v1alpha1.CassandraCluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-cluster-1",
+						Namespace: "test-namespace",
+					},
+					Spec: v1alpha1.ClusterSpec{
+						EnablePodDisruptionBudget: true,
+						DisruptionBudget: &v1alpha1.DisruptionBudgetPolicy{
+							MinAvailable:   &twoObj,
+							MaxUnavailable: &oneObj,
+						},
+					},
+				},
+				actual: nil,
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "get-error",
 			fields: fields{
@@ -192,6 +259,47 @@ func TestPodDisruptionBudget_Reconcile(t *testing.T) {
 		},
 		{
 			name: "update-error",
+			fields: fields{
+				cluster: &v1alpha1.CassandraCluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-cluster-1",
+						Namespace: "test-namespace",
+						Labels: map[string]string{
+							"app": "test-app",
+						},
+					},
+					Spec: v1alpha1.ClusterSpec{
+						EnablePodDisruptionBudget: true,
+					},
+				},
+				actual: &policyv1beta1.PodDisruptionBudget{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: "policy/v1beta1",
+						Kind:       "PodDisruptionBudget",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "test-cluster-1-cassandra",
+						Namespace:       "test-namespace",
+						ResourceVersion: "test-resource-version",
+					},
+					Spec: policyv1beta1.PodDisruptionBudgetSpec{
+						MaxUnavailable: &oneObj,
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"cluster": "test-cluster-1",
+								"state":   "serving",
+								"app":     "test-app",
+							},
+						},
+					},
+				},
+				mockUpdateError: errors.New("update error"),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "replace-error",
 			fields: fields{
 				cluster: &v1alpha1.CassandraCluster{
 					ObjectMeta: metav1.ObjectMeta{
@@ -232,7 +340,7 @@ func TestPodDisruptionBudget_Reconcile(t *testing.T) {
 						},
 					},
 				},
-				mockUpdateError: errors.New("update error"),
+				mockDeleteError: errors.New("delete error"),
 			},
 			want:    nil,
 			wantErr: true,
@@ -265,6 +373,12 @@ func TestPodDisruptionBudget_Reconcile(t *testing.T) {
 					}
 					return nil
 				},
+				DeleteCallback: func(object sdk.Object, opts ...sdk.DeleteOption) error {
+					if tt.fields.mockDeleteError != nil {
+						return tt.fields.mockDeleteError
+					}
+					return nil
+				},
 			}
 			b := resource.NewPodDisruptionBudget(tt.fields.cluster)
 			got, err := b.Reconcile(mockKubeClient)
@@ -278,3 +392,137 @@ func TestPodDisruptionBudget_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestPodDisruptionBudget_ReconcileDefaultBudget(t *testing.T) {
+	tests := []struct {
+		name           string
+		size           int
+		replication    map[string]int
+		maxUnavailable int
+	}{
+		{name: "single-node", size: 1, maxUnavailable: 1},
+		{name: "two-nodes", size: 2, maxUnavailable: 1},
+		{name: "twelve-nodes", size: 12, maxUnavailable: 1},
+		{name: "replication-of-datacenter", size: 12, replication: map[string]int{"dc1": 5, "dc2": 3}, maxUnavailable: 2},
+		{name: "replication-of-other-datacenter", size: 12, replication: map[string]int{"dc2": 5}, maxUnavailable: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := &v1alpha1.CassandraCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1", Namespace: "test-namespace"},
+				Spec: v1alpha1.ClusterSpec{
+					Size:                      tt.size,
+					Datacenter:                "dc1",
+					Replication:               tt.replication,
+					EnablePodDisruptionBudget: true,
+				},
+			}
+
+			got, err := resource.NewPodDisruptionBudget(cc).Reconcile(k8s.NewFakeClient())
+
+			assert.NoError(t, err)
+			maxUnavailable := intstr.FromInt(tt.maxUnavailable)
+			assert.Equal(t, &maxUnavailable, got.(*policyv1beta1.PodDisruptionBudget).Spec.MaxUnavailable)
+		})
+	}
+}
+
+func TestPodDisruptionBudget_ReconcileDisabled(t *testing.T) {
+	cc := &v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1", Namespace: "test-namespace"},
+		Spec:       v1alpha1.ClusterSpec{Size: 3, EnablePodDisruptionBudget: true},
+	}
+	client := k8s.NewFakeClient()
+	_, err := resource.NewPodDisruptionBudget(cc).Reconcile(client)
+	assert.NoError(t, err)
+
+	cc.Spec.EnablePodDisruptionBudget = false
+	got, err := resource.NewPodDisruptionBudget(cc).Reconcile(client)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	existing := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta:   resource.GetPodDisruptionBudgetTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1-cassandra", Namespace: "test-namespace"},
+	}
+	assert.NoError(t, client.Get(existing))
+	assert.Empty(t, existing.GetResourceVersion())
+
+	// nothing is left to delete
+	got, err = resource.NewPodDisruptionBudget(cc).Reconcile(client)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestPodDisruptionBudget_ReconcileDisabledWithInvalidPolicy(t *testing.T) {
+	cc := &v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1", Namespace: "test-namespace"},
+		Spec:       v1alpha1.ClusterSpec{Size: 3, EnablePodDisruptionBudget: true},
+	}
+	client := k8s.NewFakeClient()
+	_, err := resource.NewPodDisruptionBudget(cc).Reconcile(client)
+	assert.NoError(t, err)
+
+	// the invalid policy does not keep the budget from being removed
+	cc.Spec.EnablePodDisruptionBudget = false
+	cc.Spec.DisruptionBudget = &v1alpha1.DisruptionBudgetPolicy{MinAvailable: &oneObj, MaxUnavailable: &oneObj}
+	got, err := resource.NewPodDisruptionBudget(cc).Reconcile(client)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	existing := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta:   resource.GetPodDisruptionBudgetTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1-cassandra", Namespace: "test-namespace"},
+	}
+	assert.NoError(t, client.Get(existing))
+	assert.Empty(t, existing.GetResourceVersion())
+
+	cc.Spec.EnablePodDisruptionBudget = true
+	_, err = resource.NewPodDisruptionBudget(cc).Reconcile(client)
+	assert.Error(t, err)
+}
+
+func TestPodDisruptionBudget_ReconcileReplacesBudget(t *testing.T) {
+	cc := &v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1", Namespace: "test-namespace", UID: "test-uid"},
+		Spec:       v1alpha1.ClusterSpec{Size: 3, EnablePodDisruptionBudget: true},
+	}
+	// a budget created before the budget was computed
+	minAvailable := intstr.FromInt(2)
+	fake := k8s.NewFakeClient(&policyv1beta1.PodDisruptionBudget{
+		TypeMeta:   resource.GetPodDisruptionBudgetTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1-cassandra", Namespace: "test-namespace"},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"cluster": "test-cluster-1", "state": "serving"}},
+		},
+	})
+	// the api server rejects any update of the spec
+	client := &k8s.MockClient{
+		GetCallback: func(into sdk.Object, opts ...sdk.GetOption) error {
+			return fake.Get(into, opts...)
+		},
+		CreateCallback: fake.Create,
+		UpdateCallback: func(object sdk.Object) error {
+			return errors.New("updates to poddisruptionbudget spec are forbidden")
+		},
+		DeleteCallback: func(object sdk.Object, opts ...sdk.DeleteOption) error {
+			return fake.Delete(object, opts...)
+		},
+	}
+
+	_, err := resource.NewPodDisruptionBudget(cc).Reconcile(client)
+
+	assert.NoError(t, err)
+	existing := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta:   resource.GetPodDisruptionBudgetTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1-cassandra", Namespace: "test-namespace"},
+	}
+	assert.NoError(t, fake.Get(existing))
+	assert.Nil(t, existing.Spec.MinAvailable)
+	assert.Equal(t, &oneObj, existing.Spec.MaxUnavailable)
+
+	// the replaced budget is left alone
+	_, err = resource.NewPodDisruptionBudget(cc).Reconcile(client)
+	assert.NoError(t, err)
+}
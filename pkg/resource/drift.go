@@ -0,0 +1,132 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// drifted compares the labels, annotations, owners and spec of the desired object to the existing one and logs the
+// fields that drifted. Only the fields the desired object serializes are compared, the fields it omits are defaulted
+// by the api server or set by others and never drift.
+func drifted(desired, existing sdk.Object) bool {
+	diffs := objectDrift(desired, existing)
+	if len(diffs) == 0 {
+		return false
+	}
+
+	accessor, _ := meta.Accessor(desired)
+	logrus.WithFields(logrus.Fields{
+		"kind":      desired.GetObjectKind().GroupVersionKind().Kind,
+		"namespace": accessor.GetNamespace(),
+		"name":      accessor.GetName(),
+		"diff":      diffs,
+	}).Infof("Updating drifted %s %s", desired.GetObjectKind().GroupVersionKind().Kind, accessor.GetName())
+
+	return true
+}
+
+// objectDrift returns the paths of the fields of the desired object that the existing one does not match. The
+// desired labels and annotations already hold the foreign ones, so the keys only the existing object has were removed.
+func objectDrift(desired, existing sdk.Object) []string {
+	desiredFields, err := serializedFields(desired)
+	if err != nil {
+		return []string{fmt.Sprintf("could not serialize the desired object: %v", err)}
+	}
+	existingFields, err := serializedFields(existing)
+	if err != nil {
+		return []string{fmt.Sprintf("could not serialize the existing object: %v", err)}
+	}
+
+	var diffs []string
+	desiredMeta, _ := desiredFields["metadata"].(map[string]interface{})
+	existingMeta, _ := existingFields["metadata"].(map[string]interface{})
+	for _, field := range []string{"labels", "annotations", "ownerReferences"} {
+		path := "metadata." + field
+		diffs = diffValue(path, desiredMeta[field], existingMeta[field], diffs)
+		diffs = removedKeys(path, desiredMeta[field], existingMeta[field], diffs)
+	}
+	diffs = diffValue("spec", desiredFields["spec"], existingFields["spec"], diffs)
+
+	sort.Strings(diffs)
+	return diffs
+}
+
+// serializedFields returns the object as the api server receives it, the fields it omits are left to the api server
+func serializedFields(object sdk.Object) (map[string]interface{}, error) {
+	out, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	err = json.Unmarshal(out, &fields)
+	return fields, err
+}
+
+// diffValue appends the paths of the fields the desired value sets to something else than the existing value. An
+// explicit zero value is compared like any other. Maps only compare the keys of the desired map, lists of a
+// different length differ as a whole.
+func diffValue(path string, desired, existing interface{}, diffs []string) []string {
+	switch desiredValue := desired.(type) {
+	case nil:
+		return diffs
+	case map[string]interface{}:
+		existingValue, ok := existing.(map[string]interface{})
+		if !ok {
+			return append(diffs, describeDiff(path, desired, existing))
+		}
+		for key, value := range desiredValue {
+			diffs = diffValue(path+"."+key, value, existingValue[key], diffs)
+		}
+		return diffs
+	case []interface{}:
+		existingValue, ok := existing.([]interface{})
+		if !ok || len(existingValue) != len(desiredValue) {
+			return append(diffs, describeDiff(path, desired, existing))
+		}
+		for i := range desiredValue {
+			diffs = diffValue(fmt.Sprintf("%s[%d]", path, i), desiredValue[i], existingValue[i], diffs)
+		}
+		return diffs
+	default:
+		if !reflect.DeepEqual(desired, existing) {
+			return append(diffs, describeDiff(path, desired, existing))
+		}
+		return diffs
+	}
+}
+
+// removedKeys appends the paths of the keys of the existing map that the desired map does not have
+func removedKeys(path string, desired, existing interface{}, diffs []string) []string {
+	existingValue, ok := existing.(map[string]interface{})
+	if !ok {
+		return diffs
+	}
+	desiredValue, _ := desired.(map[string]interface{})
+	for key, value := range existingValue {
+		if _, ok := desiredValue[key]; !ok {
+			diffs = append(diffs, describeDiff(path+"."+key, nil, value))
+		}
+	}
+	return diffs
+}
+
+func describeDiff(path string, desired, existing interface{}) string {
+	return fmt.Sprintf("%s: %s -> %s", path, formatValue(existing), formatValue(desired))
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return "<unset>"
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(out)
+}
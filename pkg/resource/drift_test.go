@@ -0,0 +1,149 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/stretchr/testify/assert"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func getDriftCluster() *v1alpha1.CassandraCluster {
+	return &v1alpha1.CassandraCluster{
+		TypeMeta: resource.GetCassandraClusterTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-1",
+			Namespace: "test-namespace",
+			UID:       "test-uid",
+			Labels:    map[string]string{"app": "test-app"},
+		},
+		Spec: v1alpha1.ClusterSpec{
+			Size:   3,
+			Node:   &v1alpha1.NodePolicy{Image: "cassandra-image-test:1233", Resources: &corev1.ResourceRequirements{}},
+			Repair: &v1alpha1.RepairPolicy{Schedule: "0 0 * * *", Image: "repair:latest"},
+		},
+	}
+}
+
+func TestService_ReconcileWithoutDrift(t *testing.T) {
+	cc := getDriftCluster()
+	client := k8s.NewFakeClient()
+	service := resource.NewService(cc, resource.WithServiceType(resource.ServiceTypePublicPod), resource.WithPodNumber(0))
+	created, err := service.Reconcile(client)
+	assert.NoError(t, err)
+
+	// the api server allocates the node port and defaults the session affinity, protocol and target port
+	stored := created.(*corev1.Service).DeepCopy()
+	stored.Spec.ClusterIP = "10.0.0.1"
+	stored.Spec.Ports[0].NodePort = 31000
+	stored.Spec.Ports[0].Protocol = corev1.ProtocolTCP
+	stored.Spec.Ports[0].TargetPort = intstr.FromInt(int(stored.Spec.Ports[0].Port))
+	stored.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	assert.NoError(t, client.Update(stored))
+
+	got, err := service.Reconcile(client)
+	assert.NoError(t, err)
+	assert.Equal(t, stored.ResourceVersion, got.(*corev1.Service).ResourceVersion)
+
+	cc.Labels["app"] = "other-app"
+	got, err = resource.NewService(cc, resource.WithServiceType(resource.ServiceTypePublicPod), resource.WithPodNumber(0)).Reconcile(client)
+	assert.NoError(t, err)
+	assert.NotEqual(t, stored.ResourceVersion, got.(*corev1.Service).ResourceVersion)
+	assert.Equal(t, int32(31000), got.(*corev1.Service).Spec.Ports[0].NodePort)
+}
+
+func TestRepairCronJob_ReconcileWithoutDrift(t *testing.T) {
+	cc := getDriftCluster()
+	client := k8s.NewFakeClient()
+	created, err := resource.NewRepairCronJob(cc).Reconcile(client)
+	assert.NoError(t, err)
+	version := created.(*batchv1beta1.CronJob).ResourceVersion
+
+	got, err := resource.NewRepairCronJob(cc).Reconcile(client)
+	assert.NoError(t, err)
+	assert.Equal(t, version, got.(*batchv1beta1.CronJob).ResourceVersion)
+
+	cc.Spec.Paused = true
+	got, err = resource.NewRepairCronJob(cc).Reconcile(client)
+	assert.NoError(t, err)
+	assert.NotEqual(t, version, got.(*batchv1beta1.CronJob).ResourceVersion)
+	version = got.(*batchv1beta1.CronJob).ResourceVersion
+
+	// resuming sets suspend back to false
+	cc.Spec.Paused = false
+	got, err = resource.NewRepairCronJob(cc).Reconcile(client)
+	assert.NoError(t, err)
+	assert.NotEqual(t, version, got.(*batchv1beta1.CronJob).ResourceVersion)
+}
+
+func TestStatefulSet_ReconcileWithoutDrift(t *testing.T) {
+	cc := getDriftCluster()
+	client := k8s.NewFakeClient()
+	newStatefulSet := func() *resource.StatefulSet {
+		return resource.NewStatefulSet(cc, resource.WithServiceName("test-service"), resource.WithServiceAccountName("test-sa"))
+	}
+	_, err := newStatefulSet().Reconcile(client)
+	assert.NoError(t, err)
+	reconciled, err := newStatefulSet().Reconcile(client)
+	assert.NoError(t, err)
+	version := reconciled.(metav1.Object).GetResourceVersion()
+
+	got, err := newStatefulSet().Reconcile(client)
+	assert.NoError(t, err)
+	assert.Equal(t, version, got.(metav1.Object).GetResourceVersion())
+
+	cc.Spec.Node.Image = "cassandra-image-test:1234"
+	got, err = newStatefulSet().Reconcile(client)
+	assert.NoError(t, err)
+	assert.NotEqual(t, version, got.(metav1.Object).GetResourceVersion())
+}
+
+func TestPodDisruptionBudget_ReconcileZeroBudget(t *testing.T) {
+	cc := getDriftCluster()
+	cc.Spec.EnablePodDisruptionBudget = true
+	one := intstr.FromInt(1)
+	cc.Spec.DisruptionBudget = &v1alpha1.DisruptionBudgetPolicy{MaxUnavailable: &one}
+	client := k8s.NewFakeClient()
+	_, err := resource.NewPodDisruptionBudget(cc).Reconcile(client)
+	assert.NoError(t, err)
+
+	// no node may be evicted while the cluster is being worked on
+	zero := intstr.FromInt(0)
+	cc.Spec.DisruptionBudget.MaxUnavailable = &zero
+	_, err = resource.NewPodDisruptionBudget(cc).Reconcile(client)
+	assert.NoError(t, err)
+
+	existing := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta:   resource.GetPodDisruptionBudgetTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1-cassandra", Namespace: "test-namespace"},
+	}
+	assert.NoError(t, client.Get(existing))
+	assert.Equal(t, &zero, existing.Spec.MaxUnavailable)
+}
+
+func TestService_ReconcileEmptiedAnnotation(t *testing.T) {
+	cc := getDriftCluster()
+	cc.Spec.ServiceTemplate = &v1alpha1.ServiceTemplate{Annotations: map[string]string{"example.com/owner": "storage"}}
+	client := k8s.NewFakeClient()
+	newService := func() *resource.Service {
+		return resource.NewService(cc, resource.WithServiceType(resource.ServiceTypePublicLB))
+	}
+	created, err := newService().Reconcile(client)
+	assert.NoError(t, err)
+
+	cc.Spec.ServiceTemplate.Annotations["example.com/owner"] = ""
+	_, err = newService().Reconcile(client)
+	assert.NoError(t, err)
+
+	service := &corev1.Service{TypeMeta: resource.GetServiceTypeMeta(), ObjectMeta: metav1.ObjectMeta{Name: created.(*corev1.Service).Name, Namespace: "test-namespace"}}
+	assert.NoError(t, client.Get(service))
+	value, ok := service.Annotations["example.com/owner"]
+	assert.True(t, ok)
+	assert.Empty(t, value)
+}
@@ -0,0 +1,142 @@
+package resource
+
+import (
+	"fmt"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// BroadcastAddressAnnotation holds the external ip of the public service of a node, the node reads it through
+	// a downward api volume
+	BroadcastAddressAnnotation = "database.panth.io/broadcast-address"
+
+	broadcastAddressVolume    = "broadcast-address"
+	broadcastAddressMountPath = "/broadcast-address"
+	broadcastAddressFile      = "address"
+
+	publicPodServiceNameTemplate = "%s-cassandra-public-%d"
+)
+
+// ClientExposure returns how the public service of the cluster exposes it, a load balancer when the spec does not
+// say otherwise
+func ClientExposure(cc *v1alpha1.CassandraCluster) *v1alpha1.ServiceExposure {
+	var exposure *v1alpha1.ServiceExposure
+	if cc.Spec.Exposure != nil {
+		exposure = cc.Spec.Exposure.Client
+	}
+	return withDefaultType(exposure, v1alpha1.ExposureLoadBalancer)
+}
+
+// InternodeExposure returns how the public services of the nodes expose them, load balancers when public pod
+// services are enabled and the spec does not say otherwise
+func InternodeExposure(cc *v1alpha1.CassandraCluster) *v1alpha1.ServiceExposure {
+	var exposure *v1alpha1.ServiceExposure
+	if cc.Spec.Exposure != nil {
+		exposure = cc.Spec.Exposure.Internode
+	}
+	if exposure == nil && !cc.Spec.EnablePublicPodServices {
+		return &v1alpha1.ServiceExposure{Type: v1alpha1.ExposureNone}
+	}
+	return withDefaultType(exposure, v1alpha1.ExposureLoadBalancer)
+}
+
+func withDefaultType(exposure *v1alpha1.ServiceExposure, defaultType v1alpha1.ExposureType) *v1alpha1.ServiceExposure {
+	if exposure == nil {
+		return &v1alpha1.ServiceExposure{Type: defaultType}
+	}
+	exposure = exposure.DeepCopy()
+	if exposure.Type == "" {
+		exposure.Type = defaultType
+	}
+	return exposure
+}
+
+// broadcastAddressPublished returns true if the external ips of the public services of the nodes are published on
+// their pods
+func broadcastAddressPublished(cc *v1alpha1.CassandraCluster) bool {
+	return InternodeExposure(cc).Type == v1alpha1.ExposureLoadBalancer
+}
+
+// BroadcastAddresses publishes the external ip of the public service of every node on its pod, so the node can
+// broadcast an address the other datacenters reach it at
+type BroadcastAddresses struct {
+	cluster *v1alpha1.CassandraCluster
+}
+
+// NewBroadcastAddresses creates a new BroadcastAddresses
+func NewBroadcastAddresses(cc *v1alpha1.CassandraCluster) *BroadcastAddresses {
+	return &BroadcastAddresses{
+		cluster: cc,
+	}
+}
+
+// Reconcile annotates the pods whose public service got an external ip with it, the updated pods are returned.
+// Nothing is done unless the nodes are exposed through load balancers.
+func (b *BroadcastAddresses) Reconcile(driver opsdk.Client) ([]sdk.Object, error) {
+	if !broadcastAddressPublished(b.cluster) {
+		return nil, nil
+	}
+
+	var updated []sdk.Object
+	for i := 0; i < b.cluster.Spec.Size; i++ {
+		service := &corev1.Service{
+			TypeMeta: GetServiceTypeMeta(),
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf(publicPodServiceNameTemplate, b.cluster.GetName(), i),
+				Namespace: b.cluster.GetNamespace(),
+			},
+		}
+		err := driver.Get(service)
+		if err != nil {
+			return nil, err
+		}
+		address := externalIP(service)
+		if address == "" {
+			continue
+		}
+
+		pod := &corev1.Pod{
+			TypeMeta: GetPodTypeMeta(),
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-cassandra-%d", b.cluster.GetName(), i),
+				Namespace: b.cluster.GetNamespace(),
+			},
+		}
+		err = driver.Get(pod)
+		if err != nil {
+			return nil, err
+		}
+		if pod.ResourceVersion == "" || pod.DeletionTimestamp != nil || pod.Annotations[BroadcastAddressAnnotation] == address {
+			continue
+		}
+
+		logrus.Infof("Setting the broadcast address of pod %s/%s to %s", pod.GetNamespace(), pod.GetName(), address)
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[BroadcastAddressAnnotation] = address
+		err = driver.Update(pod)
+		if err != nil {
+			return nil, err
+		}
+		updated = append(updated, pod)
+	}
+
+	return updated, nil
+}
+
+// externalIP returns the first ip the load balancer of the service was assigned
+func externalIP(service *corev1.Service) string {
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestService_ReconcileExposure(t *testing.T) {
+	tests := []struct {
+		name         string
+		exposure     *v1alpha1.ExposurePolicy
+		serviceType  resource.ClusterServiceType
+		wantType     corev1.ServiceType
+		wantPorts    []corev1.ServicePort
+		wantRanges   []string
+		wantInternal string
+	}{
+		{
+			name:        "client-default",
+			serviceType: resource.ServiceTypePublicLB,
+			wantType:    corev1.ServiceTypeLoadBalancer,
+			wantPorts:   []corev1.ServicePort{{Name: "cql", Port: 9042}, {Name: "thrift", Port: 9160}},
+		},
+		{
+			name: "client-node-port-without-thrift",
+			exposure: &v1alpha1.ExposurePolicy{
+				Client: &v1alpha1.ServiceExposure{
+					Type:         v1alpha1.ExposureNodePort,
+					Ports:        []corev1.ServicePort{{Name: "cql", Port: 9042, NodePort: 30042}},
+					SourceRanges: []string{"10.0.0.0/8"},
+				},
+			},
+			serviceType: resource.ServiceTypePublicLB,
+			wantType:    corev1.ServiceTypeNodePort,
+			wantPorts:   []corev1.ServicePort{{Name: "cql", Port: 9042, NodePort: 30042}},
+		},
+		{
+			name: "internode-load-balancer",
+			exposure: &v1alpha1.ExposurePolicy{
+				Internode: &v1alpha1.ServiceExposure{
+					SourceRanges: []string{"192.0.2.0/24"},
+					Annotations:  map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+				},
+			},
+			serviceType:  resource.ServiceTypePublicPod,
+			wantType:     corev1.ServiceTypeLoadBalancer,
+			wantPorts:    []corev1.ServicePort{{Name: "ssl-internode-cluster", Port: 7001}},
+			wantRanges:   []string{"192.0.2.0/24"},
+			wantInternal: "true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := getDriftCluster()
+			cc.Spec.Exposure = tt.exposure
+
+			got, err := resource.NewService(cc, resource.WithServiceType(tt.serviceType), resource.WithPodNumber(0)).Reconcile(k8s.NewFakeClient())
+
+			assert.NoError(t, err)
+			service := got.(*corev1.Service)
+			assert.Equal(t, tt.wantType, service.Spec.Type)
+			assert.Equal(t, tt.wantPorts, service.Spec.Ports)
+			assert.Equal(t, tt.wantRanges, service.Spec.LoadBalancerSourceRanges)
+			assert.Equal(t, tt.wantInternal, service.Annotations["service.beta.kubernetes.io/aws-load-balancer-internal"])
+		})
+	}
+}
+
+func TestService_ReconcileExposureChange(t *testing.T) {
+	cc := getDriftCluster()
+	cc.Spec.Exposure = &v1alpha1.ExposurePolicy{
+		Client: &v1alpha1.ServiceExposure{SourceRanges: []string{"10.0.0.0/8"}},
+	}
+	client := k8s.NewFakeClient()
+	newService := func() *resource.Service {
+		return resource.NewService(cc, resource.WithServiceType(resource.ServiceTypePublicLB))
+	}
+	created, err := newService().Reconcile(client)
+	assert.NoError(t, err)
+
+	stored := created.(*corev1.Service).DeepCopy()
+	stored.Spec.HealthCheckNodePort = 32000
+	assert.NoError(t, client.Update(stored))
+
+	// the source ranges are owned by the operator, the fields of the load balancer go with its type
+	cc.Spec.Exposure.Client = &v1alpha1.ServiceExposure{Type: v1alpha1.ExposureNodePort}
+	_, err = newService().Reconcile(client)
+	assert.NoError(t, err)
+	service := &corev1.Service{TypeMeta: resource.GetServiceTypeMeta(), ObjectMeta: metav1.ObjectMeta{Name: stored.Name, Namespace: stored.Namespace}}
+	assert.NoError(t, client.Get(service))
+	assert.Equal(t, corev1.ServiceTypeNodePort, service.Spec.Type)
+	assert.Empty(t, service.Spec.LoadBalancerSourceRanges)
+	assert.Zero(t, service.Spec.HealthCheckNodePort)
+}
+
+func TestBroadcastAddresses_Reconcile(t *testing.T) {
+	cc := getDriftCluster()
+	cc.Spec.Size = 2
+	cc.Spec.EnablePublicPodServices = true
+	client := k8s.NewFakeClient()
+	for i := 0; i < cc.Spec.Size; i++ {
+		_, err := resource.NewService(cc, resource.WithServiceType(resource.ServiceTypePublicPod), resource.WithPodNumber(i)).Reconcile(client)
+		assert.NoError(t, err)
+	}
+	pods := []*corev1.Pod{
+		{TypeMeta: resource.GetPodTypeMeta(), ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1-cassandra-0", Namespace: "test-namespace"}},
+		{TypeMeta: resource.GetPodTypeMeta(), ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1-cassandra-1", Namespace: "test-namespace"}},
+	}
+	for _, pod := range pods {
+		assert.NoError(t, client.Create(pod))
+	}
+
+	// only the load balancer of the first node got its ip
+	service := publicPodService(0)
+	assert.NoError(t, client.Get(service))
+	service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}
+	assert.NoError(t, client.Update(service))
+
+	updated, err := resource.NewBroadcastAddresses(cc).Reconcile(client)
+	assert.NoError(t, err)
+	assert.Len(t, updated, 1)
+	for _, pod := range pods {
+		assert.NoError(t, client.Get(pod))
+	}
+	assert.Equal(t, "203.0.113.10", pods[0].Annotations[resource.BroadcastAddressAnnotation])
+	assert.NotContains(t, pods[1].Annotations, resource.BroadcastAddressAnnotation)
+
+	updated, err = resource.NewBroadcastAddresses(cc).Reconcile(client)
+	assert.NoError(t, err)
+	assert.Empty(t, updated)
+
+	cc.Spec.Exposure = &v1alpha1.ExposurePolicy{Internode: &v1alpha1.ServiceExposure{Type: v1alpha1.ExposureNodePort}}
+	updated, err = resource.NewBroadcastAddresses(cc).Reconcile(client)
+	assert.NoError(t, err)
+	assert.Empty(t, updated)
+}
+
+func TestStatefulSet_ReconcileBroadcastAddress(t *testing.T) {
+	cc := getDriftCluster()
+	cc.Spec.Exposure = &v1alpha1.ExposurePolicy{Internode: &v1alpha1.ServiceExposure{}}
+
+	got, err := resource.NewStatefulSet(cc, resource.WithServiceName("test-service"), resource.WithServiceAccountName("test-sa")).Reconcile(k8s.NewFakeClient())
+
+	assert.NoError(t, err)
+	podSpec := got.(*appsv1.StatefulSet).Spec.Template.Spec
+	assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "CASSANDRA_BROADCAST_ADDRESS_FILE", Value: "/broadcast-address/address"})
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "broadcast-address", MountPath: "/broadcast-address"})
+	volume := podSpec.Volumes[len(podSpec.Volumes)-1]
+	if assert.NotNil(t, volume.DownwardAPI) {
+		assert.Equal(t, "metadata.annotations['database.panth.io/broadcast-address']", volume.DownwardAPI.Items[0].FieldRef.FieldPath)
+	}
+}
@@ -0,0 +1,125 @@
+package resource
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// TemplateLabelsAnnotation records the labels of a service that came from the service template of the cluster
+	TemplateLabelsAnnotation = "database.panth.io/template-labels"
+	// TemplateAnnotationsAnnotation records the annotations of a service that came from the service template or the
+	// exposure of the cluster
+	TemplateAnnotationsAnnotation = "database.panth.io/template-annotations"
+)
+
+// preserveForeignMetadata keeps the labels and annotations of the existing object that the operator does not set,
+// like the ones a cloud controller or a user added. The labels and annotations the service template set before are
+// removed once the template drops them.
+func preserveForeignMetadata(desired, existing metav1.Object) {
+	desired.SetLabels(mergeForeign(
+		desired.GetLabels(),
+		existing.GetLabels(),
+		recordedKeys(existing, TemplateLabelsAnnotation),
+	))
+	// the records are owned by the operator, they are set again when the template still has entries
+	droppedAnnotations := recordedKeys(existing, TemplateAnnotationsAnnotation)
+	droppedAnnotations[TemplateLabelsAnnotation] = true
+	droppedAnnotations[TemplateAnnotationsAnnotation] = true
+	desired.SetAnnotations(mergeForeign(desired.GetAnnotations(), existing.GetAnnotations(), droppedAnnotations))
+}
+
+// mergeForeign adds the existing entries to the desired ones, unless the desired entries set or dropped the key
+func mergeForeign(desired, existing map[string]string, dropped map[string]bool) map[string]string {
+	if len(existing) == 0 {
+		return desired
+	}
+	merged := make(map[string]string, len(desired)+len(existing))
+	for key, value := range existing {
+		if !dropped[key] {
+			merged[key] = value
+		}
+	}
+	return mergeMap(merged, desired)
+}
+
+// recordKeys records the keys of the entries on the object so they can be removed later
+func recordKeys(object metav1.Object, annotation string, entries map[string]string) {
+	if len(entries) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[annotation] = strings.Join(keys, ",")
+	object.SetAnnotations(annotations)
+}
+
+func recordedKeys(object metav1.Object, annotation string) map[string]bool {
+	keys := map[string]bool{}
+	value := object.GetAnnotations()[annotation]
+	if value == "" {
+		return keys
+	}
+	for _, key := range strings.Split(value, ",") {
+		keys[key] = true
+	}
+	return keys
+}
+
+// mergeServiceSpec enforces the fields of the spec the operator owns, the type, the ports, the selector and the
+// source ranges, and keeps the rest of the existing spec, like the health check node port or the load balancer ip.
+// The cluster ip and the node ports the api server allocated are kept as well.
+func mergeServiceSpec(desired, existing *corev1.Service) {
+	spec := existing.Spec.DeepCopy()
+	if spec.Type != desired.Spec.Type {
+		// the fields of load balancers are rejected by the other types
+		spec.HealthCheckNodePort = 0
+		spec.LoadBalancerIP = ""
+	}
+	spec.Type = desired.Spec.Type
+	spec.Ports = desired.Spec.Ports
+	spec.Selector = desired.Spec.Selector
+	spec.LoadBalancerSourceRanges = desired.Spec.LoadBalancerSourceRanges
+	desired.Spec = *spec
+	defaultPorts(desired)
+	preserveNodePorts(desired, existing)
+}
+
+// defaultPorts sets the protocol and target port of the ports the way the api server defaults them
+func defaultPorts(desired *corev1.Service) {
+	for i := range desired.Spec.Ports {
+		port := &desired.Spec.Ports[i]
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal == 0 {
+			port.TargetPort = intstr.FromInt(int(port.Port))
+		}
+	}
+}
+
+// preserveNodePorts keeps the node ports the api server allocated, an update without them allocates new ones
+func preserveNodePorts(desired, existing *corev1.Service) {
+	for i := range desired.Spec.Ports {
+		if desired.Spec.Ports[i].NodePort != 0 {
+			continue
+		}
+		for _, port := range existing.Spec.Ports {
+			if port.Name == desired.Spec.Ports[i].Name {
+				desired.Spec.Ports[i].NodePort = port.NodePort
+			}
+		}
+	}
+}
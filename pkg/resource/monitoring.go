@@ -0,0 +1,240 @@
+package resource
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	exporterConfigVolume = "jvm-agent-config"
+	exporterPortName     = "prometheus"
+
+	// the jmx-exporter javaagent is copied from the exporter image into this volume by an init container
+	jmxExporterVolume     = "jmx-exporter"
+	jmxExporterAgentPath  = "/jmx-exporter"
+	jmxExporterAgentJar   = "jmx_prometheus_javaagent.jar"
+	jmxExporterConfigPath = "/jmx-exporter-config"
+
+	prometheusScrapeAnnotation = "prometheus.io/scrape"
+	prometheusPortAnnotation   = "prometheus.io/port"
+)
+
+// exporterDefaults are the settings of an exporter that are not set in the MonitoringPolicy
+type exporterDefaults struct {
+	image     string
+	port      int32
+	configKey string
+	// configPath is where the configmap is mounted in the container that reads it
+	configPath string
+	resources  corev1.ResourceRequirements
+}
+
+var exporters = map[string]exporterDefaults{
+	// https://hub.docker.com/_/telegraf/
+	v1alpha1.ExporterTelegraf: {
+		image:      "telegraf:1.2",
+		port:       9126,
+		configKey:  "telegraf.conf",
+		configPath: "/telegraf-config",
+		resources:  exporterResources("1", "128Mi", "0.1", "64Mi"),
+	},
+	// https://github.com/criteo/cassandra_exporter
+	v1alpha1.ExporterCassandraExporter: {
+		image:      "criteord/cassandra_exporter:2.0.2",
+		port:       8080,
+		configKey:  "config.yml",
+		configPath: "/etc/cassandra_exporter",
+		resources:  exporterResources("1", "512Mi", "0.1", "256Mi"),
+	},
+	// https://github.com/prometheus/jmx_exporter
+	v1alpha1.ExporterJMX: {
+		port:       7070,
+		configKey:  "jmx-exporter.yaml",
+		configPath: jmxExporterConfigPath,
+	},
+}
+
+func exporterResources(cpuLimit, memoryLimit, cpuRequest, memoryRequest string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpuLimit),
+			corev1.ResourceMemory: resource.MustParse(memoryLimit),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpuRequest),
+			corev1.ResourceMemory: resource.MustParse(memoryRequest),
+		},
+	}
+}
+
+// Monitoring returns the monitoring policy of the cluster with the defaults of the exporter filled in or nil
+// when no exporter is enabled. The deprecated `jvmAgent: sidecar` is the telegraf exporter with its defaults.
+func Monitoring(cc *v1alpha1.CassandraCluster) *v1alpha1.MonitoringPolicy {
+	policy := cc.Spec.Monitoring.DeepCopy()
+	if policy == nil {
+		if cc.Spec.JvmAgent != "sidecar" {
+			return nil
+		}
+		policy = &v1alpha1.MonitoringPolicy{}
+	}
+
+	if policy.Exporter == "" {
+		policy.Exporter = v1alpha1.ExporterTelegraf
+	}
+
+	defaults := exporters[policy.Exporter]
+	if policy.Image == "" {
+		policy.Image = defaults.image
+	}
+	if policy.Port == 0 {
+		policy.Port = defaults.port
+	}
+	if policy.Resources == nil && policy.Exporter != v1alpha1.ExporterJMX {
+		policy.Resources = defaults.resources.DeepCopy()
+	}
+
+	return policy
+}
+
+// exporterConfigKey returns the key of the exporter configuration in the jvm agent configmap
+func exporterConfigKey(policy *v1alpha1.MonitoringPolicy) string {
+	return exporters[policy.Exporter].configKey
+}
+
+// buildExporterContainer appends the sidecar that serves the metrics, the jmx-exporter runs inside the cassandra
+// container instead and only gets an init container when the javaagent is copied from an image
+func (b *StatefulSet) buildExporterContainer(policy *v1alpha1.MonitoringPolicy) {
+	spec := &b.desired.Spec.Template.Spec
+
+	switch policy.Exporter {
+	case v1alpha1.ExporterJMX:
+		if policy.Image != "" {
+			spec.InitContainers = append(spec.InitContainers, b.buildJMXExporterInitContainer(policy))
+		}
+	case v1alpha1.ExporterTelegraf:
+		spec.Containers = append(spec.Containers, b.buildTelegrafContainer(policy))
+	case v1alpha1.ExporterCassandraExporter:
+		spec.Containers = append(spec.Containers, b.buildCassandraExporterContainer(policy))
+	}
+}
+
+func (b *StatefulSet) buildTelegrafContainer(policy *v1alpha1.MonitoringPolicy) corev1.Container {
+	configPath := exporters[v1alpha1.ExporterTelegraf].configPath
+
+	return corev1.Container{
+		Name:            "telegraf",
+		Image:           policy.Image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Args: []string{
+			"--config",
+			fmt.Sprintf("%s/%s", configPath, exporterConfigKey(policy)),
+		},
+		Ports: exporterPorts(policy),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      exporterConfigVolume,
+				MountPath: configPath,
+			},
+			// mount cassandra's persistent-disk into the telegraf pod so that telegraf can collect usage metrics
+			{
+				Name:      fmt.Sprintf("%s-cassandra-data", b.cluster.GetName()),
+				MountPath: b.getFileMountPath(),
+			},
+		},
+		Resources: *policy.Resources,
+	}
+}
+
+// buildCassandraExporterContainer runs the criteo exporter which reads the MBeans over the JMX port of the pod
+func (b *StatefulSet) buildCassandraExporterContainer(policy *v1alpha1.MonitoringPolicy) corev1.Container {
+	return corev1.Container{
+		Name:            "cassandra-exporter",
+		Image:           policy.Image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Ports:           exporterPorts(policy),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      exporterConfigVolume,
+				MountPath: exporters[v1alpha1.ExporterCassandraExporter].configPath,
+			},
+		},
+		Resources: *policy.Resources,
+	}
+}
+
+func (b *StatefulSet) buildJMXExporterInitContainer(policy *v1alpha1.MonitoringPolicy) corev1.Container {
+	container := corev1.Container{
+		Name:            "jmx-exporter",
+		Image:           policy.Image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command: []string{
+			"cp",
+			"/" + jmxExporterAgentJar,
+			jmxExporterAgentPath,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      jmxExporterVolume,
+				MountPath: jmxExporterAgentPath,
+			},
+		},
+	}
+	if policy.Resources != nil {
+		container.Resources = *policy.Resources
+	}
+
+	return container
+}
+
+// buildJMXExporterVolumeMounts mounts the configuration of the javaagent into the cassandra container and the
+// javaagent itself when it is copied from an image
+func (b *StatefulSet) buildJMXExporterVolumeMounts(policy *v1alpha1.MonitoringPolicy) []corev1.VolumeMount {
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      exporterConfigVolume,
+			MountPath: jmxExporterConfigPath,
+		},
+	}
+
+	if policy.Image != "" {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      jmxExporterVolume,
+			MountPath: jmxExporterAgentPath,
+		})
+	}
+
+	return mounts
+}
+
+// buildJMXExporterEnvVars loads the javaagent into the cassandra JVM, cassandra-env.sh appends JVM_EXTRA_OPTS
+func (b *StatefulSet) buildJMXExporterEnvVars(policy *v1alpha1.MonitoringPolicy) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name: "JVM_EXTRA_OPTS",
+			Value: fmt.Sprintf("-javaagent:%s/%s=%d:%s/%s",
+				jmxExporterAgentPath, jmxExporterAgentJar, policy.Port, jmxExporterConfigPath, exporterConfigKey(policy)),
+		},
+	}
+}
+
+// buildPrometheusAnnotations lets a prometheus that discovers pods scrape the exporter
+func (b *StatefulSet) buildPrometheusAnnotations(policy *v1alpha1.MonitoringPolicy) {
+	if b.desired.Spec.Template.ObjectMeta.Annotations == nil {
+		b.desired.Spec.Template.ObjectMeta.Annotations = map[string]string{}
+	}
+	b.desired.Spec.Template.ObjectMeta.Annotations[prometheusScrapeAnnotation] = "true"
+	b.desired.Spec.Template.ObjectMeta.Annotations[prometheusPortAnnotation] = strconv.Itoa(int(policy.Port))
+}
+
+func exporterPorts(policy *v1alpha1.MonitoringPolicy) []corev1.ContainerPort {
+	return []corev1.ContainerPort{
+		{
+			ContainerPort: policy.Port,
+			Name:          exporterPortName,
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// vaultProvider returns true if the cluster reads its secrets from vault
+func vaultProvider(cc *v1alpha1.CassandraCluster) bool {
+	return cc.Spec.Secrets != nil && cc.Spec.Secrets.Provider == v1alpha1.SecretsProviderVault
+}
+
+// VaultKeystore returns true if the keystore of the nodes is read from vault, managed certificates take precedence
+func VaultKeystore(cc *v1alpha1.CassandraCluster) bool {
+	return vaultProvider(cc) && cc.Spec.Secrets.KeystorePath != "" && !TLSManaged(cc)
+}
+
+// VaultCredentials returns true if the credentials of the operator are read from vault
+func VaultCredentials(cc *v1alpha1.CassandraCluster) bool {
+	return vaultProvider(cc) && cc.Spec.Secrets.CredentialsPath != ""
+}
+
+// VaultKeystoreSecretName returns the name of the secret the keystore read from vault is copied into
+func VaultKeystoreSecretName(cc *v1alpha1.CassandraCluster) string {
+	return fmt.Sprintf(vaultKeystoreSecretNameTemplate, cc.GetName())
+}
+
+// VaultCredentialsSecretName returns the name of the secret the credentials read from vault are copied into
+func VaultCredentialsSecretName(cc *v1alpha1.CassandraCluster) string {
+	return fmt.Sprintf(vaultCredentialsSecretNameTemplate, cc.GetName())
+}
+
+// VaultReferences returns the vault secrets that have to exist before the nodes of the cluster can start
+func VaultReferences(cc *v1alpha1.CassandraCluster) []Reference {
+	if !VaultKeystore(cc) {
+		return nil
+	}
+
+	return []Reference{
+		{
+			Name: cc.Spec.Secrets.KeystorePath,
+			Keys: []string{keystoreKey, truststoreKey},
+		},
+	}
+}
+
+// ProvidedSecret builds a secret owned by the cluster with the data read from a secret provider
+type ProvidedSecret struct {
+	cluster *v1alpha1.CassandraCluster
+	name    string
+	data    map[string][]byte
+}
+
+// NewProvidedSecret constructor for ProvidedSecret
+func NewProvidedSecret(cc *v1alpha1.CassandraCluster, name string, data map[string][]byte) *ProvidedSecret {
+	return &ProvidedSecret{
+		cluster: cc,
+		name:    name,
+		data:    data,
+	}
+}
+
+// Reconcile creates the secret or replaces its data when it changed in the provider, annotations of the existing
+// secret are kept
+func (b *ProvidedSecret) Reconcile(driver opsdk.Client) (sdk.Object, error) {
+	existing := &corev1.Secret{
+		TypeMeta:   GetSecretTypeMeta(),
+		ObjectMeta: buildCertificatesObjectMeta(b.cluster, b.name),
+	}
+	err := driver.Get(existing)
+	if err != nil {
+		return nil, errors.New("could not get existing")
+	}
+
+	if existing.ResourceVersion == "" {
+		desired := &corev1.Secret{
+			TypeMeta:   GetSecretTypeMeta(),
+			ObjectMeta: buildCertificatesObjectMeta(b.cluster, b.name),
+			Type:       corev1.SecretTypeOpaque,
+			Data:       b.data,
+		}
+		return desired, driver.Create(desired)
+	}
+
+	if !ownedBy(existing.ObjectMeta, b.cluster.GetUID()) {
+		return nil, fmt.Errorf("secret %s exists and is not owned by cluster %s", b.name, b.cluster.GetName())
+	}
+
+	if reflect.DeepEqual(existing.Data, b.data) {
+		return existing, nil
+	}
+
+	logrus.Infof("Updating secret %s of cluster %s from the secret provider", b.name, b.cluster.GetName())
+	existing.Data = b.data
+	return existing, driver.Update(existing)
+}
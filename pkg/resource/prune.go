@@ -0,0 +1,220 @@
+package resource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	monitoringv1 "github.com/pantheon-systems/cassandra-operator/pkg/apis/monitoring/v1"
+	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/sirupsen/logrus"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Pruner deletes the children of a cluster that its spec no longer wants, like the public services of nodes
+// beyond the size of the cluster or the repair cron job once repairs are turned off. Only children the cluster
+// is an owner of are deleted.
+type Pruner struct {
+	cluster *v1alpha1.CassandraCluster
+}
+
+// NewPruner creates a new Pruner
+func NewPruner(cc *v1alpha1.CassandraCluster) *Pruner {
+	return &Pruner{
+		cluster: cc,
+	}
+}
+
+// Prune lists the children of the cluster by its label and deletes the unwanted ones, the deleted children are
+// returned
+func (p *Pruner) Prune(driver opsdk.Client) ([]sdk.Object, error) {
+	var pruned []sdk.Object
+
+	services, err := p.pruneServices(driver)
+	if err != nil {
+		return nil, err
+	}
+	pruned = append(pruned, services...)
+
+	cronJobs, err := p.pruneCronJobs(driver)
+	if err != nil {
+		return nil, err
+	}
+	pruned = append(pruned, cronJobs...)
+
+	serviceMonitor, err := p.pruneServiceMonitor(driver)
+	if err != nil {
+		return nil, err
+	}
+	if serviceMonitor != nil {
+		pruned = append(pruned, serviceMonitor)
+	}
+
+	return pruned, nil
+}
+
+// pruneServices deletes the public service once clients are no longer exposed, and the public services of nodes
+// when they are no longer exposed or the node is gone after the cluster scaled down. A node that is still
+// decommissioning keeps its service.
+func (p *Pruner) pruneServices(driver opsdk.Client) ([]sdk.Object, error) {
+	services := &corev1.ServiceList{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceList",
+		},
+	}
+	err := driver.List(p.cluster.GetNamespace(), services, p.listOptions())
+	if err != nil {
+		return nil, fmt.Errorf("could not list services of cluster %s: %v", p.cluster.GetName(), err)
+	}
+
+	var pruned []sdk.Object
+	for i := range services.Items {
+		service := &services.Items[i]
+		if !ownedBy(service.ObjectMeta, p.cluster.GetUID()) {
+			continue
+		}
+
+		wanted := true
+		switch service.GetLabels()["service-type"] {
+		case "public":
+			wanted = ClientExposure(p.cluster).Type != v1alpha1.ExposureNone
+		case "public-pod":
+			wanted, err = p.publicPodServiceWanted(driver, service)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if wanted {
+			continue
+		}
+
+		service.TypeMeta = GetServiceTypeMeta()
+		err = p.delete(driver, service)
+		if err != nil {
+			return nil, err
+		}
+		pruned = append(pruned, service)
+	}
+
+	return pruned, nil
+}
+
+func (p *Pruner) publicPodServiceWanted(driver opsdk.Client, service *corev1.Service) (bool, error) {
+	if InternodeExposure(p.cluster).Type == v1alpha1.ExposureNone {
+		return false, nil
+	}
+
+	prefix := fmt.Sprintf("%s-cassandra-public-", p.cluster.GetName())
+	index, err := strconv.Atoi(strings.TrimPrefix(service.GetName(), prefix))
+	if err != nil || !strings.HasPrefix(service.GetName(), prefix) {
+		// not a service the operator named, leave it alone
+		return true, nil
+	}
+	if index < p.cluster.Spec.Size {
+		return true, nil
+	}
+
+	pod := &corev1.Pod{
+		TypeMeta: GetPodTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-cassandra-%d", p.cluster.GetName(), index),
+			Namespace: p.cluster.GetNamespace(),
+		},
+	}
+	err = driver.Get(pod)
+	if err != nil {
+		return false, err
+	}
+	return pod.ResourceVersion != "", nil
+}
+
+// pruneCronJobs deletes the repair cron job once the repair policy is removed
+func (p *Pruner) pruneCronJobs(driver opsdk.Client) ([]sdk.Object, error) {
+	cronJobs := &batchv1beta1.CronJobList{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1beta1",
+			Kind:       "CronJobList",
+		},
+	}
+	err := driver.List(p.cluster.GetNamespace(), cronJobs, p.listOptions())
+	if err != nil {
+		return nil, fmt.Errorf("could not list cron jobs of cluster %s: %v", p.cluster.GetName(), err)
+	}
+
+	var pruned []sdk.Object
+	for i := range cronJobs.Items {
+		cronJob := &cronJobs.Items[i]
+		if !ownedBy(cronJob.ObjectMeta, p.cluster.GetUID()) {
+			continue
+		}
+		if p.cluster.Spec.Repair != nil && cronJob.GetName() == fmt.Sprintf(cronJobNameTemplate, p.cluster.GetName()) {
+			continue
+		}
+
+		cronJob.TypeMeta = GetCronJobTypeMeta()
+		err = p.delete(driver, cronJob)
+		if err != nil {
+			return nil, err
+		}
+		pruned = append(pruned, cronJob)
+	}
+
+	return pruned, nil
+}
+
+// pruneServiceMonitor deletes the ServiceMonitor once the cluster is no longer monitored by one, nothing is
+// deleted when the ServiceMonitor CRD is not installed
+func (p *Pruner) pruneServiceMonitor(driver opsdk.Client) (sdk.Object, error) {
+	if policy := Monitoring(p.cluster); policy != nil && policy.ServiceMonitor {
+		return nil, nil
+	}
+
+	serviceMonitor := &monitoringv1.ServiceMonitor{
+		TypeMeta: GetServiceMonitorTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf(serviceMonitorNameTemplate, p.cluster.GetName()),
+			Namespace: p.cluster.GetNamespace(),
+		},
+	}
+	err := driver.Get(serviceMonitor)
+	if err != nil {
+		if kindNotInstalled(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if serviceMonitor.ResourceVersion == "" || !ownedBy(serviceMonitor.ObjectMeta, p.cluster.GetUID()) {
+		return nil, nil
+	}
+
+	err = p.delete(driver, serviceMonitor)
+	if err != nil {
+		return nil, err
+	}
+	return serviceMonitor, nil
+}
+
+// delete deletes the child in the background, the objects the child owns are collected after it
+func (p *Pruner) delete(driver opsdk.Client, object sdk.Object) error {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Deleting %s %s/%s, cluster %s no longer wants it", object.GetObjectKind().GroupVersionKind().Kind,
+		accessor.GetNamespace(), accessor.GetName(), p.cluster.GetName())
+
+	background := metav1.DeletePropagationBackground
+	return driver.Delete(object, sdk.WithDeleteOptions(&metav1.DeleteOptions{PropagationPolicy: &background}))
+}
+
+func (p *Pruner) listOptions() sdk.ListOption {
+	selector := labels.SelectorFromSet(map[string]string{"cluster": p.cluster.GetName()})
+	return sdk.WithListOptions(&metav1.ListOptions{LabelSelector: selector.String()})
+}
@@ -0,0 +1,163 @@
+package resource_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	monitoringv1 "github.com/pantheon-systems/cassandra-operator/pkg/apis/monitoring/v1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/stretchr/testify/assert"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getPruneCluster() *v1alpha1.CassandraCluster {
+	return &v1alpha1.CassandraCluster{
+		TypeMeta: resource.GetCassandraClusterTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-1",
+			Namespace: "test-namespace",
+			UID:       "test-uid",
+		},
+		Spec: v1alpha1.ClusterSpec{
+			Size:                    4,
+			EnablePublicPodServices: true,
+			Repair:                  &v1alpha1.RepairPolicy{Schedule: "0 0 * * *"},
+			Monitoring:              &v1alpha1.MonitoringPolicy{ServiceMonitor: true},
+		},
+	}
+}
+
+// exists returns true if the fake client holds the object
+func exists(t *testing.T, client *k8s.FakeClient, object sdk.Object) bool {
+	// Get leaves the object alone when it is missing
+	accessor := object.(metav1.Object)
+	accessor.SetResourceVersion("")
+	assert.NoError(t, client.Get(object))
+	return accessor.GetResourceVersion() != ""
+}
+
+func publicPodService(i int) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta:   resource.GetServiceTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("test-cluster-1-cassandra-public-%d", i), Namespace: "test-namespace"},
+	}
+}
+
+func TestPruner_PrunePublicPodServices(t *testing.T) {
+	cc := getPruneCluster()
+	client := k8s.NewFakeClient()
+	for i := 0; i < cc.Spec.Size; i++ {
+		_, err := resource.NewService(cc, resource.WithServiceType(resource.ServiceTypePublicPod), resource.WithPodNumber(i)).Reconcile(client)
+		assert.NoError(t, err)
+	}
+	// the third node is still decommissioning, the fourth is gone
+	assert.NoError(t, client.Create(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1-cassandra-2", Namespace: "test-namespace"},
+	}))
+
+	cc.Spec.Size = 2
+	pruned, err := resource.NewPruner(cc).Prune(client)
+	assert.NoError(t, err)
+	assert.Len(t, pruned, 1)
+	assert.True(t, exists(t, client, publicPodService(2)))
+	assert.False(t, exists(t, client, publicPodService(3)))
+
+	cc.Spec.EnablePublicPodServices = false
+	pruned, err = resource.NewPruner(cc).Prune(client)
+	assert.NoError(t, err)
+	assert.Len(t, pruned, 3)
+	for i := 0; i < 3; i++ {
+		assert.False(t, exists(t, client, publicPodService(i)))
+	}
+}
+
+func TestPruner_PrunePublicService(t *testing.T) {
+	cc := getPruneCluster()
+	client := k8s.NewFakeClient()
+	_, err := resource.NewService(cc, resource.WithServiceType(resource.ServiceTypePublicLB)).Reconcile(client)
+	assert.NoError(t, err)
+	publicService := &corev1.Service{
+		TypeMeta:   resource.GetServiceTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1-cassandra-public", Namespace: "test-namespace"},
+	}
+
+	pruned, err := resource.NewPruner(cc).Prune(client)
+	assert.NoError(t, err)
+	assert.Empty(t, pruned)
+
+	cc.Spec.Exposure = &v1alpha1.ExposurePolicy{Client: &v1alpha1.ServiceExposure{Type: v1alpha1.ExposureNone}}
+	pruned, err = resource.NewPruner(cc).Prune(client)
+	assert.NoError(t, err)
+	assert.Len(t, pruned, 1)
+	assert.False(t, exists(t, client, publicService))
+}
+
+func TestPruner_PruneRepairCronJob(t *testing.T) {
+	cc := getPruneCluster()
+	client := k8s.NewFakeClient()
+	_, err := resource.NewRepairCronJob(cc).Reconcile(client)
+	assert.NoError(t, err)
+	cronJob := &batchv1beta1.CronJob{
+		TypeMeta:   resource.GetCronJobTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1-cassandra-repair", Namespace: "test-namespace"},
+	}
+
+	pruned, err := resource.NewPruner(cc).Prune(client)
+	assert.NoError(t, err)
+	assert.Empty(t, pruned)
+	assert.True(t, exists(t, client, cronJob))
+
+	cc.Spec.Repair = nil
+	pruned, err = resource.NewPruner(cc).Prune(client)
+	assert.NoError(t, err)
+	assert.Len(t, pruned, 1)
+	assert.False(t, exists(t, client, cronJob))
+}
+
+func TestPruner_PruneServiceMonitor(t *testing.T) {
+	cc := getPruneCluster()
+	client := k8s.NewFakeClient()
+	_, err := resource.NewServiceMonitor(cc).Reconcile(client)
+	assert.NoError(t, err)
+	serviceMonitor := &monitoringv1.ServiceMonitor{
+		TypeMeta:   resource.GetServiceMonitorTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-1-cassandra", Namespace: "test-namespace"},
+	}
+
+	cc.Spec.Monitoring.ServiceMonitor = false
+	pruned, err := resource.NewPruner(cc).Prune(client)
+	assert.NoError(t, err)
+	assert.Len(t, pruned, 1)
+	assert.False(t, exists(t, client, serviceMonitor))
+}
+
+func TestPruner_KeepsForeignObjects(t *testing.T) {
+	cc := getPruneCluster()
+	cc.Spec.Size = 1
+	cc.Spec.EnablePublicPodServices = false
+	cc.Spec.Repair = nil
+
+	// labeled like the children of the cluster, but not owned by it
+	foreignService := publicPodService(3)
+	foreignService.Labels = map[string]string{"cluster": "test-cluster-1", "service-type": "public-pod"}
+	foreignCronJob := &batchv1beta1.CronJob{
+		TypeMeta: resource.GetCronJobTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-1-backup",
+			Namespace: "test-namespace",
+			Labels:    map[string]string{"cluster": "test-cluster-1"},
+		},
+	}
+	client := k8s.NewFakeClient(foreignService, foreignCronJob)
+
+	pruned, err := resource.NewPruner(cc).Prune(client)
+	assert.NoError(t, err)
+	assert.Empty(t, pruned)
+	assert.True(t, exists(t, client, publicPodService(3)))
+	assert.True(t, exists(t, client, foreignCronJob))
+}
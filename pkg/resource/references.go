@@ -0,0 +1,73 @@
+package resource
+
+import (
+	"fmt"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+)
+
+const (
+	// keys of a certs secret that is not managed by the operator, the image loads them from the keystore mount
+	keystoreKey = "keystore.jks"
+
+	cassandraConfigKey = "cassandra.yaml"
+)
+
+// Reference is a secret or configmap the nodes of a cluster depend on and the keys they read from it
+type Reference struct {
+	Name string
+	Keys []string
+}
+
+// JvmAgentConfigName returns the name of the configmap with the configuration of the jvm agent
+func JvmAgentConfigName(cc *v1alpha1.CassandraCluster) string {
+	if cc.Spec.JvmAgentConfigName != "" {
+		return cc.Spec.JvmAgentConfigName
+	}
+	return fmt.Sprintf(jvmAgentConfigNameTemplate, cc.GetName())
+}
+
+// SecretReferences returns the secrets that have to exist before the nodes of the cluster can start, the certs
+// secret is left out when TLS is managed or the keystore is read from vault because the operator creates it
+func SecretReferences(cc *v1alpha1.CassandraCluster) []Reference {
+	if TLSManaged(cc) || VaultKeystore(cc) {
+		return nil
+	}
+
+	return []Reference{
+		{
+			Name: CertificatesSecretName(cc),
+			Keys: []string{keystoreKey, truststoreKey},
+		},
+	}
+}
+
+// ConfigMapReferences returns the configmaps that have to exist before the nodes of the cluster can start
+func ConfigMapReferences(cc *v1alpha1.CassandraCluster) []Reference {
+	references := []Reference{}
+
+	if cc.Spec.ConfigMapName != "" {
+		references = append(references, Reference{
+			Name: cc.Spec.ConfigMapName,
+			Keys: []string{cassandraConfigKey},
+		})
+	}
+
+	// the jolokia agent runs with its defaults when the configmap is empty, the exporters need their config file
+	jvmAgentConfig := Reference{Name: JvmAgentConfigName(cc)}
+	if policy := Monitoring(cc); policy != nil {
+		jvmAgentConfig.Keys = []string{exporterConfigKey(policy)}
+	}
+
+	return append(references, jvmAgentConfig)
+}
+
+// References returns true if one of the references is to the name
+func References(references []Reference, name string) bool {
+	for _, reference := range references {
+		if reference.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// maxJobNameLength keeps the job name usable as the job-name label of its pods
+	maxJobNameLength = 63
+
+	// RepairLabel tells the one off repair jobs apart from the jobs of the repair cron job of the cluster
+	RepairLabel = "repair"
+	// RepairLabelKeyspace is the value of the repair label of the jobs that repair a single keyspace
+	RepairLabelKeyspace = "keyspace"
+)
+
+// KeyspaceRepairJob builds a one off job that repairs a single keyspace, it is started after the replication
+// of the keyspace has been increased
+type KeyspaceRepairJob struct {
+	cluster  *v1alpha1.CassandraCluster
+	keyspace string
+	increase string
+	desired  *batchv1.Job
+}
+
+// NewKeyspaceRepairJob constructor for KeyspaceRepairJob, the increase identifies the replication increase that is
+// repaired and is part of the job name so each increase gets its own repair
+func NewKeyspaceRepairJob(cc *v1alpha1.CassandraCluster, keyspace string, increase string) *KeyspaceRepairJob {
+	return &KeyspaceRepairJob{
+		cluster:  cc,
+		keyspace: keyspace,
+		increase: increase,
+	}
+}
+
+// Reconcile creates the job if it does not exist, the spec of a job can not be changed so an existing
+// job is left as is
+func (b *KeyspaceRepairJob) Reconcile(driver opsdk.Client) (sdk.Object, error) {
+	if b.cluster.Spec.Repair == nil || b.cluster.Spec.Repair.Image == "" {
+		return nil, fmt.Errorf("cluster %s has no repair image configured", b.cluster.GetName())
+	}
+
+	b.configureDesired()
+
+	existing := &batchv1.Job{
+		TypeMeta:   GetJobTypeMeta(),
+		ObjectMeta: b.desired.ObjectMeta,
+	}
+	err := driver.Get(existing)
+	if err != nil {
+		return nil, errors.New("could not get existing")
+	}
+
+	if existing.ResourceVersion != "" {
+		return existing, nil
+	}
+
+	err = driver.Create(b.desired)
+	return b.desired, err
+}
+
+func (b *KeyspaceRepairJob) configureDesired() {
+	b.desired = &batchv1.Job{
+		TypeMeta: GetJobTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.buildJobName(),
+			Namespace: b.cluster.GetNamespace(),
+			Labels:    b.buildLabels(),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "repair",
+							Image: b.cluster.Spec.Repair.Image,
+							Env: append(buildRepairEnvVars(b.cluster), corev1.EnvVar{
+								Name:  keyspaceEnvVar,
+								Value: b.keyspace,
+							}),
+						},
+					},
+					RestartPolicy: corev1.RestartPolicyNever,
+				},
+			},
+		},
+	}
+	b.desired.SetOwnerReferences([]metav1.OwnerReference{asOwner(b.cluster)})
+}
+
+func (b *KeyspaceRepairJob) buildJobName() string {
+	// keyspace names allow underscores and upper case which kube names do not
+	keyspace := strings.ToLower(strings.Replace(b.keyspace, "_", "-", -1))
+
+	name := fmt.Sprintf(repairJobNameTemplate, b.cluster.GetName(), keyspace, hashIncrease(b.increase))
+	if overflow := len(name) - maxJobNameLength; overflow > 0 && overflow < len(keyspace) {
+		keyspace = strings.TrimRight(keyspace[:len(keyspace)-overflow], "-")
+		name = fmt.Sprintf(repairJobNameTemplate, b.cluster.GetName(), keyspace, hashIncrease(b.increase))
+	}
+
+	return name
+}
+
+func (b *KeyspaceRepairJob) buildLabels() map[string]string {
+	labels := map[string]string{
+		"cluster":   b.cluster.GetName(),
+		"keyspace":  strings.ToLower(strings.Replace(b.keyspace, "_", "-", -1)),
+		RepairLabel: RepairLabelKeyspace,
+	}
+
+	if appName, ok := b.cluster.ObjectMeta.Labels["app"]; ok {
+		labels["app"] = appName
+	}
+
+	return labels
+}
+
+func hashIncrease(increase string) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(increase))
+	return hash.Sum32()
+}
@@ -0,0 +1,93 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newRepairJobCluster(name string) *v1alpha1.CassandraCluster {
+	return &v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-namespace",
+		},
+		Spec: v1alpha1.ClusterSpec{
+			Repair: &v1alpha1.RepairPolicy{Image: "repair:latest"},
+		},
+	}
+}
+
+func TestKeyspaceRepairJob_Reconcile(t *testing.T) {
+	var created *batchv1.Job
+	driver := &k8s.MockClient{
+		CreateCallback: func(object sdk.Object) error {
+			created = object.(*batchv1.Job)
+			return nil
+		},
+	}
+
+	obj, err := resource.NewKeyspaceRepairJob(newRepairJobCluster("test-cluster-1"), "App_Data", "test-uid-2").Reconcile(driver)
+
+	assert.NoError(t, err)
+	assert.Equal(t, created, obj)
+	assert.Regexp(t, "^test-cluster-1-cassandra-repair-app-data-[0-9a-f]{8}$", created.GetName())
+	assert.Equal(t, "test-namespace", created.GetNamespace())
+	assert.Equal(t, "repair:latest", created.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, corev1.RestartPolicyNever, created.Spec.Template.Spec.RestartPolicy)
+	assert.Contains(t, created.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "KEYSPACE", Value: "App_Data"})
+	assert.Contains(t, created.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "CASSANDRA_CLUSTER", Value: "test-cluster-1"})
+	assert.Equal(t, "keyspace", created.GetLabels()["repair"])
+
+	// a later increase gets a new job
+	other, err := resource.NewKeyspaceRepairJob(newRepairJobCluster("test-cluster-1"), "App_Data", "test-uid-4").Reconcile(driver)
+	assert.NoError(t, err)
+	assert.NotEqual(t, obj.(*batchv1.Job).GetName(), other.(*batchv1.Job).GetName())
+}
+
+func TestKeyspaceRepairJob_ReconcileExisting(t *testing.T) {
+	driver := &k8s.MockClient{
+		GetCallback: func(into sdk.Object, opts ...sdk.GetOption) error {
+			into.(*batchv1.Job).ResourceVersion = "1"
+			return nil
+		},
+		CreateCallback: func(object sdk.Object) error {
+			t.Error("existing job should not be created")
+			return nil
+		},
+	}
+
+	_, err := resource.NewKeyspaceRepairJob(newRepairJobCluster("test-cluster-1"), "app_data", "test-uid-2").Reconcile(driver)
+	assert.NoError(t, err)
+}
+
+func TestKeyspaceRepairJob_LongName(t *testing.T) {
+	var created *batchv1.Job
+	driver := &k8s.MockClient{
+		CreateCallback: func(object sdk.Object) error {
+			created = object.(*batchv1.Job)
+			return nil
+		},
+	}
+
+	keyspace := "a_very_long_keyspace_name_that_goes_on_and_on_x"
+	_, err := resource.NewKeyspaceRepairJob(newRepairJobCluster("test-cluster-1"), keyspace, "test-uid-2").Reconcile(driver)
+
+	assert.NoError(t, err)
+	assert.True(t, len(created.GetName()) <= 63, created.GetName())
+}
+
+func TestKeyspaceRepairJob_NoRepairImage(t *testing.T) {
+	cluster := newRepairJobCluster("test-cluster-1")
+	cluster.Spec.Repair = nil
+
+	_, err := resource.NewKeyspaceRepairJob(cluster, "app_data", "test-uid-2").Reconcile(&k8s.MockClient{})
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
 	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -61,7 +62,7 @@ func (b *Service) Reconcile(driver opsdk.Client) (sdk.Object, error) {
 
 	existing := &corev1.Service{
 		TypeMeta:   GetServiceTypeMeta(),
-		ObjectMeta: b.configured.ObjectMeta,
+		ObjectMeta: metav1.ObjectMeta{Name: b.configured.Name, Namespace: b.configured.Namespace},
 	}
 	err = driver.Get(existing)
 	if err != nil {
@@ -73,7 +74,12 @@ func (b *Service) Reconcile(driver opsdk.Client) (sdk.Object, error) {
 		// we put our code here to reconcile the two and return
 		// the reconciled object
 		b.configured.ResourceVersion = existing.ResourceVersion
-		b.configured.Spec.ClusterIP = existing.Spec.ClusterIP
+		mergeServiceSpec(b.configured, existing)
+		preserveForeignMetadata(b.configured, existing)
+		// the merged spec holds the whole existing spec, so fields the operator emptied are found as well
+		if equality.Semantic.DeepEqual(b.configured.Spec, existing.Spec) && !drifted(b.configured, existing) {
+			return b.configured, nil
+		}
 		err = driver.Update(b.configured)
 	} else {
 		err = driver.Create(b.configured)
@@ -110,6 +116,7 @@ func (b *Service) buildConfigured(clusterServiceType ClusterServiceType) error {
 
 	b.configureDefaultSelectors()
 	b.configureDefaultLabels()
+	b.configureTemplate()
 
 	b.setOwner(asOwner(b.cluster))
 	return nil
@@ -122,8 +129,7 @@ func (b *Service) configurePublicLB() {
 		Labels:    map[string]string{},
 	}
 
-	b.configured.Spec.Type = corev1.ServiceTypeLoadBalancer
-	b.configured.Spec.Ports = []corev1.ServicePort{
+	b.configureExposure(ClientExposure(b.cluster), []corev1.ServicePort{
 		{
 			Port: 9042,
 			Name: "cql",
@@ -132,7 +138,7 @@ func (b *Service) configurePublicLB() {
 			Port: 9160,
 			Name: "thrift",
 		},
-	}
+	})
 
 	labels := b.configured.GetLabels()
 	labels["service-type"] = "public"
@@ -144,17 +150,16 @@ func (b *Service) configurePublicPod() {
 	podNumber := b.options.PodNumber
 
 	b.configured.ObjectMeta = metav1.ObjectMeta{
-		Name:      fmt.Sprintf("%s-cassandra-public-%d", clusterName, podNumber),
+		Name:      fmt.Sprintf(publicPodServiceNameTemplate, clusterName, podNumber),
 		Namespace: b.cluster.GetNamespace(),
 		Labels:    map[string]string{},
 	}
-	b.configured.Spec.Type = corev1.ServiceTypeLoadBalancer
-	b.configured.Spec.Ports = []corev1.ServicePort{
+	b.configureExposure(InternodeExposure(b.cluster), []corev1.ServicePort{
 		{
 			Port: 7001,
 			Name: "ssl-internode-cluster",
 		},
-	}
+	})
 
 	b.configured.Spec.Selector["statefulset.kubernetes.io/pod-name"] = fmt.Sprintf("%s-cassandra-%d", clusterName, podNumber)
 
@@ -163,6 +168,28 @@ func (b *Service) configurePublicPod() {
 	b.configured.SetLabels(labels)
 }
 
+// configureExposure sets the type, the ports and the load balancer of a public service, the ports of the exposure
+// replace the default ones
+func (b *Service) configureExposure(exposure *v1alpha1.ServiceExposure, defaultPorts []corev1.ServicePort) {
+	b.configured.Spec.Type = corev1.ServiceTypeLoadBalancer
+	if exposure.Type == v1alpha1.ExposureNodePort {
+		b.configured.Spec.Type = corev1.ServiceTypeNodePort
+	}
+
+	b.configured.Spec.Ports = defaultPorts
+	if len(exposure.Ports) > 0 {
+		b.configured.Spec.Ports = exposure.Ports
+	}
+
+	if b.configured.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		b.configured.Spec.LoadBalancerSourceRanges = exposure.SourceRanges
+	}
+
+	if len(exposure.Annotations) > 0 {
+		b.configured.SetAnnotations(mergeMap(map[string]string{}, exposure.Annotations))
+	}
+}
+
 func (b *Service) configureHeadless() {
 	b.configured.ObjectMeta = metav1.ObjectMeta{
 		Name:      fmt.Sprintf("%s-cassandra-headless", b.cluster.GetName()),
@@ -182,6 +209,14 @@ func (b *Service) configureHeadless() {
 		},
 	}
 
+	// the ServiceMonitor scrapes the exporter of every node through the endpoints of the headless service
+	if policy := Monitoring(b.cluster); policy != nil {
+		b.configured.Spec.Ports = append(b.configured.Spec.Ports, corev1.ServicePort{
+			Port: policy.Port,
+			Name: exporterPortName,
+		})
+	}
+
 	labels := b.configured.GetLabels()
 	labels["service-type"] = "headless"
 	b.configured.SetLabels(labels)
@@ -224,6 +259,30 @@ func (b *Service) configureDefaultLabels() {
 	b.configured.SetLabels(mergeMap(serviceLabels, clusterLabels))
 }
 
+// configureTemplate adds the labels and annotations of the service template, the labels of the operator and the
+// annotations of the exposure win. The annotations of both are recorded so they are removed once they are dropped.
+func (b *Service) configureTemplate() {
+	annotations := map[string]string{}
+	labels := map[string]string{}
+	if template := b.cluster.Spec.ServiceTemplate; template != nil {
+		mergeMap(annotations, template.Annotations)
+		for key, value := range template.Labels {
+			if _, ok := b.configured.Labels[key]; !ok {
+				labels[key] = value
+			}
+		}
+	}
+
+	annotations = mergeMap(annotations, b.configured.GetAnnotations())
+	if len(annotations) > 0 {
+		b.configured.SetAnnotations(annotations)
+	}
+	recordKeys(b.configured, TemplateAnnotationsAnnotation, annotations)
+
+	b.configured.SetLabels(mergeMap(b.configured.GetLabels(), labels))
+	recordKeys(b.configured, TemplateLabelsAnnotation, labels)
+}
+
 func (b *Service) configureDefaultSelectors() {
 	b.configured.Spec.Selector["cluster"] = b.cluster.GetName()
 	b.configured.Spec.Selector["state"] = "serving"
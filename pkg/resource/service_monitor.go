@@ -0,0 +1,97 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	monitoringv1 "github.com/pantheon-systems/cassandra-operator/pkg/apis/monitoring/v1"
+	opsdk "github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceMonitor reconciles the prometheus-operator ServiceMonitor that scrapes the exporter of the nodes
+// through the headless service
+type ServiceMonitor struct {
+	cluster *v1alpha1.CassandraCluster
+	desired *monitoringv1.ServiceMonitor
+}
+
+// NewServiceMonitor creates a new ServiceMonitor
+func NewServiceMonitor(cc *v1alpha1.CassandraCluster) *ServiceMonitor {
+	return &ServiceMonitor{
+		cluster: cc,
+	}
+}
+
+// Reconcile creates or updates the ServiceMonitor, nothing is returned when the ServiceMonitor CRD is not
+// installed in the cluster
+func (b *ServiceMonitor) Reconcile(driver opsdk.Client) (sdk.Object, error) {
+	b.buildDesired()
+
+	existing := &monitoringv1.ServiceMonitor{
+		TypeMeta:   GetServiceMonitorTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Name: b.desired.Name, Namespace: b.desired.Namespace},
+	}
+	err := driver.Get(existing)
+	if err != nil {
+		if kindNotInstalled(err) {
+			logrus.Debugf("ServiceMonitor CRD is not installed, not monitoring cluster %s", b.cluster.GetName())
+			return nil, nil
+		}
+		return nil, errors.New("could not get existing")
+	}
+
+	if existing.GetResourceVersion() != "" {
+		b.desired.SetResourceVersion(existing.GetResourceVersion())
+		preserveForeignMetadata(b.desired, existing)
+		err = driver.Update(b.desired)
+	} else {
+		err = driver.Create(b.desired)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return b.desired, nil
+}
+
+func (b *ServiceMonitor) buildDesired() {
+	b.desired = &monitoringv1.ServiceMonitor{
+		TypeMeta: GetServiceMonitorTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf(serviceMonitorNameTemplate, b.cluster.GetName()),
+			Namespace: b.cluster.GetNamespace(),
+			Labels:    mergeMap(map[string]string{"cluster": b.cluster.GetName()}, b.cluster.GetLabels()),
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"cluster":      b.cluster.GetName(),
+					"service-type": "headless",
+				},
+			},
+			NamespaceSelector: monitoringv1.NamespaceSelector{
+				MatchNames: []string{b.cluster.GetNamespace()},
+			},
+			Endpoints: []monitoringv1.Endpoint{
+				{
+					Port: exporterPortName,
+					Path: "/metrics",
+				},
+			},
+		},
+	}
+
+	b.desired.SetOwnerReferences(append(b.desired.GetOwnerReferences(), asOwner(b.cluster)))
+}
+
+// kindNotInstalled returns true when the api server does not serve the kind, the sdk wraps the error of the
+// rest mapper so its message is matched as well
+func kindNotInstalled(err error) bool {
+	return meta.IsNoMatchError(err) || strings.Contains(err.Error(), "no matches for kind")
+}
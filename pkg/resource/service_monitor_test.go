@@ -0,0 +1,109 @@
+package resource_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	monitoringv1 "github.com/pantheon-systems/cassandra-operator/pkg/apis/monitoring/v1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getServiceMonitorCluster() *v1alpha1.CassandraCluster {
+	return &v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-1",
+			Namespace: "test-namespace",
+			Labels: map[string]string{
+				"app": "test-app",
+			},
+		},
+		Spec: v1alpha1.ClusterSpec{
+			Monitoring: &v1alpha1.MonitoringPolicy{
+				ServiceMonitor: true,
+			},
+		},
+	}
+}
+
+func TestServiceMonitor_ReconcileCreate(t *testing.T) {
+	var created sdk.Object
+	mockClient := &k8s.MockClient{
+		CreateCallback: func(object sdk.Object) error {
+			created = object
+			return nil
+		},
+	}
+
+	expected := &monitoringv1.ServiceMonitor{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "monitoring.coreos.com/v1",
+			Kind:       "ServiceMonitor",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-1-cassandra",
+			Namespace: "test-namespace",
+			Labels: map[string]string{
+				"app":     "test-app",
+				"cluster": "test-cluster-1",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{Name: "test-cluster-1", Controller: &trueVar},
+			},
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"cluster":      "test-cluster-1",
+					"service-type": "headless",
+				},
+			},
+			NamespaceSelector: monitoringv1.NamespaceSelector{
+				MatchNames: []string{"test-namespace"},
+			},
+			Endpoints: []monitoringv1.Endpoint{
+				{Port: "prometheus", Path: "/metrics"},
+			},
+		},
+	}
+
+	got, err := resource.NewServiceMonitor(getServiceMonitorCluster()).Reconcile(mockClient)
+	assert.NoError(t, err)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ServiceMonitor.Reconcile() = %v, want %v", got, expected)
+	}
+	assert.Equal(t, expected, created)
+}
+
+func TestServiceMonitor_ReconcileCRDNotInstalled(t *testing.T) {
+	mockClient := &k8s.MockClient{
+		GetCallback: func(into sdk.Object, opts ...sdk.GetOption) error {
+			return errors.New(`failed to get resource client: no matches for kind "ServiceMonitor" in version "monitoring.coreos.com/v1"`)
+		},
+		CreateCallback: func(object sdk.Object) error {
+			t.Error("ServiceMonitor created without its CRD")
+			return nil
+		},
+	}
+
+	got, err := resource.NewServiceMonitor(getServiceMonitorCluster()).Reconcile(mockClient)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestServiceMonitor_ReconcileGetError(t *testing.T) {
+	mockClient := &k8s.MockClient{
+		GetCallback: func(into sdk.Object, opts ...sdk.GetOption) error {
+			return errors.New("some error")
+		},
+	}
+
+	got, err := resource.NewServiceMonitor(getServiceMonitorCluster()).Reconcile(mockClient)
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
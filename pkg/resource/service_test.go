@@ -5,6 +5,7 @@ import (
 	"github.com/operator-framework/operator-sdk/pkg/util/k8sutil"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
 	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/stretchr/testify/assert"
 	"reflect"
 	"testing"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -146,14 +148,19 @@ func TestService_Reconcile(t *testing.T) {
 						"state":   "serving",
 						"app":     "test-app",
 					},
+					// the ports of an existing service are defaulted like the api server does
 					Ports: []corev1.ServicePort{
 						{
-							Port: 9042,
-							Name: "cql",
+							Port:       9042,
+							Name:       "cql",
+							Protocol:   corev1.ProtocolTCP,
+							TargetPort: intstr.FromInt(9042),
 						},
 						{
-							Port: 9160,
-							Name: "thrift",
+							Port:       9160,
+							Name:       "thrift",
+							Protocol:   corev1.ProtocolTCP,
+							TargetPort: intstr.FromInt(9160),
 						},
 					},
 				},
@@ -437,3 +444,65 @@ func TestService_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestService_ReconcilePreservesForeignChanges(t *testing.T) {
+	cc := getDriftCluster()
+	client := k8s.NewFakeClient()
+	newService := func() *resource.Service {
+		return resource.NewService(cc, resource.WithServiceType(resource.ServiceTypePublicLB))
+	}
+	created, err := newService().Reconcile(client)
+	assert.NoError(t, err)
+
+	// a cloud controller and a user change the service
+	stored := created.(*corev1.Service).DeepCopy()
+	stored.Labels["team"] = "storage"
+	stored.Annotations = map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"}
+	stored.Spec.LoadBalancerIP = "203.0.113.10"
+	stored.Spec.HealthCheckNodePort = 32000
+	stored.Spec.Ports[0].Port = 9043
+	assert.NoError(t, client.Update(stored))
+
+	_, err = newService().Reconcile(client)
+	assert.NoError(t, err)
+	service := &corev1.Service{TypeMeta: resource.GetServiceTypeMeta(), ObjectMeta: metav1.ObjectMeta{Name: stored.Name, Namespace: stored.Namespace}}
+	assert.NoError(t, client.Get(service))
+	assert.Equal(t, "storage", service.Labels["team"])
+	assert.Equal(t, "public", service.Labels["service-type"])
+	assert.Equal(t, "true", service.Annotations["service.beta.kubernetes.io/aws-load-balancer-internal"])
+	assert.Equal(t, "203.0.113.10", service.Spec.LoadBalancerIP)
+	assert.Equal(t, int32(32000), service.Spec.HealthCheckNodePort)
+	// the ports are owned by the operator
+	assert.Equal(t, int32(9042), service.Spec.Ports[0].Port)
+}
+
+func TestService_ReconcileServiceTemplate(t *testing.T) {
+	cc := getDriftCluster()
+	cc.Spec.ServiceTemplate = &v1alpha1.ServiceTemplate{
+		Labels:      map[string]string{"team": "storage", "cluster": "other"},
+		Annotations: map[string]string{"cloud.google.com/load-balancer-type": "Internal"},
+	}
+	client := k8s.NewFakeClient()
+	newService := func() *resource.Service {
+		return resource.NewService(cc, resource.WithServiceType(resource.ServiceTypePublicLB))
+	}
+	created, err := newService().Reconcile(client)
+	assert.NoError(t, err)
+	service := created.(*corev1.Service)
+	assert.Equal(t, "storage", service.Labels["team"])
+	assert.Equal(t, "test-cluster-1", service.Labels["cluster"])
+	assert.Equal(t, "Internal", service.Annotations["cloud.google.com/load-balancer-type"])
+
+	stored := service.DeepCopy()
+	stored.Annotations["external-dns.alpha.kubernetes.io/hostname"] = "cassandra.example.com"
+	assert.NoError(t, client.Update(stored))
+
+	// removing the template removes what it added, the foreign annotation stays
+	cc.Spec.ServiceTemplate = nil
+	_, err = newService().Reconcile(client)
+	assert.NoError(t, err)
+	service = &corev1.Service{TypeMeta: resource.GetServiceTypeMeta(), ObjectMeta: metav1.ObjectMeta{Name: service.Name, Namespace: service.Namespace}}
+	assert.NoError(t, client.Get(service))
+	assert.NotContains(t, service.Labels, "team")
+	assert.Equal(t, map[string]string{"external-dns.alpha.kubernetes.io/hostname": "cassandra.example.com"}, service.Annotations)
+}
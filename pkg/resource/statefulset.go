@@ -27,6 +27,7 @@ type StatefulSet struct {
 
 	seedList            []string
 	desiredReplicas     int32
+	existingReplicas    int32
 	enableAutoBootstrap bool
 
 	options *builderOp
@@ -76,6 +77,7 @@ func (b *StatefulSet) Reconcile(driver opsdk.Client) (sdk.Object, error) {
 
 	existingReplicas := *existing.Spec.Replicas
 	existingReadyReplicas := existing.Status.ReadyReplicas
+	b.existingReplicas = existingReplicas
 
 	// TODO: can capture the second return val for this method which is a bool to repair or not
 	b.desiredReplicas, _ = b.calculateReplicas(existingReplicas, existingReadyReplicas)
@@ -85,6 +87,10 @@ func (b *StatefulSet) Reconcile(driver opsdk.Client) (sdk.Object, error) {
 	b.configureDesired()
 
 	b.desired.ResourceVersion = existing.ResourceVersion
+	preserveForeignMetadata(b.desired, existing)
+	if !drifted(b.desired, existing) {
+		return b.desired, nil
+	}
 	// We are using Update here as we have the OnDelete update stratagy in place for the stateful set
 	// See https://kubernetes.io/docs/tutorials/stateful-application/basic-stateful-set/#updating-statefulsets
 	err = driver.Update(b.desired)
@@ -96,6 +102,11 @@ func (b *StatefulSet) Reconcile(driver opsdk.Client) (sdk.Object, error) {
 	return b.desired, nil
 }
 
+// ScaledUp returns the number of replicas of the statefulset before Reconcile added a node and whether it did
+func (b *StatefulSet) ScaledUp() (int32, bool) {
+	return b.existingReplicas, b.existingReplicas > 0 && b.desiredReplicas > b.existingReplicas
+}
+
 // Calculates seed list for cluster. If ExternalSeeds is set in the resource
 // We append the external seeds to the primary cluster seed list.
 // TODO: Do not make all local cluster nodes seeds
@@ -179,6 +190,11 @@ func (b *StatefulSet) calculateReplicas(existingReplicas, existingReadyReplicas
 		repair = true
 	}
 
+	// the nodes added last are still streaming, the next one is added once they are done
+	if b.options.HoldScaleUp && replicas > existingReplicas {
+		replicas, repair = existingReplicas, false
+	}
+
 	return replicas, repair
 }
 
@@ -218,13 +234,16 @@ func (b *StatefulSet) configureDesired() {
 	b.setOwner(asOwner(b.cluster))
 	b.buildVolumeClaimTemplates()
 
-	// TODO: JvmAgent should be itoa or string enum
-	if b.cluster.Spec.JvmAgent == "sidecar" {
+	if policy := Monitoring(b.cluster); policy != nil {
+		b.buildPrometheusAnnotations(policy)
+	}
+
+	// the statefulset uses OnDelete, changing the revision only marks the pods for the managed restart
+	if b.options.CertsRevision != "" {
 		if b.desired.Spec.Template.ObjectMeta.Annotations == nil {
 			b.desired.Spec.Template.ObjectMeta.Annotations = map[string]string{}
 		}
-		b.desired.Spec.Template.ObjectMeta.Annotations["prometheus.io/scrape"] = "true"
-		b.desired.Spec.Template.ObjectMeta.Annotations["prometheus.io/port"] = "9126"
+		b.desired.Spec.Template.ObjectMeta.Annotations[CertificatesRevisionAnnotation] = b.options.CertsRevision
 	}
 }
 
@@ -254,6 +254,119 @@ func TestStatefulSet_ReconcileJvmAgent(t *testing.T) {
 	}
 }
 
+func TestStatefulSet_ReconcileMonitoringCassandraExporter(t *testing.T) {
+	cluster := getBaseInputCluster()
+	cluster.Spec.Monitoring = &v1alpha1.MonitoringPolicy{
+		Exporter: v1alpha1.ExporterCassandraExporter,
+		Port:     9500,
+		Resources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: kuberesource.MustParse("1Gi"),
+			},
+		},
+	}
+
+	expected := getBaseExpectedStatefulSet()
+	expected.Spec.Template.ObjectMeta.Annotations = map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   "9500",
+	}
+	expected.Spec.Template.Spec.Containers = append(expected.Spec.Template.Spec.Containers, corev1.Container{
+		Name:            "cassandra-exporter",
+		Image:           "criteord/cassandra_exporter:2.0.2",
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Ports: []corev1.ContainerPort{
+			{
+				ContainerPort: 9500,
+				Name:          "prometheus",
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "jvm-agent-config",
+				MountPath: "/etc/cassandra_exporter",
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: kuberesource.MustParse("1Gi"),
+			},
+		},
+	})
+
+	mockClient := &k8s.MockClient{}
+	statefulset := getNewSS(cluster)
+	got, err := statefulset.Reconcile(mockClient)
+
+	assert.NoError(t, err)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("StatefulSet.Reconcile() = %v, want %v", got, expected)
+	}
+}
+
+func TestStatefulSet_ReconcileMonitoringJMXExporter(t *testing.T) {
+	cluster := getBaseInputCluster()
+	cluster.Spec.Monitoring = &v1alpha1.MonitoringPolicy{
+		Exporter: v1alpha1.ExporterJMX,
+		Image:    "jmx-exporter-agent:0.3.1",
+	}
+
+	expected := getBaseExpectedStatefulSet()
+	expected.Spec.Template.ObjectMeta.Annotations = map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   "7070",
+	}
+	expected.Spec.Template.Spec.Volumes = append(expected.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "jmx-exporter",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	expected.Spec.Template.Spec.InitContainers = []corev1.Container{
+		{
+			Name:            "jmx-exporter",
+			Image:           "jmx-exporter-agent:0.3.1",
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"cp", "/jmx_prometheus_javaagent.jar", "/jmx-exporter"},
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "jmx-exporter",
+					MountPath: "/jmx-exporter",
+				},
+			},
+		},
+	}
+
+	container := &expected.Spec.Template.Spec.Containers[0]
+	container.Ports = append(container.Ports, corev1.ContainerPort{
+		ContainerPort: 7070,
+		Name:          "prometheus",
+	})
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  "JVM_EXTRA_OPTS",
+		Value: "-javaagent:/jmx-exporter/jmx_prometheus_javaagent.jar=7070:/jmx-exporter-config/jmx-exporter.yaml",
+	})
+	container.VolumeMounts = append(container.VolumeMounts,
+		corev1.VolumeMount{
+			Name:      "jvm-agent-config",
+			MountPath: "/jmx-exporter-config",
+		},
+		corev1.VolumeMount{
+			Name:      "jmx-exporter",
+			MountPath: "/jmx-exporter",
+		},
+	)
+
+	mockClient := &k8s.MockClient{}
+	statefulset := getNewSS(cluster)
+	got, err := statefulset.Reconcile(mockClient)
+
+	assert.NoError(t, err)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("StatefulSet.Reconcile() = %v, want %v", got, expected)
+	}
+}
+
 func TestStatefulSet_ReconcileAffinityAndAnti(t *testing.T) {
 	affinity := &corev1.Affinity{
 		PodAntiAffinity: &corev1.PodAntiAffinity{
@@ -435,6 +548,43 @@ func TestStatefulSet_ReconcileAlreadyExistsSize3Replica1Ready1(t *testing.T) {
 	}
 }
 
+func TestStatefulSet_ReconcileScaleUpHeld(t *testing.T) {
+	cluster := getBaseInputCluster()
+	cluster.Spec.Size = 3
+
+	existing := getBaseExpectedStatefulSet()
+	existing.Spec.Replicas = &two
+	existing.ObjectMeta.ResourceVersion = "some-resource-version"
+	existing.Status.ReadyReplicas = two
+
+	mockClient := &k8s.MockClient{
+		GetCallback: func(into sdk.Object, opts ...sdk.GetOption) error {
+			return k8sutil.RuntimeObjectIntoRuntimeObject(existing, into)
+		},
+	}
+	statefulset := resource.NewStatefulSet(
+		cluster,
+		resource.WithServiceAccountName("some-service-account-name"),
+		resource.WithServiceName("some-service-name"),
+		resource.WithScaleUpHeld(true),
+	)
+	got, err := statefulset.Reconcile(mockClient)
+
+	assert.NoError(t, err)
+	assert.Equal(t, two, *got.(*appsv1.StatefulSet).Spec.Replicas)
+	_, scaledUp := statefulset.ScaledUp()
+	assert.False(t, scaledUp)
+
+	statefulset = getNewSS(cluster)
+	got, err = statefulset.Reconcile(mockClient)
+
+	assert.NoError(t, err)
+	assert.Equal(t, three, *got.(*appsv1.StatefulSet).Spec.Replicas)
+	from, scaledUp := statefulset.ScaledUp()
+	assert.True(t, scaledUp)
+	assert.Equal(t, two, from)
+}
+
 func TestStatefulSet_ReconcileAlreadyExistsSize3Replica2Ready2(t *testing.T) {
 	cluster := getBaseInputCluster()
 	cluster.Spec.Size = 3
@@ -599,6 +749,101 @@ func TestStatefulSet_ReconcileDatacenter(t *testing.T) {
 	}
 }
 
+func TestStatefulSet_ReconcileAuth(t *testing.T) {
+	cluster := getBaseInputCluster()
+	cluster.Spec.Auth = &v1alpha1.AuthPolicy{Enabled: true}
+
+	expected := getBaseExpectedStatefulSet()
+	expected.Spec.Template.Spec.Containers[0].Env = append(
+		expected.Spec.Template.Spec.Containers[0].Env,
+		corev1.EnvVar{
+			Name:  "CASSANDRA_AUTHENTICATOR",
+			Value: "PasswordAuthenticator",
+		},
+		corev1.EnvVar{
+			Name:  "CASSANDRA_AUTHORIZER",
+			Value: "CassandraAuthorizer",
+		},
+	)
+
+	mockClient := &k8s.MockClient{}
+	statefulset := getNewSS(cluster)
+	got, err := statefulset.Reconcile(mockClient)
+
+	assert.NoError(t, err)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("StatefulSet.Reconcile() = %v, want %v", got, expected)
+	}
+}
+
+func TestStatefulSet_ReconcileTLS(t *testing.T) {
+	cluster := getBaseInputCluster()
+	cluster.Spec.TLS = &v1alpha1.TLSPolicy{Managed: true, ClientEncryption: true}
+
+	password := &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: "test-cluster-1-cassandra-keystore-password",
+			},
+			Key: "password",
+		},
+	}
+
+	expected := getBaseExpectedStatefulSet()
+	expected.Spec.Template.ObjectMeta.Annotations = map[string]string{
+		"database.panth.io/certificates-revision": "3",
+	}
+	expected.Spec.Template.Spec.Containers[0].Env = append(
+		expected.Spec.Template.Spec.Containers[0].Env,
+		corev1.EnvVar{
+			Name: "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		},
+		corev1.EnvVar{
+			Name:  "CASSANDRA_INTERNODE_ENCRYPTION",
+			Value: "all",
+		},
+		corev1.EnvVar{
+			Name:  "CASSANDRA_CLIENT_ENCRYPTION",
+			Value: "true",
+		},
+		corev1.EnvVar{
+			Name:  "CASSANDRA_KEYSTORE_PATH",
+			Value: "/keystore/$(POD_NAME).keystore.jks",
+		},
+		corev1.EnvVar{
+			Name:      "CASSANDRA_KEYSTORE_PASSWORD",
+			ValueFrom: password,
+		},
+		corev1.EnvVar{
+			Name:  "CASSANDRA_TRUSTSTORE_PATH",
+			Value: "/keystore/truststore.jks",
+		},
+		corev1.EnvVar{
+			Name:      "CASSANDRA_TRUSTSTORE_PASSWORD",
+			ValueFrom: password,
+		},
+	)
+
+	mockClient := &k8s.MockClient{}
+	statefulset := resource.NewStatefulSet(
+		cluster,
+		resource.WithServiceAccountName("some-service-account-name"),
+		resource.WithServiceName("some-service-name"),
+		resource.WithCertificatesRevision("3"),
+	)
+	got, err := statefulset.Reconcile(mockClient)
+
+	assert.NoError(t, err)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("StatefulSet.Reconcile() = %v, want %v", got, expected)
+	}
+}
+
 func TestStatefulSet_ReconcileCapacity(t *testing.T) {
 	cluster := getBaseInputCluster()
 	cluster.Spec.Node.PersistentVolume = &v1alpha1.PersistentVolumeSpec{
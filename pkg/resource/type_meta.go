@@ -36,6 +36,14 @@ func GetCronJobTypeMeta() metav1.TypeMeta {
 	}
 }
 
+// GetJobTypeMeta returns meta/v1 TypeMeta for batch/v1 Job
+func GetJobTypeMeta() metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: jobAPIVersion,
+		Kind:       jobKind,
+	}
+}
+
 // GetPodTypeMeta returns meta/v1 TypeMeta for core/v1 Pod
 func GetPodTypeMeta() metav1.TypeMeta {
 	return metav1.TypeMeta{
@@ -59,3 +67,27 @@ func GetCassandraClusterTypeMeta() metav1.TypeMeta {
 		Kind:       cassandraClusterKind,
 	}
 }
+
+// GetSecretTypeMeta returns meta/v1 TypeMeta for core/v1 Secret
+func GetSecretTypeMeta() metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: secretAPIVersion,
+		Kind:       secretKind,
+	}
+}
+
+// GetConfigMapTypeMeta returns meta/v1 TypeMeta for core/v1 ConfigMap
+func GetConfigMapTypeMeta() metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: configMapAPIVersion,
+		Kind:       configMapKind,
+	}
+}
+
+// GetServiceMonitorTypeMeta returns meta/v1 TypeMeta for monitoring.coreos.com/v1 ServiceMonitor
+func GetServiceMonitorTypeMeta() metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: serviceMonitorAPIVersion,
+		Kind:       serviceMonitorKind,
+	}
+}
@@ -3,6 +3,7 @@ package resource
 import (
 	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func mergeMap(a, b map[string]string) map[string]string {
@@ -13,12 +14,27 @@ func mergeMap(a, b map[string]string) map[string]string {
 }
 
 func asOwner(m *v1alpha1.CassandraCluster) metav1.OwnerReference {
+	return OwnerOf(m.TypeMeta, m.ObjectMeta)
+}
+
+// OwnerOf returns a controller owner reference to an operator managed resource
+func OwnerOf(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) metav1.OwnerReference {
 	trueVar := true
 	return metav1.OwnerReference{
-		APIVersion: m.APIVersion,
-		Kind:       m.Kind,
-		Name:       m.Name,
-		UID:        m.UID,
+		APIVersion: typeMeta.APIVersion,
+		Kind:       typeMeta.Kind,
+		Name:       objectMeta.Name,
+		UID:        objectMeta.UID,
 		Controller: &trueVar,
 	}
 }
+
+// ownedBy returns true if the object has an owner reference to the uid
+func ownedBy(objectMeta metav1.ObjectMeta, uid types.UID) bool {
+	for _, owner := range objectMeta.OwnerReferences {
+		if owner.UID == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -2,23 +2,65 @@ package stub
 
 import (
 	"context"
+	"fmt"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
 	"github.com/pantheon-systems/cassandra-operator/pkg/backend/nodetool"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/secrets"
 	opVersion "github.com/pantheon-systems/cassandra-operator/version"
 
 	opsdk "github.com/operator-framework/operator-sdk/pkg/sdk"
 	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
 	"github.com/pantheon-systems/cassandra-operator/pkg/controller"
+	"github.com/pantheon-systems/cassandra-operator/pkg/metrics"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/pantheon-systems/cassandra-operator/pkg/workqueue"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
-// NewHandler creates a new handler for the cassandra cluster operator
-func NewHandler(k8sDriver k8s.Client, nodetoolDriver *nodetool.Executor) opsdk.Handler {
+// NewHandler creates a new handler for the cassandra cluster operator, the secret provider may be nil when
+// vault is not configured. Events are only processed while Run is running.
+func NewHandler(k8sDriver k8s.Client, nodetoolDriver nodetool.NodeManager, cqlConnector cql.Connector, secretProvider secrets.SecretProvider, opts ...HandlerOption) *Handler {
 	statusManager := controller.NewStatusManager(nodetoolDriver, k8sDriver)
-	return &Handler{
-		k8sDriver:      k8sDriver,
-		statusManager:  statusManager,
-		nodetoolDriver: nodetoolDriver,
+	h := &Handler{
+		k8sDriver:       k8sDriver,
+		statusManager:   statusManager,
+		cleanupManager:  controller.NewCleanupManager(nodetoolDriver, k8sDriver),
+		nodetoolDriver:  nodetoolDriver,
+		cqlSessions:     cql.NewSessionCache(cqlConnector),
+		secretProvider:  secretProvider,
+		clusterSelector: labels.Everything(),
+		references:      newReferenceIndex(),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.queue = workqueue.New(h.process, h.queueOptions...)
+
+	return h
+}
+
+// HandlerOption is a function that sets the configuration on the Handler
+type HandlerOption func(*Handler)
+
+// WithClusterSelector restricts the handler to the clusters matching the label selector, the keyspaces, roles
+// and pods of other clusters are ignored as well
+func WithClusterSelector(selector labels.Selector) HandlerOption {
+	return func(h *Handler) {
+		h.clusterSelector = selector
+	}
+}
+
+// WithQueueOptions sets the options of the queue the events are processed from
+func WithQueueOptions(opts ...workqueue.QueueOption) HandlerOption {
+	return func(h *Handler) {
+		h.queueOptions = append(h.queueOptions, opts...)
 	}
 }
 
@@ -26,43 +68,240 @@ func NewHandler(k8sDriver k8s.Client, nodetoolDriver *nodetool.Executor) opsdk.H
 type Handler struct {
 	k8sDriver      k8s.Client
 	statusManager  *controller.ClusterStatusManager
-	nodetoolDriver *nodetool.Executor
+	cleanupManager *controller.CleanupManager
+	nodetoolDriver nodetool.NodeManager
+	// cqlSessions shares the CQL sessions of a cluster between its reconciles
+	cqlSessions    *cql.SessionCache
+	secretProvider secrets.SecretProvider
+	queue          *workqueue.Queue
+	queueOptions   []workqueue.QueueOption
+	// clusterSelector selects the clusters the operator manages
+	clusterSelector labels.Selector
+	// references are the secrets and configmaps the clusters in the initial phase wait for
+	references *referenceIndex
 }
 
-// Handle takes events and dispatches to synchronization code
+// Handle queues the event under the cluster the object belongs to, the events of a cluster are processed one
+// at a time and only the latest pending event of an object is processed. The events of secrets and configmaps no
+// cluster in the initial phase waits for are dropped.
 func (h *Handler) Handle(ctx context.Context, event opsdk.Event) error {
-	var err error
 	switch o := event.Object.(type) {
 	case *v1alpha1.CassandraCluster:
-		err = h.handleCassandraClusterEvent(o, event.Deleted)
+		h.references.update(o, event.Deleted || !h.clusterSelector.Matches(labels.Set(o.GetLabels())))
+	case *corev1.Secret:
+		if !h.references.referenced("Secret", o.GetNamespace(), o.GetName()) {
+			return nil
+		}
+	case *corev1.ConfigMap:
+		if !h.references.referenced("ConfigMap", o.GetNamespace(), o.GetName()) {
+			return nil
+		}
+	}
+
+	key, id := eventKey(event)
+	if key == "" {
+		return nil
+	}
+
+	h.queue.Add(key, id, event)
+	return nil
+}
+
+// Run processes the queued events with the number of workers until the context is done
+func (h *Handler) Run(ctx context.Context, workers int) {
+	h.queue.Run(ctx, workers)
+}
+
+// eventKey returns the cluster the object of the event belongs to as the key it is queued under and the id of
+// the object
+func eventKey(event opsdk.Event) (string, string) {
+	var namespace, name, cluster string
+	switch o := event.Object.(type) {
+	case *v1alpha1.CassandraCluster:
+		namespace, name, cluster = o.GetNamespace(), o.GetName(), o.GetName()
+	case *v1alpha1.CassandraKeyspace:
+		namespace, name, cluster = o.GetNamespace(), o.GetName(), o.Spec.Cluster
+	case *v1alpha1.CassandraRole:
+		namespace, name, cluster = o.GetNamespace(), o.GetName(), o.Spec.Cluster
 	case *corev1.Pod:
-		err = h.handlePodEvent(o, event.Deleted)
+		namespace, name, cluster = o.GetNamespace(), o.GetName(), o.GetLabels()["cluster"]
+	case *corev1.Secret:
+		namespace, name = o.GetNamespace(), o.GetName()
+	case *corev1.ConfigMap:
+		namespace, name = o.GetNamespace(), o.GetName()
+	default:
+		return "", ""
+	}
+
+	// the kind is not set on every object the sdk passes so the go type identifies it
+	id := fmt.Sprintf("%T %s/%s", event.Object, namespace, name)
+	if cluster == "" {
+		// secrets and configmaps are queued on their own, the clusters they affect are queued when processed
+		return id, id
+	}
+
+	return fmt.Sprintf("%s/%s", namespace, cluster), id
+}
+
+// process dispatches a queued event to the synchronization code, the context is done when the handler stops
+func (h *Handler) process(ctx context.Context, value interface{}) error {
+	event := value.(opsdk.Event)
+
+	selected, err := h.selected(event.Object)
+	if err != nil || !selected {
+		return err
+	}
+
+	switch o := event.Object.(type) {
+	case *v1alpha1.CassandraCluster:
+		err = h.handleCassandraClusterEvent(ctx, o, event.Deleted)
+	case *v1alpha1.CassandraKeyspace:
+		err = h.handleCassandraKeyspaceEvent(ctx, o, event.Deleted)
+	case *v1alpha1.CassandraRole:
+		err = h.handleCassandraRoleEvent(o, event.Deleted)
+	case *corev1.Pod:
+		err = h.handlePodEvent(ctx, o, event.Deleted)
+	case *corev1.Secret:
+		err = h.recheckReferencingClusters(o.GetNamespace(), o.GetName(), resource.SecretReferences)
+	case *corev1.ConfigMap:
+		err = h.recheckReferencingClusters(o.GetNamespace(), o.GetName(), resource.ConfigMapReferences)
 	}
 	return err
 }
 
-func (h *Handler) handleCassandraClusterEvent(o *v1alpha1.CassandraCluster, deleted bool) error {
+// selected reports whether the object belongs to a cluster matching the cluster selector, secrets and configmaps
+// are filtered when the clusters referencing them are queued
+func (h *Handler) selected(object interface{}) (bool, error) {
+	if h.clusterSelector.Empty() {
+		return true, nil
+	}
+
+	var namespace, cluster string
+	switch o := object.(type) {
+	case *v1alpha1.CassandraCluster:
+		return h.clusterSelector.Matches(labels.Set(o.GetLabels())), nil
+	case *v1alpha1.CassandraKeyspace:
+		namespace, cluster = o.GetNamespace(), o.Spec.Cluster
+	case *v1alpha1.CassandraRole:
+		namespace, cluster = o.GetNamespace(), o.Spec.Cluster
+	case *corev1.Pod:
+		namespace, cluster = o.GetNamespace(), o.GetLabels()["cluster"]
+	default:
+		return true, nil
+	}
+
+	cc := &v1alpha1.CassandraCluster{
+		TypeMeta: resource.GetCassandraClusterTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster,
+			Namespace: namespace,
+		},
+	}
+	err := h.k8sDriver.Get(cc)
+	if k8serrors.IsNotFound(err) {
+		// the objects of a deleted cluster are still cleaned up, the controllers handle the missing cluster
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if cc.ResourceVersion == "" {
+		return true, nil
+	}
+
+	return h.clusterSelector.Matches(labels.Set(cc.GetLabels())), nil
+}
+
+func (h *Handler) handleCassandraClusterEvent(ctx context.Context, o *v1alpha1.CassandraCluster, deleted bool) error {
 	if value, exists := o.Annotations["database.panth.io/cassandra-operator-version"]; exists && value != opVersion.Version {
 		return nil
 	}
-	// TODO: If there is already a pending update for a resource then
-	// emit an error and do nothing for that update, leaving the actual
-	// stored resource unchanged
-	// NOTE: we could track and store the controllers...
 	if !deleted {
-		// update cluster status based on reality
-		err := h.statusManager.Update(o)
-		if err != nil {
-			return err
+		start := time.Now()
+		err := h.syncCassandraCluster(ctx, o)
+		metrics.ObserveReconcile(o, start, err)
+		return err
+	}
+
+	metrics.DeleteCluster(o.GetNamespace(), o.GetName())
+	h.cqlSessions.Close(controller.ClusterHosts(o))
+	return nil
+}
+
+func (h *Handler) syncCassandraCluster(ctx context.Context, o *v1alpha1.CassandraCluster) error {
+	// update cluster status based on reality
+	err := h.statusManager.Update(ctx, o)
+	if err != nil {
+		return err
+	}
+	metrics.SetClusterStatus(o)
+
+	err = controller.New(o, h.k8sDriver, h.cqlSessions, h.secretProvider).Sync()
+	if err != nil {
+		return err
+	}
+
+	// cleanups run in the background, they are started and recorded after the cluster was reconciled
+	return h.cleanupManager.Converge(ctx, o)
+}
+
+// recheckReferencingClusters queues the clusters waiting in the initial phase on the changed secret or configmap
+// so they are validated again without waiting for the next resync
+func (h *Handler) recheckReferencingClusters(namespace, name string, references func(*v1alpha1.CassandraCluster) []resource.Reference) error {
+	clusters := &v1alpha1.CassandraClusterList{
+		TypeMeta: resource.GetCassandraClusterTypeMeta(),
+	}
+	err := h.k8sDriver.List(namespace, clusters)
+	if err != nil {
+		return err
+	}
+
+	for i := range clusters.Items {
+		cc := &clusters.Items[i]
+		if cc.Status.Phase != v1alpha1.ClusterPhaseInitial || !resource.References(references(cc), name) {
+			continue
+		}
+		if !h.clusterSelector.Matches(labels.Set(cc.GetLabels())) {
+			continue
 		}
 
-		return controller.New(o, h.k8sDriver).Sync()
+		// items of a list do not carry their type which the sdk needs to update the cluster
+		cc.TypeMeta = resource.GetCassandraClusterTypeMeta()
+		event := opsdk.Event{Object: cc}
+		key, id := eventKey(event)
+		h.queue.Add(key, id, event)
 	}
 
 	return nil
 }
 
-func (h *Handler) handlePodEvent(o *corev1.Pod, deleted bool) error {
+func (h *Handler) handleCassandraKeyspaceEvent(ctx context.Context, o *v1alpha1.CassandraKeyspace, deleted bool) error {
+	if value, exists := o.Annotations["database.panth.io/cassandra-operator-version"]; exists && value != opVersion.Version {
+		return nil
+	}
+
+	// deleting the resource leaves the keyspace and its data in place
+	if deleted {
+		return nil
+	}
+
+	return controller.NewKeyspaceController(h.k8sDriver, h.cqlSessions, h.nodetoolDriver).Sync(ctx, o)
+}
+
+func (h *Handler) handleCassandraRoleEvent(o *v1alpha1.CassandraRole, deleted bool) error {
+	if value, exists := o.Annotations["database.panth.io/cassandra-operator-version"]; exists && value != opVersion.Version {
+		return nil
+	}
+
+	roleCtrl := controller.NewRoleController(h.k8sDriver, h.cqlSessions)
+	if deleted {
+		return roleCtrl.Delete(o)
+	}
+
+	return roleCtrl.Sync(o)
+}
+
+func (h *Handler) handlePodEvent(ctx context.Context, o *corev1.Pod, deleted bool) error {
 	if o.Annotations["disable-pod-finalizer"] == "true" {
 		return nil
 	}
@@ -73,5 +312,5 @@ func (h *Handler) handlePodEvent(o *corev1.Pod, deleted bool) error {
 		return err
 	}
 
-	return podFinalizerCtrl.Process(o)
+	return podFinalizerCtrl.Process(ctx, o)
 }
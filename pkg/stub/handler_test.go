@@ -0,0 +1,229 @@
+package stub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/operator-framework/operator-sdk/pkg/sdk"
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/cql"
+	"github.com/pantheon-systems/cassandra-operator/pkg/backend/k8s"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+	"github.com/pantheon-systems/cassandra-operator/pkg/stub"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestHandler_ClusterSelector(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	listed := make(chan struct{}, 1)
+	clusterLabels := map[string]string{"other-cluster": "b", "managed-cluster": "a"}
+
+	client := &k8s.MockClient{
+		GetCallback: func(into sdk.Object, opts ...sdk.GetOption) error {
+			cc := into.(*v1alpha1.CassandraCluster)
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, fmt.Sprintf("get %s", cc.GetName()))
+			cc.ResourceVersion = "1"
+			cc.Labels = map[string]string{"team": clusterLabels[cc.GetName()]}
+			return nil
+		},
+		ListCallback: func(namespace string, into sdk.Object, opts ...sdk.ListOption) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, "list")
+			select {
+			case listed <- struct{}{}:
+			default:
+			}
+			// stops the reconcile of the cluster after it was selected
+			return errors.New("list failed")
+		},
+	}
+
+	selector, _ := labels.Parse("team=a")
+	handler := stub.NewHandler(client, nil, nil, nil, stub.WithClusterSelector(selector))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go handler.Run(ctx, 1)
+
+	handle := func(object sdk.Object) {
+		assert.NoError(t, handler.Handle(ctx, sdk.Event{Object: object}))
+	}
+	handle(&v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other-cluster", Labels: map[string]string{"team": "b"}},
+	})
+	handle(&v1alpha1.CassandraKeyspace{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "keyspace"},
+		Spec:       v1alpha1.KeyspaceSpec{Cluster: "other-cluster"},
+	})
+	handle(&v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "managed-cluster", Labels: map[string]string{"team": "a"}},
+	})
+
+	select {
+	case <-listed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("selected cluster was not reconciled")
+	}
+
+	// the events of the other cluster are dropped after the cluster of the keyspace was looked up
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"get other-cluster", "list"}, calls)
+}
+
+func TestHandler_CreatesCluster(t *testing.T) {
+	client := k8s.NewFakeClient(
+		&v1alpha1.CassandraCluster{
+			TypeMeta: resource.GetCassandraClusterTypeMeta(),
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-cluster",
+				Namespace:   "default",
+				Annotations: map[string]string{},
+			},
+			Spec: v1alpha1.ClusterSpec{
+				Size:          3,
+				ConfigMapName: "test-cluster-cassandra-config",
+				Node: &v1alpha1.NodePolicy{
+					Resources: &corev1.ResourceRequirements{},
+				},
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-cassandra-config", Namespace: "default"},
+			Data:       map[string]string{"cassandra.yaml": ""},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-prometheus-jvm-agent-config", Namespace: "default"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-cassandra-certs", Namespace: "default"},
+			Data:       map[string][]byte{"keystore.jks": {}, "truststore.jks": {}},
+		},
+	)
+	handler := stub.NewHandler(client, nil, &cql.MockConnector{}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go handler.Run(ctx, 1)
+
+	// the first event records the version of the operator, the next one validates and creates the cluster
+	for i := 0; i < 2; i++ {
+		cc := &v1alpha1.CassandraCluster{
+			TypeMeta:   resource.GetCassandraClusterTypeMeta(),
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+		}
+		assert.NoError(t, client.Get(cc))
+		assert.NoError(t, handler.Handle(ctx, sdk.Event{Object: cc}))
+
+		// the event object belongs to the handler now, the store is read into another copy
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			stored := &v1alpha1.CassandraCluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}}
+			assert.NoError(t, client.Get(stored))
+			if stored.Status.Phase == v1alpha1.ClusterPhaseInitial && (i == 0 || len(stored.Status.Conditions) > 0) {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("cluster was not reconciled: %+v", stored.Status)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(statefulSets.Items) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		assert.NoError(t, client.List("default", statefulSets))
+	}
+	if assert.Len(t, statefulSets.Items, 1) {
+		// the nodes of a new cluster are added one at a time
+		assert.Equal(t, int32(1), *statefulSets.Items[0].Spec.Replicas)
+		assert.Equal(t, "test-cluster", statefulSets.Items[0].OwnerReferences[0].Name)
+	}
+
+	services := &corev1.ServiceList{}
+	assert.NoError(t, client.List("default", services))
+	assert.NotEmpty(t, services.Items)
+}
+
+func TestHandler_DropsUnreferencedConfigMaps(t *testing.T) {
+	var mu sync.Mutex
+	var lists int
+	listed := make(chan struct{}, 1)
+	client := &k8s.MockClient{
+		ListCallback: func(namespace string, into sdk.Object, opts ...sdk.ListOption) error {
+			mu.Lock()
+			defer mu.Unlock()
+			lists++
+			select {
+			case listed <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+	}
+	handler := stub.NewHandler(client, nil, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go handler.Run(ctx, 1)
+
+	handle := func(object sdk.Object) {
+		assert.NoError(t, handler.Handle(ctx, sdk.Event{Object: object}))
+	}
+	// the cluster of another version of the operator is not reconciled, it still waits for its configmap
+	handle(&v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "test-cluster",
+			Annotations: map[string]string{"database.panth.io/cassandra-operator-version": "other"},
+		},
+		Spec:   v1alpha1.ClusterSpec{ConfigMapName: "test-cluster-cassandra-config"},
+		Status: v1alpha1.ClusterStatus{Phase: v1alpha1.ClusterPhaseInitial},
+	})
+	handle(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cassandra-operator-lock"}})
+	handle(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unrelated"}})
+	handle(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "test-cluster-cassandra-config"}})
+	handle(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster-cassandra-config"}})
+
+	select {
+	case <-listed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("referencing clusters were not listed")
+	}
+
+	// only the referenced configmap lists the clusters
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, 1, lists)
+	mu.Unlock()
+
+	// a cluster that left the initial phase no longer waits for the configmap
+	handle(&v1alpha1.CassandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "test-cluster",
+			Annotations: map[string]string{"database.panth.io/cassandra-operator-version": "other"},
+		},
+		Spec:   v1alpha1.ClusterSpec{ConfigMapName: "test-cluster-cassandra-config"},
+		Status: v1alpha1.ClusterStatus{Phase: v1alpha1.ClusterPhaseCreating},
+	})
+	handle(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster-cassandra-config"}})
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, 1, lists)
+	mu.Unlock()
+}
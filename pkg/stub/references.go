@@ -0,0 +1,76 @@
+package stub
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/apis/database/v1alpha1"
+	"github.com/pantheon-systems/cassandra-operator/pkg/resource"
+)
+
+// referenceIndex records the secrets and configmaps the clusters in the initial phase wait for, so the events of
+// the other secrets and configmaps are dropped without listing the clusters
+type referenceIndex struct {
+	mu sync.Mutex
+	// clusters are the clusters referencing a secret or configmap, by the kind, namespace and name of the object
+	clusters map[string]map[string]bool
+	// objects are the objects a cluster references, by the namespace and name of the cluster
+	objects map[string][]string
+}
+
+func newReferenceIndex() *referenceIndex {
+	return &referenceIndex{
+		clusters: map[string]map[string]bool{},
+		objects:  map[string][]string{},
+	}
+}
+
+// update replaces the references of the cluster, only the clusters waiting in the initial phase are indexed and
+// the references of a removed cluster are dropped
+func (i *referenceIndex) update(cc *v1alpha1.CassandraCluster, removed bool) {
+	cluster := fmt.Sprintf("%s/%s", cc.GetNamespace(), cc.GetName())
+
+	var objects []string
+	if !removed && cc.Status.Phase == v1alpha1.ClusterPhaseInitial {
+		for _, reference := range resource.SecretReferences(cc) {
+			objects = append(objects, referenceKey("Secret", cc.GetNamespace(), reference.Name))
+		}
+		for _, reference := range resource.ConfigMapReferences(cc) {
+			objects = append(objects, referenceKey("ConfigMap", cc.GetNamespace(), reference.Name))
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, object := range i.objects[cluster] {
+		delete(i.clusters[object], cluster)
+		if len(i.clusters[object]) == 0 {
+			delete(i.clusters, object)
+		}
+	}
+	delete(i.objects, cluster)
+
+	if len(objects) == 0 {
+		return
+	}
+	i.objects[cluster] = objects
+	for _, object := range objects {
+		if i.clusters[object] == nil {
+			i.clusters[object] = map[string]bool{}
+		}
+		i.clusters[object][cluster] = true
+	}
+}
+
+// referenced returns true if a cluster in the initial phase references the object of the kind
+func (i *referenceIndex) referenced(kind, namespace, name string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return len(i.clusters[referenceKey(kind, namespace, name)]) > 0
+}
+
+func referenceKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s %s/%s", kind, namespace, name)
+}
@@ -0,0 +1,205 @@
+package workqueue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// DefaultBaseDelay is the delay before the first retry of a failed item, it doubles with every failure
+	DefaultBaseDelay = time.Second
+	// DefaultMaxDelay caps the delay between retries
+	DefaultMaxDelay = 5 * time.Minute
+
+	queueName = "cassandra-operator"
+)
+
+// ProcessFunc processes an item that was added to the queue, the context is done when the queue stops
+type ProcessFunc func(ctx context.Context, item interface{}) error
+
+// Queue processes items grouped by a key, such as the cluster they belong to. Items of one key are processed
+// one at a time in the order they were added while items of different keys are processed in parallel. An item
+// replaces a pending item with the same id so only the latest state of an object is processed, and a failed
+// item is retried with the exponential backoff of its key unless a newer item with its id was added in the
+// meantime. The keys are queued on a client-go rate limiting queue, which serializes them and tracks the failures.
+type Queue struct {
+	process   ProcessFunc
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	keys      workqueue.RateLimitingInterface
+
+	mu sync.Mutex
+	// pending are the items waiting for a key, the entry of a key is removed when its items are taken
+	pending map[string]*pendingItems
+}
+
+// pendingItems are the items waiting for a key, ids keeps the order they were first added in
+type pendingItems struct {
+	ids   []string
+	items map[string]item
+}
+
+type item struct {
+	id    string
+	value interface{}
+}
+
+// QueueOption is a function that sets the configuration on the Queue
+type QueueOption func(*Queue)
+
+// WithBackoff sets the delay before the first retry and the maximum delay between retries
+func WithBackoff(base, max time.Duration) QueueOption {
+	return func(q *Queue) {
+		q.baseDelay = base
+		q.maxDelay = max
+	}
+}
+
+// New creates a new Queue that processes the items with the function
+func New(process ProcessFunc, opts ...QueueOption) *Queue {
+	q := &Queue{
+		process:   process,
+		baseDelay: DefaultBaseDelay,
+		maxDelay:  DefaultMaxDelay,
+		pending:   map[string]*pendingItems{},
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+	q.keys = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(q.baseDelay, q.maxDelay), queueName)
+
+	return q
+}
+
+// Add queues the item with the id for the key, a pending item with the same id is replaced
+func (q *Queue) Add(key, id string, value interface{}) {
+	if q.keys.ShuttingDown() {
+		return
+	}
+
+	q.mu.Lock()
+	q.pend(key, item{id: id, value: value})
+	q.mu.Unlock()
+
+	q.keys.Add(key)
+}
+
+func (q *Queue) pend(key string, it item) {
+	pending, ok := q.pending[key]
+	if !ok {
+		pending = &pendingItems{items: map[string]item{}}
+		q.pending[key] = pending
+	}
+
+	if _, ok := pending.items[it.id]; !ok {
+		pending.ids = append(pending.ids, it.id)
+	}
+	pending.items[it.id] = it
+}
+
+// Len returns the number of pending items
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n := 0
+	for _, pending := range q.pending {
+		n += len(pending.ids)
+	}
+	return n
+}
+
+// Run processes items with the number of workers until the context is done, pending items are dropped
+func (q *Queue) Run(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q.work(ctx) {
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	q.keys.ShutDown()
+	wg.Wait()
+
+	q.mu.Lock()
+	q.pending = map[string]*pendingItems{}
+	q.mu.Unlock()
+}
+
+// work processes the pending items of the next ready key, it returns false once the queue is shut down
+func (q *Queue) work(ctx context.Context) bool {
+	obj, shutdown := q.keys.Get()
+	if shutdown {
+		return false
+	}
+	key := obj.(string)
+	defer q.keys.Done(key)
+
+	var failed []item
+	for _, it := range q.take(key) {
+		err := q.process(ctx, it.value)
+		if err != nil {
+			logrus.Errorf("Processing %s failed %d times: %v", it.id, q.keys.NumRequeues(key)+1, err)
+			failed = append(failed, it)
+		}
+	}
+
+	if len(failed) == 0 {
+		q.keys.Forget(key)
+		return true
+	}
+
+	q.retry(key, failed)
+	q.keys.AddRateLimited(key)
+	return true
+}
+
+// take removes the pending items of the key in the order they were added
+func (q *Queue) take(key string) []item {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending, ok := q.pending[key]
+	if !ok {
+		return nil
+	}
+	delete(q.pending, key)
+
+	items := make([]item, 0, len(pending.ids))
+	for _, id := range pending.ids {
+		items = append(items, pending.items[id])
+	}
+	return items
+}
+
+// retry puts the failed items back ahead of the items added in the meantime, unless a newer item with their id
+// replaced them
+func (q *Queue) retry(key string, failed []item) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	added := q.pending[key]
+	delete(q.pending, key)
+	for _, it := range failed {
+		if added != nil {
+			if _, ok := added.items[it.id]; ok {
+				continue
+			}
+		}
+		q.pend(key, it)
+	}
+	if added != nil {
+		for _, id := range added.ids {
+			q.pend(key, added.items[id])
+		}
+	}
+}
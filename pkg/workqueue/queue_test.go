@@ -0,0 +1,174 @@
+package workqueue_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pantheon-systems/cassandra-operator/pkg/workqueue"
+	"github.com/stretchr/testify/assert"
+)
+
+type value struct {
+	key string
+	n   int
+}
+
+// recorder processes values and records the order and concurrency they were processed with
+type recorder struct {
+	mu        sync.Mutex
+	processed []value
+	running   map[string]int
+	maxPerKey int
+	maxTotal  int
+	total     int
+	delay     time.Duration
+	fail      func(value) error
+}
+
+func newRecorder(delay time.Duration) *recorder {
+	return &recorder{running: map[string]int{}, delay: delay}
+}
+
+func (r *recorder) process(ctx context.Context, item interface{}) error {
+	v := item.(value)
+
+	r.mu.Lock()
+	r.running[v.key]++
+	r.total++
+	if r.running[v.key] > r.maxPerKey {
+		r.maxPerKey = r.running[v.key]
+	}
+	if r.total > r.maxTotal {
+		r.maxTotal = r.total
+	}
+	r.mu.Unlock()
+
+	time.Sleep(r.delay)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.running[v.key]--
+	r.total--
+	r.processed = append(r.processed, v)
+
+	if r.fail != nil {
+		return r.fail(v)
+	}
+	return nil
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.processed)
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the queue")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestQueue_SerializesKeysAndParallelizesClusters(t *testing.T) {
+	r := newRecorder(5 * time.Millisecond)
+	q := workqueue.New(r.process)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx, 4)
+
+	for i := 0; i < 5; i++ {
+		for _, key := range []string{"ns/cluster-a", "ns/cluster-b", "ns/cluster-c"} {
+			q.Add(key, fmt.Sprintf("%s-%d", key, i), value{key: key, n: i})
+		}
+	}
+
+	waitFor(t, func() bool { return r.count() == 15 })
+	assert.Equal(t, 1, r.maxPerKey)
+	assert.True(t, r.maxTotal > 1)
+
+	// the items of a key are processed in the order they were added
+	last := map[string]int{}
+	for _, v := range r.processed {
+		if n, ok := last[v.key]; ok {
+			assert.True(t, v.n > n)
+		}
+		last[v.key] = v.n
+	}
+}
+
+func TestQueue_ReplacesPendingItems(t *testing.T) {
+	r := newRecorder(20 * time.Millisecond)
+	q := workqueue.New(r.process)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx, 2)
+
+	q.Add("ns/cluster-a", "busy", value{key: "ns/cluster-a", n: 0})
+	waitFor(t, func() bool { return q.Len() == 0 })
+
+	// added while the key is being processed, only the latest of the object is processed afterwards
+	for i := 1; i <= 3; i++ {
+		q.Add("ns/cluster-a", "object", value{key: "ns/cluster-a", n: i})
+	}
+	assert.Equal(t, 1, q.Len())
+
+	waitFor(t, func() bool { return r.count() == 2 })
+	assert.Equal(t, []value{{key: "ns/cluster-a", n: 0}, {key: "ns/cluster-a", n: 3}}, r.processed)
+}
+
+func TestQueue_RetriesWithBackoff(t *testing.T) {
+	r := newRecorder(0)
+	failures := 0
+	r.fail = func(v value) error {
+		if failures < 2 {
+			failures++
+			return errors.New("reconcile failed")
+		}
+		return nil
+	}
+	q := workqueue.New(r.process, workqueue.WithBackoff(10*time.Millisecond, time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx, 1)
+
+	start := time.Now()
+	q.Add("ns/cluster-a", "object", value{key: "ns/cluster-a", n: 1})
+
+	waitFor(t, func() bool { return r.count() == 3 })
+	// the retries wait 10ms and 20ms
+	assert.True(t, time.Since(start) >= 30*time.Millisecond)
+}
+
+func TestQueue_DropsRetryOfReplacedItem(t *testing.T) {
+	r := newRecorder(0)
+	r.fail = func(v value) error {
+		if v.n == 1 {
+			return errors.New("reconcile failed")
+		}
+		return nil
+	}
+	q := workqueue.New(r.process, workqueue.WithBackoff(50*time.Millisecond, time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx, 1)
+
+	q.Add("ns/cluster-a", "object", value{key: "ns/cluster-a", n: 1})
+	waitFor(t, func() bool { return r.count() == 1 })
+	q.Add("ns/cluster-a", "object", value{key: "ns/cluster-a", n: 2})
+	waitFor(t, func() bool { return r.count() == 2 })
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, []value{{key: "ns/cluster-a", n: 1}, {key: "ns/cluster-a", n: 2}}, r.processed)
+}